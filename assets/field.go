@@ -2,10 +2,28 @@ package assets
 
 import (
 	"fmt"
+	"regexp"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/nyaruka/gocommon/uuids"
+	"github.com/nyaruka/goflow/utils"
 )
 
+func init() {
+	utils.RegisterValidatorTag("regex", func(fl validator.FieldLevel) bool {
+		_, err := regexp.Compile(fl.Field().String())
+		return err == nil
+	}, func(validator.FieldError) string {
+		return "is not a valid regular expression"
+	})
+	utils.RegisterValidatorAlias("field_choice", "required,excludes=0x2C", func(validator.FieldError) string {
+		return "must be a non-empty choice without commas"
+	})
+	utils.RegisterValidatorAlias("field_normalizer", "eq=phone|eq=title_case|eq=upper_case|eq=lower_case", func(validator.FieldError) string {
+		return "is not a valid field normalizer"
+	})
+}
+
 // FieldUUID is the UUID of a field
 type FieldUUID uuids.UUID
 
@@ -20,15 +38,45 @@ const (
 	FieldTypeWard     FieldType = "ward"
 	FieldTypeDistrict FieldType = "district"
 	FieldTypeState    FieldType = "state"
+
+	FieldTypeBoolean     FieldType = "boolean"
+	FieldTypeChoice      FieldType = "choice"
+	FieldTypeMultiChoice FieldType = "multi_choice"
+	FieldTypeJSON        FieldType = "json"
+)
+
+// FieldNormalizer is a transformation applied to values of a field before they are validated and saved
+type FieldNormalizer string
+
+// field value normalizers
+const (
+	FieldNormalizerPhone     FieldNormalizer = "phone"      // E.164 phone number using the default country
+	FieldNormalizerTitleCase FieldNormalizer = "title_case" // e.g. "bob smith" becomes "Bob Smith"
+	FieldNormalizerUpperCase FieldNormalizer = "upper_case"
+	FieldNormalizerLowerCase FieldNormalizer = "lower_case"
 )
 
-// Field is a custom contact property.
+// FieldRules are normalizations and constraints applied to values of a field. Min and max are numbers for number
+// fields and dates (YYYY-MM-DD) for datetime fields. Message optionally replaces the error text when a value breaks a rule.
+type FieldRules struct {
+	Normalize []FieldNormalizer `json:"normalize,omitempty" validate:"omitempty,dive,field_normalizer"`
+	MaxLength int               `json:"max_length,omitempty" validate:"omitempty,min=1"`
+	Regex     string            `json:"regex,omitempty" validate:"omitempty,regex"`
+	Min       string            `json:"min,omitempty"`
+	Max       string            `json:"max,omitempty"`
+	Message   string            `json:"message,omitempty" validate:"max=640"`
+}
+
+// Field is a custom contact property. Fields of type `choice` or `multi_choice` also have a list of the
+// values which are allowed (see ChoiceField), and any field can have rules which values are normalized and
+// validated with (see RulesField).
 //
 //	{
 //	  "uuid": "d66a7823-eada-40e5-9a3a-57239d4690bf",
-//	  "key": "gender",
-//	  "name": "Gender",
-//	  "type": "text"
+//	  "key": "national_id",
+//	  "name": "National ID",
+//	  "type": "text",
+//	  "rules": {"normalize": ["upper_case"], "max_length": 8}
 //	}
 //
 // @asset field
@@ -39,6 +87,37 @@ type Field interface {
 	Type() FieldType
 }
 
+// ChoiceField is a field which only allows certain values. Choices can't contain commas as they're used to separate
+// the selected choices of multi-choice values.
+type ChoiceField interface {
+	Field
+
+	Choices() []string
+}
+
+// FieldChoices returns the allowed values of the given field if it's a choice field
+func FieldChoices(f Field) []string {
+	if c, ok := f.(ChoiceField); ok {
+		return c.Choices()
+	}
+	return nil
+}
+
+// RulesField is a field whose values are normalized and validated with rules
+type RulesField interface {
+	Field
+
+	Rules() *FieldRules
+}
+
+// FieldRulesOf returns the rules of the given field if it has any
+func FieldRulesOf(f Field) *FieldRules {
+	if r, ok := f.(RulesField); ok {
+		return r.Rules()
+	}
+	return nil
+}
+
 // FieldReference is a reference to a field
 type FieldReference struct {
 	Key  string `json:"key" validate:"required,max=64"`
@@ -0,0 +1,51 @@
+package assets
+
+import "fmt"
+
+// Function is a named function which can be called in expressions like a builtin function. Its name can't be the same
+// as a builtin function or a top-level of the context, e.g. `contact`. Its body is an expression which can only
+// reference the function's parameters, builtin functions and other functions.
+//
+//	{
+//	  "name": "national_id",
+//	  "params": ["value"],
+//	  "body": "upper(replace(trim(value), \"-\", \"\"))"
+//	}
+//
+// @asset function
+type Function interface {
+	Name() string
+	Params() []string
+	Body() string
+}
+
+// FunctionReference is a reference to a function
+type FunctionReference struct {
+	Name string `json:"name" validate:"required,max=64"`
+}
+
+// NewFunctionReference creates a new function reference with the given name
+func NewFunctionReference(name string) *FunctionReference {
+	return &FunctionReference{Name: name}
+}
+
+// Type returns the name of the asset type
+func (r *FunctionReference) Type() string {
+	return "function"
+}
+
+// Identity returns the unique identity of the asset
+func (r *FunctionReference) Identity() string {
+	return r.Name
+}
+
+// Variable returns whether this a variable (vs concrete) reference
+func (r *FunctionReference) Variable() bool {
+	return false
+}
+
+func (r *FunctionReference) String() string {
+	return fmt.Sprintf("%s[name=%s]", r.Type(), r.Identity())
+}
+
+var _ Reference = (*FunctionReference)(nil)
@@ -2,12 +2,14 @@ package assets
 
 import "fmt"
 
-// Global is a named constant.
+// Global is a named constant. A global can be secret (see SecretGlobal), in which case it can only be used in webhook
+// URLs and headers, and its value is masked anywhere else it appears.
 //
 //	{
 //	  "key": "organization_name",
 //	  "name": "Organization Name",
-//	  "value": "U-Report"
+//	  "value": "U-Report",
+//	  "secret": false
 //	}
 //
 // @asset global
@@ -17,6 +19,21 @@ type Global interface {
 	Value() string
 }
 
+// SecretGlobal is a global whose value may be secret
+type SecretGlobal interface {
+	Global
+
+	Secret() bool
+}
+
+// IsSecretGlobal returns whether the given global is secret, which is never the case if it isn't a SecretGlobal
+func IsSecretGlobal(g Global) bool {
+	if s, ok := g.(SecretGlobal); ok {
+		return s.Secret()
+	}
+	return false
+}
+
 // GlobalReference is a reference to a global
 type GlobalReference struct {
 	Key  string `json:"key" validate:"required,max=64"`
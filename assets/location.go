@@ -1,8 +1,13 @@
 package assets
 
-import "github.com/nyaruka/goflow/envs"
+import (
+	"github.com/nyaruka/goflow/envs"
+	"github.com/nyaruka/goflow/utils"
+)
 
-// LocationHierarchy is a searchable hierarchy of locations.
+// LocationHierarchy is a searchable hierarchy of locations. Locations can optionally have a `centroid` as a
+// `[longitude, latitude]` position, a `bbox` as `[west, south, east, north]` and a `boundary` as a GeoJSON polygon or
+// multipolygon. Locations with boundaries can be found by shared locations.
 //
 //	{
 //	  "name": "Rwanda",
@@ -14,6 +19,12 @@ import "github.com/nyaruka/goflow/envs"
 //	      "children": [
 //	        {
 //	          "name": "Gasabo",
+//	          "centroid": [30.15, -1.925],
+//	          "bbox": [30.05, -2.0, 30.25, -1.85],
+//	          "boundary": {
+//	            "type": "Polygon",
+//	            "coordinates": [[[30.05, -2.0], [30.25, -2.0], [30.25, -1.85], [30.05, -1.85], [30.05, -2.0]]]
+//	          },
 //	          "children": [
 //	            {
 //	              "id": "575743222",
@@ -42,3 +53,10 @@ type LocationHierarchy interface {
 	FindByPath(path envs.LocationPath) *envs.Location
 	FindByName(env envs.Environment, name string, level envs.LocationLevel, parent *envs.Location) []*envs.Location
 }
+
+// PointLocationHierarchy is a location hierarchy which can also be searched by points inside location boundaries
+type PointLocationHierarchy interface {
+	LocationHierarchy
+
+	FindByPoint(point utils.GeoPoint, level envs.LocationLevel, parent *envs.Location) []*envs.Location
+}
@@ -1,15 +1,37 @@
 package static
 
 import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
 	"github.com/nyaruka/goflow/assets"
+	"github.com/nyaruka/goflow/utils"
+	"github.com/shopspring/decimal"
 )
 
+func init() {
+	utils.RegisterStructValidator(FieldValidation, Field{})
+
+	// limits depend on the field type so this tag is only reported by the struct validator
+	utils.RegisterValidatorTag("field_limit", func(validator.FieldLevel) bool { return true }, func(e validator.FieldError) string {
+		switch assets.FieldType(e.Param()) {
+		case assets.FieldTypeNumber:
+			return "is not a valid number"
+		case assets.FieldTypeDatetime:
+			return "is not a valid date in the format YYYY-MM-DD"
+		}
+		return "is only allowed for number and datetime fields"
+	})
+}
+
 // Field is a JSON serializable implementation of a field asset
 type Field struct {
-	UUID_ assets.FieldUUID `json:"uuid"`
-	Key_  string           `json:"key" validate:"required"`
-	Name_ string           `json:"name"`
-	Type_ assets.FieldType `json:"type" validate:"required"`
+	UUID_    assets.FieldUUID   `json:"uuid"`
+	Key_     string             `json:"key" validate:"required"`
+	Name_    string             `json:"name"`
+	Type_    assets.FieldType   `json:"type" validate:"required"`
+	Choices_ []string           `json:"choices,omitempty" validate:"omitempty,dive,field_choice"`
+	Rules_   *assets.FieldRules `json:"rules,omitempty"`
 }
 
 // NewField creates a new field from the passed in key, name and type
@@ -17,6 +39,16 @@ func NewField(uuid assets.FieldUUID, key string, name string, valueType assets.F
 	return &Field{UUID_: uuid, Key_: key, Name_: name, Type_: valueType}
 }
 
+// NewChoiceField creates a new field which only allows the given choices as values
+func NewChoiceField(uuid assets.FieldUUID, key string, name string, valueType assets.FieldType, choices []string) assets.Field {
+	return &Field{UUID_: uuid, Key_: key, Name_: name, Type_: valueType, Choices_: choices}
+}
+
+// NewFieldWithRules creates a new field whose values are normalized and validated with the given rules
+func NewFieldWithRules(uuid assets.FieldUUID, key string, name string, valueType assets.FieldType, rules *assets.FieldRules) assets.Field {
+	return &Field{UUID_: uuid, Key_: key, Name_: name, Type_: valueType, Rules_: rules}
+}
+
 // UUID returns the UUID of this field
 func (f *Field) UUID() assets.FieldUUID { return f.UUID_ }
 
@@ -28,3 +60,49 @@ func (f *Field) Name() string { return f.Name_ }
 
 // Type returns the value type of the field
 func (f *Field) Type() assets.FieldType { return f.Type_ }
+
+// Choices returns the allowed values of the field if it's a choice field
+func (f *Field) Choices() []string { return f.Choices_ }
+
+// Rules returns the rules that values of this field must follow, if any
+func (f *Field) Rules() *assets.FieldRules { return f.Rules_ }
+
+var _ assets.ChoiceField = (*Field)(nil)
+var _ assets.RulesField = (*Field)(nil)
+
+//------------------------------------------------------------------------------------------
+// Validation
+//------------------------------------------------------------------------------------------
+
+// FieldValidation validates that the min and max rules of the given field are valid values for its type
+func FieldValidation(sl validator.StructLevel) {
+	f := sl.Current().Interface().(Field)
+	if f.Rules_ == nil {
+		return
+	}
+
+	if !isValidFieldLimit(f.Type_, f.Rules_.Min) {
+		sl.ReportError(f.Rules_.Min, "rules.min", "Min", "field_limit", string(f.Type_))
+	}
+	if !isValidFieldLimit(f.Type_, f.Rules_.Max) {
+		sl.ReportError(f.Rules_.Max, "rules.max", "Max", "field_limit", string(f.Type_))
+	}
+}
+
+// checks that the given min or max rule is valid for fields of the given type, which for types without limits
+// means that it isn't set
+func isValidFieldLimit(typ assets.FieldType, limit string) bool {
+	if limit == "" {
+		return true
+	}
+
+	switch typ {
+	case assets.FieldTypeNumber:
+		_, err := decimal.NewFromString(limit)
+		return err == nil
+	case assets.FieldTypeDatetime:
+		_, err := time.Parse("2006-01-02", limit)
+		return err == nil
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/nyaruka/goflow/assets"
 	"github.com/nyaruka/goflow/assets/static"
+	"github.com/nyaruka/goflow/utils"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -16,3 +17,52 @@ func TestField(t *testing.T) {
 	assert.Equal(t, "Age", field.Name())
 	assert.Equal(t, assets.FieldTypeNumber, field.Type())
 }
+
+func TestChoiceField(t *testing.T) {
+	field := static.NewChoiceField(assets.FieldUUID("5d8e37a7-8c9b-4ef8-a0e5-8bb5f1b0b3c8"), "tags", "Tags", assets.FieldTypeMultiChoice, []string{"VIP", "Gold"})
+	assert.Equal(t, "tags", field.Key())
+	assert.Equal(t, assets.FieldTypeMultiChoice, field.Type())
+	assert.Equal(t, []string{"VIP", "Gold"}, assets.FieldChoices(field))
+
+	field = static.NewField(assets.FieldUUID("ffffffff-9b24-92e1-ffff-ffffb207cdb4"), "age", "Age", assets.FieldTypeNumber)
+	assert.Nil(t, assets.FieldChoices(field))
+
+	// choices can't contain the separator used for multiple choices
+	f := &static.Field{}
+	err := utils.UnmarshalAndValidate([]byte(`{"uuid": "5d8e37a7-8c9b-4ef8-a0e5-8bb5f1b0b3c8", "key": "tags", "name": "Tags", "type": "multi_choice", "choices": ["VIP", "Red, White", ""]}`), f)
+	assert.EqualError(t, err, "field 'choices[1]' must be a non-empty choice without commas, field 'choices[2]' must be a non-empty choice without commas")
+}
+
+func TestFieldWithRules(t *testing.T) {
+	field := static.NewFieldWithRules(assets.FieldUUID("0e5aa7d0-6e2c-4cc8-8f2d-6e2f3b0c2a57"), "national_id", "National ID", assets.FieldTypeText, &assets.FieldRules{MaxLength: 8})
+	assert.Equal(t, 8, assets.FieldRulesOf(field).MaxLength)
+
+	field = static.NewField(assets.FieldUUID("ffffffff-9b24-92e1-ffff-ffffb207cdb4"), "age", "Age", assets.FieldTypeNumber)
+	assert.Nil(t, assets.FieldRulesOf(field))
+
+	f := &static.Field{}
+	err := utils.UnmarshalAndValidate([]byte(`{"uuid": "0e5aa7d0-6e2c-4cc8-8f2d-6e2f3b0c2a57", "key": "national_id", "name": "National ID", "type": "text", "rules": {"normalize": ["upper_case"], "regex": "^[A-Z]{2}\\d{6}$"}}`), f)
+	assert.NoError(t, err)
+	assert.Equal(t, []assets.FieldNormalizer{assets.FieldNormalizerUpperCase}, f.Rules().Normalize)
+	assert.Equal(t, `^[A-Z]{2}\d{6}$`, f.Rules().Regex)
+
+	err = utils.UnmarshalAndValidate([]byte(`{"uuid": "0e5aa7d0-6e2c-4cc8-8f2d-6e2f3b0c2a57", "key": "national_id", "name": "National ID", "type": "text", "rules": {"normalize": ["reverse"], "regex": "[A-Z"}}`), f)
+	assert.EqualError(t, err, "field 'rules.normalize[0]' is not a valid field normalizer, field 'rules.regex' is not a valid regular expression")
+}
+
+func TestFieldRuleLimits(t *testing.T) {
+	err := utils.UnmarshalAndValidate([]byte(`{"uuid": "f1b5aea6-6586-41c7-9020-1a6326cc6565", "key": "age", "name": "Age", "type": "number", "rules": {"min": "0", "max": "120.5"}}`), &static.Field{})
+	assert.NoError(t, err)
+
+	err = utils.UnmarshalAndValidate([]byte(`{"uuid": "3810a485-3fda-4011-a589-7320c0b8dbef", "key": "dob", "name": "DOB", "type": "datetime", "rules": {"min": "1900-01-01", "max": "2020-12-31"}}`), &static.Field{})
+	assert.NoError(t, err)
+
+	err = utils.UnmarshalAndValidate([]byte(`{"uuid": "f1b5aea6-6586-41c7-9020-1a6326cc6565", "key": "age", "name": "Age", "type": "number", "rules": {"min": "zero", "max": "120"}}`), &static.Field{})
+	assert.EqualError(t, err, "field 'rules.min' is not a valid number")
+
+	err = utils.UnmarshalAndValidate([]byte(`{"uuid": "3810a485-3fda-4011-a589-7320c0b8dbef", "key": "dob", "name": "DOB", "type": "datetime", "rules": {"min": "1900-01-01", "max": "31/12/2020"}}`), &static.Field{})
+	assert.EqualError(t, err, "field 'rules.max' is not a valid date in the format YYYY-MM-DD")
+
+	err = utils.UnmarshalAndValidate([]byte(`{"uuid": "0e5aa7d0-6e2c-4cc8-8f2d-6e2f3b0c2a57", "key": "national_id", "name": "National ID", "type": "text", "rules": {"min": "A"}}`), &static.Field{})
+	assert.EqualError(t, err, "field 'rules.min' is only allowed for number and datetime fields")
+}
@@ -0,0 +1,30 @@
+package static
+
+import (
+	"github.com/nyaruka/goflow/assets"
+)
+
+// Function is a JSON serializable implementation of a function asset
+type Function struct {
+	Name_   string   `json:"name"   validate:"required"`
+	Params_ []string `json:"params"`
+	Body_   string   `json:"body"   validate:"required"`
+}
+
+// NewFunction creates a new function
+func NewFunction(name string, params []string, body string) assets.Function {
+	return &Function{
+		Name_:   name,
+		Params_: params,
+		Body_:   body,
+	}
+}
+
+// Name returns the name of this function
+func (f *Function) Name() string { return f.Name_ }
+
+// Params returns the parameter names of this function
+func (f *Function) Params() []string { return f.Params_ }
+
+// Body returns the expression body of this function
+func (f *Function) Body() string { return f.Body_ }
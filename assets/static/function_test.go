@@ -0,0 +1,15 @@
+package static_test
+
+import (
+	"testing"
+
+	"github.com/nyaruka/goflow/assets/static"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunction(t *testing.T) {
+	fn := static.NewFunction("national_id", []string{"value"}, `upper(value)`)
+	assert.Equal(t, "national_id", fn.Name())
+	assert.Equal(t, []string{"value"}, fn.Params())
+	assert.Equal(t, `upper(value)`, fn.Body())
+}
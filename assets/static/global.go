@@ -6,9 +6,10 @@ import (
 
 // Global is a JSON serializable implementation of a global asset
 type Global struct {
-	Key_   string `json:"key" validate:"required"`
-	Name_  string `json:"name"`
-	Value_ string `json:"value"`
+	Key_    string `json:"key" validate:"required"`
+	Name_   string `json:"name"`
+	Value_  string `json:"value"`
+	Secret_ bool   `json:"secret,omitempty"`
 }
 
 // NewGlobal creates a new global
@@ -20,6 +21,16 @@ func NewGlobal(key, name, value string) assets.Global {
 	}
 }
 
+// NewSecretGlobal creates a new global whose value is secret
+func NewSecretGlobal(key, name, value string) assets.Global {
+	return &Global{
+		Key_:    key,
+		Name_:   name,
+		Value_:  value,
+		Secret_: true,
+	}
+}
+
 // Key returns the key of this global
 func (g *Global) Key() string { return g.Key_ }
 
@@ -28,3 +39,8 @@ func (g *Global) Name() string { return g.Name_ }
 
 // Value returns the type of this global
 func (g *Global) Value() string { return g.Value_ }
+
+// Secret returns whether the value of this global is secret
+func (g *Global) Secret() bool { return g.Secret_ }
+
+var _ assets.SecretGlobal = (*Global)(nil)
@@ -3,6 +3,7 @@ package static_test
 import (
 	"testing"
 
+	"github.com/nyaruka/goflow/assets"
 	"github.com/nyaruka/goflow/assets/static"
 	"github.com/stretchr/testify/assert"
 )
@@ -12,4 +13,8 @@ func TestGlobal(t *testing.T) {
 	assert.Equal(t, "org_name", global.Key())
 	assert.Equal(t, "Org Name", global.Name())
 	assert.Equal(t, "U-Report", global.Value())
+	assert.False(t, assets.IsSecretGlobal(global))
+
+	global = static.NewSecretGlobal("api_token", "API Token", "sesame")
+	assert.True(t, assets.IsSecretGlobal(global))
 }
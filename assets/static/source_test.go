@@ -104,6 +104,10 @@ func TestSource(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Empty", flow.Name())
 
+	functions, err := src.Functions()
+	assert.NoError(t, err)
+	assert.Len(t, functions, 0)
+
 	globals, err := src.Globals()
 	assert.NoError(t, err)
 	assert.Len(t, globals, 0)
@@ -43,13 +43,13 @@ func TestGenerateDocs(t *testing.T) {
 	context := completion["context"].(map[string]any)
 	functions := completion["functions"].([]any)
 
-	assert.Equal(t, 90, len(functions))
+	assert.Equal(t, 91, len(functions))
 
 	types := context["types"].([]any)
-	assert.Equal(t, 21, len(types))
+	assert.Equal(t, 24, len(types))
 
 	root := context["root"].([]any)
-	assert.Equal(t, 15, len(root))
+	assert.Equal(t, 17, len(root))
 }
 
 func readJSONOutput(t *testing.T, file ...string) any {
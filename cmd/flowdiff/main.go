@@ -0,0 +1,119 @@
+package main
+
+// go install github.com/nyaruka/goflow/cmd/flowdiff
+// flowdiff old_flow.json new_flow.json
+// flowdiff -merge base_flow.json our_flow.json their_flow.json > merged_flow.json
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/definition"
+)
+
+const usage = `usage: flowdiff [flags] <from.json> <to.json>
+       flowdiff -merge [flags] <base.json> <ours.json> <theirs.json>`
+
+func main() {
+	var merge, pretty bool
+
+	flags := flag.NewFlagSet("", flag.ExitOnError)
+	flags.BoolVar(&merge, "merge", false, "Merge two revisions of a flow with a common base revision")
+	flags.BoolVar(&pretty, "pretty", false, "Pretty format output")
+	flags.Parse(os.Args[1:])
+	args := flags.Args()
+
+	if (!merge && len(args) != 2) || (merge && len(args) != 3) {
+		fmt.Println(usage)
+		flags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	if merge {
+		output, conflicts, err := Merge(args[0], args[1], args[2], pretty)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(output))
+
+		// report conflicts on stderr so that the merged flow can still be piped into a file
+		if len(conflicts) > 0 {
+			fmt.Fprintln(os.Stderr, string(marshal(conflicts, true)))
+			os.Exit(2)
+		}
+	} else {
+		output, err := Diff(args[0], args[1], pretty)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(output))
+	}
+}
+
+// Diff reads two revisions of a flow and returns the changes between them as JSON
+func Diff(fromPath, toPath string, pretty bool) ([]byte, error) {
+	from, err := readFlow(fromPath)
+	if err != nil {
+		return nil, err
+	}
+	to, err := readFlow(toPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshal(definition.Diff(from, to), pretty), nil
+}
+
+// Merge reads three revisions of a flow and returns the merged flow as JSON, and any conflicts
+func Merge(basePath, oursPath, theirsPath string, pretty bool) ([]byte, []*definition.Conflict, error) {
+	base, err := readFlow(basePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	ours, err := readFlow(oursPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	theirs, err := readFlow(theirsPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged, conflicts, err := definition.Merge(base, ours, theirs)
+	if err != nil {
+		return nil, conflicts, err
+	}
+
+	return marshal(merged, pretty), conflicts, nil
+}
+
+func readFlow(path string) (flows.Flow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %w", path, err)
+	}
+
+	flow, err := definition.ReadFlow(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading flow from %s: %w", path, err)
+	}
+	return flow, nil
+}
+
+func marshal(v any, pretty bool) []byte {
+	if pretty {
+		marshaled, err := jsonx.MarshalPretty(v)
+		if err != nil {
+			panic(err)
+		}
+		return marshaled
+	}
+	return jsonx.MustMarshal(v)
+}
@@ -0,0 +1,71 @@
+package main_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	main "github.com/nyaruka/goflow/cmd/flowdiff"
+	"github.com/nyaruka/goflow/flows/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const basePath = "../../flows/definition/testdata/diff_base.json"
+
+// writes a revision of the base flow with the given old/new replacement pairs applied
+func writeRevision(t *testing.T, name string, replacements ...string) string {
+	data, err := os.ReadFile(basePath)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), name)
+	err = os.WriteFile(path, []byte(strings.NewReplacer(replacements...).Replace(string(data))), 0644)
+	require.NoError(t, err)
+	return path
+}
+
+func TestDiff(t *testing.T) {
+	revised := writeRevision(t, "revised.json", `"What is your favorite color?"`, `"What's your favorite color?"`)
+
+	output, err := main.Diff(basePath, revised, false)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{
+			"type": "changed",
+			"item": "action",
+			"uuid": "a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b01",
+			"node_uuid": "0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c01",
+			"before": {"uuid": "a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b01", "type": "send_msg", "text": "What is your favorite color?"},
+			"after": {"uuid": "a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b01", "type": "send_msg", "text": "What's your favorite color?"}
+		}
+	]`, string(output))
+
+	_, err = main.Diff(basePath, "missing.json", false)
+	assert.ErrorContains(t, err, "error reading file missing.json")
+
+	invalid := writeRevision(t, "invalid.json", `"type": "messaging"`, `"type": "spam"`)
+	_, err = main.Diff(basePath, invalid, false)
+	assert.ErrorContains(t, err, "error reading flow from "+invalid)
+}
+
+func TestMerge(t *testing.T) {
+	ours := writeRevision(t, "ours.json", `"What is your favorite color?"`, `"What's your favorite color?"`)
+	theirs := writeRevision(t, "theirs.json", `"name": "Favorites"`, `"name": "Favorite Colors"`)
+
+	output, conflicts, err := main.Merge(basePath, ours, theirs, true)
+	require.NoError(t, err)
+	assert.Len(t, conflicts, 0)
+
+	merged, err := definition.ReadFlow(output, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Favorite Colors", merged.Name())
+	assert.Contains(t, string(output), `"text": "What's your favorite color?"`)
+
+	theirs = writeRevision(t, "theirs.json", `"What is your favorite color?"`, `"Favorite color?"`)
+
+	_, conflicts, err = main.Merge(basePath, ours, theirs, false)
+	require.NoError(t, err)
+	assert.Len(t, conflicts, 1)
+	assert.Equal(t, "text", conflicts[0].Property)
+}
@@ -0,0 +1,128 @@
+package main
+
+// go install github.com/nyaruka/goflow/cmd/flowgraph
+// flowgraph -format mermaid flows.json > flows.mmd
+// flowgraph -segments segments.json flows.json 76f0a02f-3b75-4b86-9064-e9195e1b3a02 | dot -Tsvg > flow.svg
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/goflow/assets"
+	"github.com/nyaruka/goflow/assets/static"
+	"github.com/nyaruka/goflow/envs"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/engine"
+	"github.com/nyaruka/goflow/flows/inspect"
+)
+
+const usage = `usage: flowgraph [flags] <assets.json> [flow_uuid]...
+
+Renders the given flows, or all flows in the assets if none are given. Segments to annotate exits with path counts
+should be a JSON array of segments as recorded in sprints.`
+
+func main() {
+	var format, segmentsPath string
+
+	flags := flag.NewFlagSet("", flag.ExitOnError)
+	flags.StringVar(&format, "format", "dot", "Output format: dot or mermaid")
+	flags.StringVar(&segmentsPath, "segments", "", "Optional path of a JSON file of segments")
+	flags.Parse(os.Args[1:])
+	args := flags.Args()
+
+	if len(args) < 1 || (format != "dot" && format != "mermaid") {
+		fmt.Println(usage)
+		flags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	flowUUIDs := make([]assets.FlowUUID, len(args)-1)
+	for i, a := range args[1:] {
+		flowUUIDs[i] = assets.FlowUUID(a)
+	}
+
+	output, err := Graph(args[0], flowUUIDs, segmentsPath, format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Print(output)
+}
+
+// Graph loads flows from the given assets file and renders them in the given format
+func Graph(assetsPath string, flowUUIDs []assets.FlowUUID, segmentsPath string, format string) (string, error) {
+	assetsJSON, err := os.ReadFile(assetsPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading assets file %s: %w", assetsPath, err)
+	}
+
+	source, err := static.NewSource(assetsJSON)
+	if err != nil {
+		return "", fmt.Errorf("error reading assets from %s: %w", assetsPath, err)
+	}
+
+	sa, err := engine.NewSessionAssets(envs.NewBuilder().Build(), source, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if len(flowUUIDs) == 0 {
+		all := &struct {
+			Flows []struct {
+				UUID assets.FlowUUID `json:"uuid"`
+			} `json:"flows"`
+		}{}
+		if err := jsonx.Unmarshal(assetsJSON, all); err != nil {
+			return "", fmt.Errorf("error reading flows from %s: %w", assetsPath, err)
+		}
+		for _, f := range all.Flows {
+			flowUUIDs = append(flowUUIDs, f.UUID)
+		}
+	}
+
+	fs := make([]flows.Flow, len(flowUUIDs))
+	for i, uuid := range flowUUIDs {
+		if fs[i], err = sa.Flows().Get(uuid); err != nil {
+			return "", err
+		}
+	}
+
+	graph := inspect.NewGraph(fs...)
+
+	if segmentsPath != "" {
+		counts, err := readExitCounts(segmentsPath)
+		if err != nil {
+			return "", err
+		}
+		graph.AddExitCounts(counts)
+	}
+
+	if format == "mermaid" {
+		return graph.Mermaid(), nil
+	}
+	return graph.DOT(), nil
+}
+
+// reads a JSON file of segments and counts how many times each exit was taken
+func readExitCounts(path string) (map[flows.ExitUUID]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading segments file %s: %w", path, err)
+	}
+
+	var segments []struct {
+		ExitUUID flows.ExitUUID `json:"exit_uuid"`
+	}
+	if err := jsonx.Unmarshal(data, &segments); err != nil {
+		return nil, fmt.Errorf("error reading segments from %s: %w", path, err)
+	}
+
+	counts := make(map[flows.ExitUUID]int)
+	for _, s := range segments {
+		counts[s.ExitUUID]++
+	}
+	return counts, nil
+}
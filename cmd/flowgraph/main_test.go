@@ -0,0 +1,47 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/nyaruka/goflow/assets"
+	main "github.com/nyaruka/goflow/cmd/flowgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const assetsPath = "../../test/testdata/runner/subflow.json"
+
+func TestGraph(t *testing.T) {
+	output, err := main.Graph(assetsPath, []assets.FlowUUID{"76f0a02f-3b75-4b86-9064-e9195e1b3a02"}, "testdata/segments.json", "dot")
+	require.NoError(t, err)
+	assert.Equal(t, `digraph {
+  node [shape=box];
+  subgraph cluster_0 {
+    label="Parent Flow";
+    n0 [label="send_msg: This is the parent flow\nenter_flow: Child Flow\nswitch"];
+    n1 [label="send_msg: Flow succeeded, they said @chi…"];
+    n2 [label="send_msg: Flow expired"];
+  }
+  f1 [label="Child Flow", shape=folder];
+  n0 -> f1 [style=dashed];
+  n0 -> n1 [label="Completed (2)"];
+  n0 -> n2 [label="Expired (1)"];
+}
+`, output)
+
+	// all flows in the assets
+	output, err = main.Graph(assetsPath, nil, "", "mermaid")
+	require.NoError(t, err)
+	assert.Contains(t, output, `subgraph flow0 ["Parent Flow"]`)
+	assert.Contains(t, output, `subgraph flow1 ["Child flow"]`)
+	assert.Contains(t, output, `n0 -.-> n3`)
+
+	_, err = main.Graph("missing.json", nil, "", "dot")
+	assert.EqualError(t, err, "error reading assets file missing.json: open missing.json: no such file or directory")
+
+	_, err = main.Graph(assetsPath, []assets.FlowUUID{"a121f1af-7dfa-47af-9d22-9726372e2daa"}, "", "dot")
+	assert.ErrorContains(t, err, "a121f1af-7dfa-47af-9d22-9726372e2daa")
+
+	_, err = main.Graph(assetsPath, nil, "missing.json", "dot")
+	assert.ErrorContains(t, err, "error reading segments file missing.json")
+}
@@ -0,0 +1,323 @@
+package main
+
+// go install github.com/nyaruka/goflow/cmd/flowlint
+// flowlint flow.json
+// flowlint -config lint.json -format sarif exports/ > results.sarif
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/nyaruka/gocommon/i18n"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/goflow/assets"
+	"github.com/nyaruka/goflow/assets/static"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/envs"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/definition"
+	"github.com/nyaruka/goflow/flows/definition/migrations"
+	"github.com/nyaruka/goflow/flows/engine"
+	"github.com/nyaruka/goflow/flows/inspect/issues"
+	"github.com/nyaruka/goflow/utils"
+)
+
+const usage = `usage: flowlint [flags] <path>...
+
+Each path can be a flow definition, a set of assets containing flows (e.g. an export), or a directory of those.`
+
+// exit codes
+const (
+	exitOK       = 0
+	exitFindings = 1
+	exitError    = 2
+)
+
+func main() {
+	var configPath, format string
+	var strict bool
+
+	flags := flag.NewFlagSet("", flag.ExitOnError)
+	flags.StringVar(&configPath, "config", "", "path to optional JSON config file of rule severities")
+	flags.StringVar(&format, "format", "text", "output format: text, json or sarif")
+	flags.BoolVar(&strict, "strict", false, "exit with non-zero code for warnings as well as errors")
+	flags.Parse(os.Args[1:])
+	args := flags.Args()
+
+	if len(args) == 0 || !slices.Contains(formats, format) {
+		fmt.Println(usage)
+		flags.PrintDefaults()
+		os.Exit(exitError)
+	}
+
+	cfg := DefaultConfig
+	if configPath != "" {
+		var err error
+		if cfg, err = LoadConfig(configPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitError)
+		}
+	}
+
+	findings, err := Lint(args, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+
+	if err := Write(os.Stdout, format, findings); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+
+	os.Exit(ExitCode(findings, strict))
+}
+
+// Severity is the severity of a finding
+type Severity string
+
+const (
+	SeverityOff     Severity = "off"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Config configures the severity of each issue type and rule
+type Config struct {
+	Rules map[string]Severity `json:"rules" validate:"dive,keys,required,endkeys,eq=off|eq=warning|eq=error"`
+}
+
+// DefaultConfig reports flow issues as errors and style rules as warnings
+var DefaultConfig = &Config{Rules: map[string]Severity{}}
+
+// LoadConfig loads a config from the given JSON file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", path, err)
+	}
+
+	cfg := &Config{}
+	if err := utils.UnmarshalAndValidate(data, cfg); err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", path, err)
+	}
+
+	for name := range cfg.Rules {
+		if issues.RegisteredTypes[name] == nil && styleRules[name] == nil {
+			return nil, fmt.Errorf("error reading config file %s: no such rule '%s'", path, name)
+		}
+	}
+	return cfg, nil
+}
+
+// gets the severity of the given issue type or rule
+func (c *Config) severity(rule string) Severity {
+	if s, configured := c.Rules[rule]; configured {
+		return s
+	}
+	if styleRules[rule] != nil {
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+// Finding is an issue or rule violation found in a flow
+type Finding struct {
+	File       string           `json:"file"`
+	FlowUUID   assets.FlowUUID  `json:"flow_uuid"`
+	FlowName   string           `json:"flow_name"`
+	Rule       string           `json:"rule"`
+	Severity   Severity         `json:"severity"`
+	NodeUUID   core.NodeUUID    `json:"node_uuid,omitempty"`
+	ActionUUID flows.ActionUUID `json:"action_uuid,omitempty"`
+	Language   i18n.Language    `json:"language,omitempty"`
+	Message    string           `json:"message"`
+}
+
+// Lint lints all the flows found at the given paths
+func Lint(paths []string, cfg *Config) ([]*Finding, error) {
+	files, err := findFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]*Finding, 0)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file %s: %w", file, err)
+		}
+
+		fileFindings, err := lintFile(file, data, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("error linting file %s: %w", file, err)
+		}
+		findings = append(findings, fileFindings...)
+	}
+
+	return findings, nil
+}
+
+// ExitCode determines the exit code for the given findings
+func ExitCode(findings []*Finding, strict bool) int {
+	for _, f := range findings {
+		if f.Severity == SeverityError || (strict && f.Severity == SeverityWarning) {
+			return exitFindings
+		}
+	}
+	return exitOK
+}
+
+// expands the given paths into a sorted list of JSON files
+func findFiles(paths []string) ([]string, error) {
+	files := make([]string, 0)
+
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && (p == path || strings.HasSuffix(p, ".json")) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+// lints a file which is either a single flow definition or a set of assets containing flows
+func lintFile(file string, data []byte, cfg *Config) ([]*Finding, error) {
+	findings := make([]*Finding, 0)
+
+	if _, dataType, _, _ := jsonparser.Get(data, "flows"); dataType != jsonparser.Array {
+		flow, err := definition.ReadFlow(data, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// without assets we can't check for missing dependencies
+		return lintFlow(file, nil, flow, cfg), nil
+	}
+
+	source, flowUUIDs, err := readAssets(data)
+	if err != nil {
+		return nil, err
+	}
+
+	sa, err := engine.NewSessionAssets(envs.NewBuilder().Build(), source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, flowUUID := range flowUUIDs {
+		flow, err := sa.Flows().Get(flowUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		findings = append(findings, lintFlow(file, sa, flow, cfg)...)
+	}
+	return findings, nil
+}
+
+// reads a set of assets, migrating its flows to the latest spec version
+func readAssets(data []byte) (assets.Source, []assets.FlowUUID, error) {
+	var set map[string]any
+	if err := jsonx.Unmarshal(data, &set); err != nil {
+		return nil, nil, err
+	}
+
+	flowsData, _ := set["flows"].([]any)
+	flowUUIDs := make([]assets.FlowUUID, len(flowsData))
+
+	for i, f := range flowsData {
+		migrated, err := migrations.MigrateToLatest(jsonx.MustMarshal(f), nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error migrating flow[%d]: %w", i, err)
+		}
+
+		header := &migrations.Header13{}
+		jsonx.MustUnmarshal(migrated, header)
+		flowUUIDs[i] = header.UUID
+
+		if flowsData[i], err = jsonx.DecodeGeneric(migrated); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	source, err := static.NewSource(jsonx.MustMarshal(set))
+	if err != nil {
+		return nil, nil, err
+	}
+	return source, flowUUIDs, nil
+}
+
+// lints a single flow, checking for issues and style rule violations
+func lintFlow(file string, sa flows.SessionAssets, flow flows.Flow, cfg *Config) []*Finding {
+	findings := make([]*Finding, 0)
+
+	report := func(rule string, nodeUUID core.NodeUUID, actionUUID flows.ActionUUID, lang i18n.Language, message string) {
+		if severity := cfg.severity(rule); severity != SeverityOff {
+			findings = append(findings, &Finding{
+				File:       file,
+				FlowUUID:   flow.UUID(),
+				FlowName:   flow.Name(),
+				Rule:       rule,
+				Severity:   severity,
+				NodeUUID:   nodeUUID,
+				ActionUUID: actionUUID,
+				Language:   lang,
+				Message:    message,
+			})
+		}
+	}
+
+	for _, issue := range flow.Inspect(sa).Issues {
+		report(issue.Type(), issue.NodeUUID(), issue.ActionUUID(), issue.Language(), issue.Description())
+	}
+
+	for _, name := range styleRuleNames() {
+		styleRules[name](flow, func(nodeUUID core.NodeUUID, actionUUID flows.ActionUUID, lang i18n.Language, message string) {
+			report(name, nodeUUID, actionUUID, lang, message)
+		})
+	}
+
+	return findings
+}
+
+// Write writes the given findings in the given format
+func Write(w io.Writer, format string, findings []*Finding) error {
+	var output []byte
+	var err error
+
+	switch format {
+	case "json":
+		output, err = jsonx.MarshalPretty(findings)
+	case "sarif":
+		output, err = jsonx.MarshalPretty(toSARIF(findings))
+	default:
+		output = []byte(toText(findings))
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(output))
+	return err
+}
+
+var formats = []string{"text", "json", "sarif"}
@@ -0,0 +1,181 @@
+package main_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	main "github.com/nyaruka/goflow/cmd/flowlint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	// a single flow without assets can't have missing dependencies
+	findings, err := main.Lint([]string{"testdata/flow.json"}, main.DefaultConfig)
+	require.NoError(t, err)
+
+	rules := make([]string, len(findings))
+	for i, f := range findings {
+		rules[i] = f.Rule
+		assert.Equal(t, main.SeverityWarning, f.Severity)
+		assert.Equal(t, "Support", f.FlowName)
+	}
+	assert.Equal(t, []string{"hardcoded_phone", "missing_translation", "missing_translation", "missing_translation", "wait_timeout"}, rules)
+	assert.Equal(t, "hardcoded phone number '+1 206 555 0100'", findings[0].Message)
+	assert.Equal(t, "b8a2d7c6-6f1e-4b1a-9d3e-0d6b1c2f9a02", string(findings[0].ActionUUID))
+	assert.Equal(t, "spa", string(findings[1].Language))
+	assert.Equal(t, "3f1c9b0e-8a7d-4c55-b1f2-7e6d5c4b3a02", string(findings[4].NodeUUID))
+
+	assert.Equal(t, 0, main.ExitCode(findings, false))
+	assert.Equal(t, 1, main.ExitCode(findings, true))
+
+	// a directory of exports with a config which changes severities
+	cfg, err := main.LoadConfig("testdata/config.json")
+	require.NoError(t, err)
+
+	findings, err = main.Lint([]string{"testdata/exports"}, cfg)
+	require.NoError(t, err)
+	require.Len(t, findings, 3)
+
+	assert.Equal(t, "testdata/exports/support.json", findings[0].File)
+	assert.Equal(t, "missing_dependency", findings[0].Rule)
+	assert.Equal(t, main.SeverityError, findings[0].Severity)
+	assert.Equal(t, "missing field dependency 'last_question'", findings[0].Message)
+	assert.Equal(t, "hardcoded_phone", findings[1].Rule)
+	assert.Equal(t, main.SeverityError, findings[1].Severity)
+	assert.Equal(t, "wait_timeout", findings[2].Rule)
+	assert.Equal(t, main.SeverityWarning, findings[2].Severity)
+
+	assert.Equal(t, 1, main.ExitCode(findings, false))
+
+	_, err = main.Lint([]string{"testdata/missing.json"}, main.DefaultConfig)
+	assert.ErrorContains(t, err, "missing.json")
+
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"uuid": "d6f8a3b2-52c4-4a8e-9f5a-2b0cf3c3e101"}`), 0644))
+
+	_, err = main.Lint([]string{invalid}, main.DefaultConfig)
+	assert.ErrorContains(t, err, "error linting file "+invalid)
+}
+
+func TestHardcodedPhone(t *testing.T) {
+	tcs := []struct {
+		text  string
+		phone string
+	}{
+		{"Or call us on +1 206 555 0100", "+1 206 555 0100"},
+		{"Call +250788123123 or +1 206 555 0100", "+250788123123"},
+		{"Your appointment is on 2024-01-15", ""},
+		{"Your order 1234567890 has shipped", ""},
+		{"Call 206 555 0100", ""},
+		{"Reference +0000 0000 00", ""},
+	}
+
+	for _, tc := range tcs {
+		flow := filepath.Join(t.TempDir(), "flow.json")
+		require.NoError(t, os.WriteFile(flow, []byte(`{
+			"uuid": "d6f8a3b2-52c4-4a8e-9f5a-2b0cf3c3e101",
+			"name": "Support",
+			"spec_version": "14.4.2",
+			"language": "eng",
+			"type": "messaging",
+			"nodes": [
+				{
+					"uuid": "3f1c9b0e-8a7d-4c55-b1f2-7e6d5c4b3a01",
+					"actions": [{"uuid": "b8a2d7c6-6f1e-4b1a-9d3e-0d6b1c2f9a01", "type": "send_msg", "text": "`+tc.text+`"}],
+					"exits": [{"uuid": "5d0e2b7a-4c3f-4e8b-a1d9-6f2c8b7e1a01"}]
+				}
+			]
+		}`), 0644))
+
+		findings, err := main.Lint([]string{flow}, main.DefaultConfig)
+		require.NoError(t, err)
+
+		if tc.phone != "" {
+			if assert.Len(t, findings, 1, "findings mismatch for '%s'", tc.text) {
+				assert.Equal(t, "hardcoded phone number '"+tc.phone+"'", findings[0].Message)
+			}
+		} else {
+			assert.Len(t, findings, 0, "findings mismatch for '%s'", tc.text)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig := func(s string) string {
+		path := filepath.Join(dir, "config.json")
+		require.NoError(t, os.WriteFile(path, []byte(s), 0644))
+		return path
+	}
+
+	cfg, err := main.LoadConfig(writeConfig(`{"rules": {"legacy_vars": "off", "wait_timeout": "error"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]main.Severity{"legacy_vars": "off", "wait_timeout": "error"}, cfg.Rules)
+
+	_, err = main.LoadConfig(writeConfig(`{"rules": {"legacy_vars": "fatal"}}`))
+	assert.ErrorContains(t, err, "failed tag")
+
+	_, err = main.LoadConfig(writeConfig(`{"rules": {"spelling": "error"}}`))
+	assert.ErrorContains(t, err, "no such rule 'spelling'")
+
+	_, err = main.LoadConfig(filepath.Join(dir, "missing.json"))
+	assert.ErrorContains(t, err, "error reading config file")
+}
+
+func TestWrite(t *testing.T) {
+	findings, err := main.Lint([]string{"testdata/exports"}, main.DefaultConfig)
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, main.Write(out, "text", findings[:1]))
+	assert.Equal(t, "testdata/exports/support.json: flow 'Support' > node 3f1c9b0e-8a7d-4c55-b1f2-7e6d5c4b3a03 > action b8a2d7c6-6f1e-4b1a-9d3e-0d6b1c2f9a03: error: missing field dependency 'last_question' [missing_dependency]\n", out.String())
+
+	out.Reset()
+	require.NoError(t, main.Write(out, "text", nil))
+	assert.Equal(t, "no issues found\n", out.String())
+
+	out.Reset()
+	require.NoError(t, main.Write(out, "json", findings[:1]))
+	assert.JSONEq(t, `[
+		{
+			"file": "testdata/exports/support.json",
+			"flow_uuid": "d6f8a3b2-52c4-4a8e-9f5a-2b0cf3c3e101",
+			"flow_name": "Support",
+			"rule": "missing_dependency",
+			"severity": "error",
+			"node_uuid": "3f1c9b0e-8a7d-4c55-b1f2-7e6d5c4b3a03",
+			"action_uuid": "b8a2d7c6-6f1e-4b1a-9d3e-0d6b1c2f9a03",
+			"message": "missing field dependency 'last_question'"
+		}
+	]`, out.String())
+
+	out.Reset()
+	require.NoError(t, main.Write(out, "sarif", findings[:1]))
+	assert.JSONEq(t, `{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": [
+			{
+				"tool": {"driver": {"name": "flowlint", "rules": [{"id": "missing_dependency"}]}},
+				"results": [
+					{
+						"ruleId": "missing_dependency",
+						"level": "error",
+						"message": {"text": "missing field dependency 'last_question'"},
+						"locations": [
+							{
+								"physicalLocation": {"artifactLocation": {"uri": "testdata/exports/support.json"}},
+								"logicalLocations": [
+									{"fullyQualifiedName": "flow 'Support' > node 3f1c9b0e-8a7d-4c55-b1f2-7e6d5c4b3a03 > action b8a2d7c6-6f1e-4b1a-9d3e-0d6b1c2f9a03"}
+								]
+							}
+						]
+					}
+				]
+			}
+		]
+	}`, out.String())
+}
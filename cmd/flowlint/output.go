@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// formats findings as lines of text
+func toText(findings []*Finding) string {
+	if len(findings) == 0 {
+		return "no issues found"
+	}
+
+	lines := make([]string, len(findings))
+	for i, f := range findings {
+		lines[i] = fmt.Sprintf("%s: %s: %s: %s [%s]", f.File, location(f), f.Severity, f.Message, f.Rule)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formats the location of a finding in a flow, e.g. flow 'Registration' > node 1234... > action 2345...
+func location(f *Finding) string {
+	parts := []string{fmt.Sprintf("flow '%s'", f.FlowName)}
+	if f.NodeUUID != "" {
+		parts = append(parts, fmt.Sprintf("node %s", f.NodeUUID))
+	}
+	if f.ActionUUID != "" {
+		parts = append(parts, fmt.Sprintf("action %s", f.ActionUUID))
+	}
+	if f.Language != "" {
+		parts = append(parts, fmt.Sprintf("language %s", f.Language))
+	}
+	return strings.Join(parts, " > ")
+}
+
+// minimal subset of SARIF 2.1.0 (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html)
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name  string       `json:"name"`
+			Rules []*sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string           `json:"ruleId"`
+	Level     string           `json:"level"`
+	Message   sarifMessage     `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+	LogicalLocations []*sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+func toSARIF(findings []*Finding) *sarifLog {
+	run := &sarifRun{}
+	run.Tool.Driver.Name = "flowlint"
+	run.Tool.Driver.Rules = make([]*sarifRule, 0)
+	run.Results = make([]*sarifResult, len(findings))
+
+	seenRules := make(map[string]bool)
+
+	for i, f := range findings {
+		if !seenRules[f.Rule] {
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &sarifRule{ID: f.Rule})
+			seenRules[f.Rule] = true
+		}
+
+		loc := &sarifLocation{LogicalLocations: []*sarifLogicalLocation{{FullyQualifiedName: location(f)}}}
+		loc.PhysicalLocation.ArtifactLocation.URI = f.File
+
+		run.Results[i] = &sarifResult{
+			RuleID:    f.Rule,
+			Level:     string(f.Severity),
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []*sarifLocation{loc},
+		}
+	}
+
+	return &sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []*sarifRun{run},
+	}
+}
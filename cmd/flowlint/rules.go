@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/nyaruka/gocommon/i18n"
+	"github.com/nyaruka/gocommon/uuids"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/inspect"
+	"github.com/nyaruka/goflow/flows/routers/waits"
+	"github.com/nyaruka/goflow/utils"
+)
+
+type reportFunc func(core.NodeUUID, flows.ActionUUID, i18n.Language, string)
+
+// style rules are checks which aren't issues in the engine sense but which authors can opt into
+var styleRules = map[string]func(flows.Flow, reportFunc){
+	"wait_timeout":        checkWaitTimeout,
+	"hardcoded_phone":     checkHardcodedPhone,
+	"missing_translation": checkMissingTranslation,
+}
+
+func styleRuleNames() []string {
+	return slices.Sorted(maps.Keys(styleRules))
+}
+
+// checks that every message wait has a timeout
+func checkWaitTimeout(flow flows.Flow, report reportFunc) {
+	for _, node := range flow.Nodes() {
+		if node.Router() != nil && node.Router().Wait() != nil {
+			wait := node.Router().Wait()
+			if wait.Type() == waits.TypeMsg && wait.Timeout() == nil {
+				report(node.UUID(), "", i18n.NilLanguage, "wait for message has no timeout")
+			}
+		}
+	}
+}
+
+// only international numbers are matched so that dates, amounts and other long numbers aren't mistaken for phones
+var phoneRegex = regexp.MustCompile(`\+\d[\d \-]{6,}\d`)
+
+// checks that templates don't contain phone numbers which should be in globals instead
+func checkHardcodedPhone(flow flows.Flow, report reportFunc) {
+	for _, node := range flow.Nodes() {
+		node.EnumerateTemplates(flow.Localization(), func(a flows.Action, r flows.Router, l i18n.Language, t string) {
+			for _, candidate := range phoneRegex.FindAllString(t, -1) {
+				if utils.ParsePhoneNumber(candidate, i18n.NilCountry) != "" {
+					report(node.UUID(), actionUUID(a), l, fmt.Sprintf("hardcoded phone number '%s'", candidate))
+					break
+				}
+			}
+		})
+	}
+}
+
+// checks that all localizable text has a translation in every language that the flow has translations for
+func checkMissingTranslation(flow flows.Flow, report reportFunc) {
+	languages := flow.Localization().Languages()
+	slices.Sort(languages)
+
+	for _, node := range flow.Nodes() {
+		check := func(a flows.Action) func(uuids.UUID, string, []string, func([]string)) {
+			return func(itemUUID uuids.UUID, property string, values []string, w func([]string)) {
+				if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+					return
+				}
+				// router items like categories aren't identified by the finding so include their UUID in the message
+				message := fmt.Sprintf("missing translation of %s", property)
+				if a == nil {
+					message = fmt.Sprintf("missing translation of %s of %s", property, itemUUID)
+				}
+
+				for _, lang := range languages {
+					if len(flow.Localization().GetItemTranslation(lang, itemUUID, property)) == 0 {
+						report(node.UUID(), actionUUID(a), lang, message)
+					}
+				}
+			}
+		}
+
+		for _, action := range node.Actions() {
+			inspect.LocalizableText(action, check(action))
+		}
+		if node.Router() != nil {
+			node.Router().EnumerateLocalizables(check(nil))
+		}
+	}
+}
+
+func actionUUID(a flows.Action) flows.ActionUUID {
+	if a != nil {
+		return a.UUID()
+	}
+	return ""
+}
@@ -13,11 +13,14 @@ const (
 	ErrInvalidLanguage       = "invalid_language"       // `value` the value we tried to parse as a language code
 	ErrInvalidGroup          = "invalid_group"          // `value` the value we tried to parse as a group name
 	ErrInvalidFlow           = "invalid_flow"           // `value` the value we tried to parse as a flow name
+	ErrInvalidBoolean        = "invalid_boolean"        // `value` the value we tried to parse as a boolean
+	ErrInvalidChoice         = "invalid_choice"         // `property` the property key, `value` the value which isn't a choice of that field
 	ErrInvalidPartialName    = "invalid_partial_name"   // `min_token_length` the minimum length of token required for name contains condition
 	ErrInvalidPartialURN     = "invalid_partial_urn"    // `min_value_length` the minimum length of value required for URN contains condition
 	ErrUnsupportedContains   = "unsupported_contains"   // `property` the property key
 	ErrUnsupportedComparison = "unsupported_comparison" // `property` the property key, `operator` one of =>, <, >=, <=
 	ErrUnsupportedSetCheck   = "unsupported_setcheck"   // `property` the property key, `operator` one of =, !=
+	ErrUnsupportedJSON       = "unsupported_json"       // `property` the property key
 	ErrUnknownPropertyType   = "unknown_property_type"  // `type` the property type
 	ErrUnknownProperty       = "unknown_property"       // `property` the property key
 	ErrRedactedURNs          = "redacted_urns"
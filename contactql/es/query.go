@@ -91,7 +91,7 @@ func (c *Converter) fieldCondition(resolver contactql.Resolver, cond *contactql.
 
 	// special cases for set/unset
 	if (cond.Operator() == contactql.OpEqual || cond.Operator() == contactql.OpNotEqual) && cond.Value() == "" {
-		query := elastic.Nested("fields", elastic.All(fieldQuery, elastic.Exists("fields."+string(fieldType))))
+		query := elastic.Nested("fields", elastic.All(fieldQuery, elastic.Exists(fieldValueKey(fieldType))))
 
 		// if we are looking for unset, inverse our query
 		if cond.Operator() == contactql.OpEqual {
@@ -100,8 +100,8 @@ func (c *Converter) fieldCondition(resolver contactql.Resolver, cond *contactql.
 		return query
 	}
 
-	if fieldType == assets.FieldTypeText {
-		value := strings.ToLower(cond.Value())
+	if fieldType == assets.FieldTypeText || fieldType == assets.FieldTypeChoice {
+		value := strings.ToLower(strings.TrimSpace(cond.Value()))
 
 		switch cond.Operator() {
 		case contactql.OpEqual:
@@ -184,11 +184,47 @@ func (c *Converter) fieldCondition(resolver contactql.Resolver, cond *contactql.
 		default:
 			panic(fmt.Sprintf("unsupported location field operator: %s", cond.Operator()))
 		}
+
+	} else if fieldType == assets.FieldTypeBoolean {
+		value, _ := cond.ValueAsBoolean()
+
+		switch cond.Operator() {
+		case contactql.OpEqual:
+			return elastic.Nested("fields", elastic.All(fieldQuery, elastic.Term("fields.boolean", value)))
+		case contactql.OpNotEqual:
+			return elastic.Not(elastic.Nested("fields", elastic.All(fieldQuery, elastic.Term("fields.boolean", value))))
+		default:
+			panic(fmt.Sprintf("unsupported boolean field operator: %s", cond.Operator()))
+		}
+
+	} else if fieldType == assets.FieldTypeMultiChoice {
+		value := strings.ToLower(strings.TrimSpace(cond.Value()))
+
+		switch cond.Operator() {
+		case contactql.OpEqual, contactql.OpContains:
+			return elastic.Nested("fields", elastic.All(fieldQuery, elastic.Term("fields.choices", value)))
+		case contactql.OpNotEqual:
+			return elastic.Not(elastic.Nested("fields", elastic.All(fieldQuery, elastic.Term("fields.choices", value))))
+		default:
+			panic(fmt.Sprintf("unsupported multi-choice field operator: %s", cond.Operator()))
+		}
 	}
 
 	panic(fmt.Sprintf("unsupported field type: %s", fieldType))
 }
 
+// gets the key of the values of the given field type in contact documents. Choices are indexed as text, and
+// multiple choices as a list of lowercase keywords.
+func fieldValueKey(fieldType assets.FieldType) string {
+	switch fieldType {
+	case assets.FieldTypeChoice:
+		return "fields.text"
+	case assets.FieldTypeMultiChoice:
+		return "fields.choices"
+	}
+	return "fields." + string(fieldType)
+}
+
 func (c *Converter) attributeCondition(resolver contactql.Resolver, cond *contactql.Condition) elastic.Query {
 	key := cond.PropertyKey()
 	value := strings.ToLower(cond.Value())
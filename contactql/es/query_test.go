@@ -46,6 +46,10 @@ func newMockResolver() contactql.Resolver {
 			static.NewField("67663ad1-3abc-42dd-a162-09df2dea66ec", "state", "State", assets.FieldTypeState),
 			static.NewField("54c72635-d747-4e45-883c-099d57dd998e", "district", "District", assets.FieldTypeDistrict),
 			static.NewField("fde8f740-c337-421b-8abb-83b954897c80", "ward", "Ward", assets.FieldTypeWard),
+			static.NewField("1f6d3a3c-7a0e-4b0e-9d4f-3b2c1a0f9e8d", "vip", "VIP", assets.FieldTypeBoolean),
+			static.NewChoiceField("2a7e4b4d-8b1f-4c1f-8e5a-4c3d2b1a0f9e", "tier", "Tier", assets.FieldTypeChoice, []string{"Bronze", "Silver", "Gold"}),
+			static.NewChoiceField("3b8f5c5e-9c2a-4d2a-9f6b-5d4e3c2b1a0f", "tags", "Tags", assets.FieldTypeMultiChoice, []string{"VIP", "Donor"}),
+			static.NewField("4c9a6d6f-ad3b-4e3b-a07c-6e5f4d3c2b1a", "profile", "Profile", assets.FieldTypeJSON),
 		},
 		[]assets.Flow{
 			static.NewFlow("c261165a-f5b0-40ba-b916-76fb49667a4f", "Registration", []byte(`{}`)),
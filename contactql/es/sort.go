@@ -46,8 +46,10 @@ func (c *Converter) Sort(sortBy string, resolver contactql.Resolver) (elastic.So
 	switch field.Type() {
 	case assets.FieldTypeState, assets.FieldTypeDistrict, assets.FieldTypeWard:
 		key = fmt.Sprintf("fields.%s_keyword", field.Type())
+	case assets.FieldTypeJSON:
+		return nil, fmt.Errorf("can't sort by JSON field: %s", property)
 	default:
-		key = fmt.Sprintf("fields.%s", field.Type())
+		key = fieldValueKey(field.Type())
 	}
 
 	return elastic.SortNested(key, elastic.Term("fields.field", field.UUID()), "fields", ascending), nil
@@ -15,8 +15,9 @@ import (
 // Queryable is the interface objects must implement queried
 //
 // Returned values must match the type the queried property is declared as - *types.XNumber for number
-// properties, time.Time for datetime ones and string for everything else - as evaluation asserts them to
-// that type. Return no values rather than a value of a different type.
+// properties, time.Time for datetime ones, bool for boolean ones and string for everything else - as evaluation
+// asserts them to that type. Return no values rather than a value of a different type. Multi-choice properties
+// return a value for each selected choice.
 type Queryable interface {
 	QueryProperty(envs.Environment, string, PropertyType) []any
 }
@@ -103,6 +104,16 @@ func evaluateConditionWithValue(env envs.Environment, resolver Resolver, c *Cond
 	case assets.FieldTypeDatetime:
 		asDate, _ := c.ValueAsDate(env)
 		return dateComparison(val.(time.Time), c.operator, asDate)
+	case assets.FieldTypeBoolean:
+		asBool, _ := c.ValueAsBoolean()
+		return booleanComparison(val.(bool), c.operator, asBool)
+	case assets.FieldTypeMultiChoice:
+		// values are individual choices so contains is the same as equals
+		op := c.operator
+		if op == OpContains {
+			op = OpEqual
+		}
+		return textComparison(val.(string), op, c.value, false)
 	default:
 		isName := c.propKey == AttributeName // needs to be handled as special case
 		return textComparison(val.(string), c.operator, c.value, isName)
@@ -129,6 +140,17 @@ func textComparison(objectVal string, op Operator, queryVal string, isName bool)
 	}
 }
 
+func booleanComparison(objectVal bool, op Operator, queryVal bool) bool {
+	switch op {
+	case OpEqual:
+		return objectVal == queryVal
+	case OpNotEqual:
+		return objectVal != queryVal
+	default:
+		panic(fmt.Sprintf("can't query boolean fields with %s", op))
+	}
+}
+
 func numberComparison(objectVal *types.XNumber, op Operator, queryVal *types.XNumber) bool {
 	switch op {
 	case OpEqual:
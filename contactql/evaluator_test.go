@@ -36,6 +36,10 @@ func TestEvaluateQuery(t *testing.T) {
 		"state":    []any{"Kigali"},
 		"district": []any{"Gasabo"},
 		"ward":     []any{"Ndera"},
+		"vip":      []any{true},
+		"tier":     []any{"Gold"},
+		"tags":     []any{"VIP", "Donor"},
+		"profile":  []any{`{"city":"Kigali"}`},
 		"empty":    []any{""},
 		"nope":     []any{envs.NewBuilder().Build()},
 	}
@@ -131,6 +135,29 @@ func TestEvaluateQuery(t *testing.T) {
 		{query: `ward != ndera`, result: false},
 		{query: `ward != solano`, result: true},
 
+		// boolean field condition
+		{query: `vip = true`, result: true},
+		{query: `vip = YES`, result: true},
+		{query: `vip = no`, result: false},
+		{query: `vip != false`, result: true},
+		{query: `vip != true`, result: false},
+
+		// choice field condition
+		{query: `tier = gold`, result: true},
+		{query: `tier = Silver`, result: false},
+		{query: `tier != silver`, result: true},
+
+		// multi-choice field condition
+		{query: `tags ~ "vip"`, result: true},
+		{query: `tags ~ staff`, result: false},
+		{query: `tags = donor`, result: true},
+		{query: `tags != donor`, result: false},
+		{query: `tags != staff`, result: true},
+
+		// JSON fields can only be checked for existence
+		{query: `profile != ""`, result: true},
+		{query: `profile = ""`, result: false},
+
 		// existence
 		{query: `age = ""`, result: false},
 		{query: `age != ""`, result: true},
@@ -157,6 +184,10 @@ func TestEvaluateQuery(t *testing.T) {
 			static.NewField("369be3e2-0186-4e5d-93c4-6264736588f8", "state", "State", assets.FieldTypeState),
 			static.NewField("e52f34ad-a5a7-4855-9040-05a910a75f57", "district", "District", assets.FieldTypeDistrict),
 			static.NewField("e9e738ce-617d-4c61-bfce-3d3b55cfe3dd", "ward", "Ward", assets.FieldTypeWard),
+			static.NewField("a41f7f7f-2a0d-4bf4-9c5b-3b3c6e6f7a21", "vip", "VIP", assets.FieldTypeBoolean),
+			static.NewChoiceField("3c6b5a1e-8f0d-4e55-a8b6-1d6c3a9f2e44", "tier", "Tier", assets.FieldTypeChoice, []string{"Bronze", "Silver", "Gold"}),
+			static.NewChoiceField("6e2d9c4b-7a1f-4b3e-9d8c-5f4a2b1c0e99", "tags", "Tags", assets.FieldTypeMultiChoice, []string{"VIP", "Donor", "Staff"}),
+			static.NewField("0f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a", "profile", "Profile", assets.FieldTypeJSON),
 			static.NewField("023f733d-ce00-4a61-96e4-b411987028ea", "empty", "Empty", assets.FieldTypeText),
 			static.NewField("81e25783-a1d8-42b9-85e4-68c7ab2df39d", "xyz", "XYZ", assets.FieldTypeText),
 		},
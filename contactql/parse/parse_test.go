@@ -109,9 +109,9 @@ func TestParseQuery(t *testing.T) {
 		// field conditions
 		{text: `Age IS 18`, parsed: `fields.age = 18`, resolver: resolver},
 		{text: `AGE != ""`, parsed: `fields.age != ""`, resolver: resolver},
-		{text: `age ~ 34`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
+		{text: `age ~ 34`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
 		{text: `FIELDS.AGE != "45"`, parsed: `fields.age != 45`, resolver: resolver},
-		{text: `gender ~ M`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
+		{text: `gender ~ M`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
 		{text: `fields.language = "EN"`, parsed: `fields.language = "EN"`, resolver: resolver},
 
 		// lt/lte/gt/gte comparisons
@@ -219,21 +219,21 @@ func TestParseQuery(t *testing.T) {
 		{text: `state = ""`, parsed: `fields.state = ""`, resolver: resolver},
 
 		// ~ only supported for name and URNs
-		{text: `uuid ~ 02352`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
-		{text: `id ~ 02352`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
+		{text: `uuid ~ 02352`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
+		{text: `id ~ 02352`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
 		{text: `name ~ felix`, parsed: `name ~ "felix"`, resolver: resolver},
-		{text: `status ~ sto`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
-		{text: `language ~ eng`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
-		{text: `group ~ porters`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
-		{text: `flow ~ reg`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
-		{text: `tickets ~ 12`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
-		{text: `created_on ~ 2018`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
+		{text: `status ~ sto`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
+		{text: `language ~ eng`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
+		{text: `group ~ porters`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
+		{text: `flow ~ reg`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
+		{text: `tickets ~ 12`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
+		{text: `created_on ~ 2018`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
 		{text: `urn ~ 02352`, parsed: `urn ~ 02352`, resolver: resolver},
 		{text: `tel ~ 02352`, parsed: `urns.tel ~ 02352`, resolver: resolver},
-		{text: `age ~ 18`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
-		{text: `gender ~ mal`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
-		{text: `dob ~ 20-02-2020`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
-		{text: `state ~ Pichincha`, err: "contains conditions can only be used with name or URN values", resolver: resolver},
+		{text: `age ~ 18`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
+		{text: `gender ~ mal`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
+		{text: `dob ~ 20-02-2020`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
+		{text: `state ~ Pichincha`, err: "contains conditions can only be used with name, URN or multi-choice values", resolver: resolver},
 
 		// > >= < <= only supported for numeric or date fields
 		{text: `uuid > 02352`, err: "comparisons with > can only be used with date and number fields", resolver: resolver},
@@ -377,7 +377,7 @@ func TestParsingErrors(t *testing.T) {
 		},
 		{
 			query:    `uuid ~ 234`,
-			errMsg:   "contains conditions can only be used with name or URN values",
+			errMsg:   "contains conditions can only be used with name, URN or multi-choice values",
 			errCode:  "unsupported_contains",
 			errExtra: map[string]any{"property": "uuid"},
 		},
@@ -399,6 +399,24 @@ func TestParsingErrors(t *testing.T) {
 			errCode:  "unsupported_setcheck",
 			errExtra: map[string]any{"property": "uuid", "operator": "!="},
 		},
+		{
+			query:    `vip = maybe`,
+			errMsg:   "can't convert 'maybe' to a boolean",
+			errCode:  "invalid_boolean",
+			errExtra: map[string]any{"value": "maybe"},
+		},
+		{
+			query:    `tags ~ platinum`,
+			errMsg:   "'platinum' is not a valid choice for field 'tags'",
+			errCode:  "invalid_choice",
+			errExtra: map[string]any{"property": "tags", "value": "platinum"},
+		},
+		{
+			query:    `profile = Kigali`,
+			errMsg:   "can only check whether JSON field 'profile' is set or not set",
+			errCode:  "unsupported_json",
+			errExtra: map[string]any{"property": "profile"},
+		},
 		{
 			query:    `beers = 12`,
 			errMsg:   "can't resolve 'beers' to attribute, scheme or field",
@@ -419,6 +437,9 @@ func TestParsingErrors(t *testing.T) {
 			static.NewField("f1b5aea6-6586-41c7-9020-1a6326cc6565", "age", "Age", assets.FieldTypeNumber),
 			static.NewField("3810a485-3fda-4011-a589-7320c0b8dbef", "dob", "DOB", assets.FieldTypeDatetime),
 			static.NewField("d66a7823-eada-40e5-9a3a-57239d4690bf", "gender", "Gender", assets.FieldTypeText),
+			static.NewField("a41f7f7f-2a0d-4bf4-9c5b-3b3c6e6f7a21", "vip", "VIP", assets.FieldTypeBoolean),
+			static.NewChoiceField("6e2d9c4b-7a1f-4b3e-9d8c-5f4a2b1c0e99", "tags", "Tags", assets.FieldTypeMultiChoice, []string{"VIP", "Donor"}),
+			static.NewField("0f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a", "profile", "Profile", assets.FieldTypeJSON),
 		},
 		[]assets.Flow{},
 		[]assets.Group{},
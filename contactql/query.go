@@ -146,6 +146,11 @@ func (c *Condition) ValueAsNumber() (*types.XNumber, error) {
 	return types.NewXNumberFromString(c.value)
 }
 
+// ValueAsBoolean returns the value as a boolean if possible, or an error if not
+func (c *Condition) ValueAsBoolean() (bool, error) {
+	return utils.ParseBoolean(c.value)
+}
+
 // ValueAsDate returns the value as a date if possible, or an error if not
 func (c *Condition) ValueAsDate(env envs.Environment) (time.Time, error) {
 	return envs.DateTimeFromString(env, c.value, false)
@@ -203,9 +208,9 @@ func (c *Condition) validate(env envs.Environment, resolver Resolver) error {
 			if len(c.value) < minURNContainsLength {
 				return NewQueryError(ErrInvalidPartialURN, fmt.Sprintf("contains operator on URN requires value of minimum length %d", minURNContainsLength)).WithExtra("min_value_length", strconv.Itoa(minURNContainsLength))
 			}
-		} else {
-			// ~ can only be used with the name/urn attributes or actual URNs
-			return NewQueryError(ErrUnsupportedContains, "contains conditions can only be used with name or URN values").WithExtra("property", c.propKey)
+		} else if valueType != assets.FieldTypeMultiChoice {
+			// ~ can only be used with the name/urn attributes, actual URNs or multi-choice fields
+			return NewQueryError(ErrUnsupportedContains, "contains conditions can only be used with name, URN or multi-choice values").WithExtra("property", c.propKey)
 		}
 
 	case OpGreaterThan, OpGreaterThanOrEqual, OpLessThan, OpLessThanOrEqual:
@@ -222,7 +227,19 @@ func (c *Condition) validate(env envs.Environment, resolver Resolver) error {
 		}
 	} else {
 		// check values are valid for the value type
-		if valueType == assets.FieldTypeNumber {
+		if valueType == assets.FieldTypeJSON {
+			return NewQueryError(ErrUnsupportedJSON, fmt.Sprintf("can only check whether JSON field '%s' is set or not set", c.propKey)).WithExtra("property", c.propKey)
+		} else if valueType == assets.FieldTypeBoolean {
+			_, err := c.ValueAsBoolean()
+			if err != nil {
+				return NewQueryError(ErrInvalidBoolean, fmt.Sprintf("can't convert '%s' to a boolean", c.value)).WithExtra("value", c.value)
+			}
+		} else if valueType == assets.FieldTypeChoice || valueType == assets.FieldTypeMultiChoice {
+			field := resolver.ResolveField(c.propKey)
+			if !utils.StringSliceContains(assets.FieldChoices(field), strings.TrimSpace(c.value), false) {
+				return NewQueryError(ErrInvalidChoice, fmt.Sprintf("'%s' is not a valid choice for field '%s'", c.value, c.propKey)).WithExtra("property", c.propKey).WithExtra("value", c.value)
+			}
+		} else if valueType == assets.FieldTypeNumber {
 			_, err := c.ValueAsNumber()
 			if err != nil {
 				return NewQueryError(ErrInvalidNumber, fmt.Sprintf("can't convert '%s' to a number", c.value)).WithExtra("value", c.value)
@@ -6,6 +6,7 @@ type Assets interface {
 	Campaigns() *CampaignAssets
 	Channels() *ChannelAssets
 	Fields() *FieldAssets
+	Functions() *FunctionAssets
 	Globals() *GlobalAssets
 	Groups() *GroupAssets
 	Labels() *LabelAssets
@@ -615,12 +615,7 @@ func (c *Contact) QueryProperty(env envs.Environment, key string, propType conta
 	}
 
 	// try as a contact field
-	nativeValue := c.fields[key].QueryValue()
-	if nativeValue == nil {
-		return nil
-	}
-
-	return []any{nativeValue}
+	return c.fields[key].QueryValues()
 }
 
 var _ contactql.Queryable = (*Contact)(nil)
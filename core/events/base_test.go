@@ -325,6 +325,22 @@ func TestEventMarshaling(t *testing.T) {
 			},
 			`msg_created`,
 		},
+		{
+			func() events.Event {
+				return events.NewMsgScheduled(
+					core.NewMsgOut(
+						urns.URN("tel:+12345678900"),
+						assets.NewChannelReference(assets.ChannelUUID("57f1078f-88aa-46f4-a59a-948a5739c03d"), "My Android Phone"),
+						&core.MsgContent{Text: "Don't forget your appointment tomorrow"},
+						nil,
+						i18n.NilLocale,
+						"",
+					),
+					time.Date(2025, 5, 4, 14, 30, 0, 0, time.UTC),
+				)
+			},
+			`msg_scheduled`,
+		},
 		{
 			func() events.Event {
 				return events.NewMsgCreated(
@@ -561,7 +577,7 @@ func TestWebhookCalledEventTrimming(t *testing.T) {
 	assert.Equal(t, 42, len(call.ResponseTrace))
 	assert.Equal(t, 20000, len(call.ResponseBody))
 
-	event := events.NewWebhookCalled(call, core.CallStatusSuccess, "")
+	event := events.NewWebhookCalled(call, core.CallStatusSuccess, "", nil)
 
 	assert.Equal(t, "http://temba.io/", event.URL)
 	assert.Equal(t, 10000, len(event.Request))
@@ -583,7 +599,7 @@ func TestWebhookCalledEventValid(t *testing.T) {
 	call, err := svc.Call(request)
 	require.NoError(t, err)
 
-	event := events.NewWebhookCalled(call, core.CallStatusSuccess, "")
+	event := events.NewWebhookCalled(call, core.CallStatusSuccess, "", nil)
 
 	assert.Equal(t, "http://temba.io/", event.URL)
 	assert.Equal(t, "HTTP/1.0 200 OK\r\nContent-Length: 14\r\nHeader: hello\r\n\r\n{\"foo\": \"bar\"}", event.Response)
@@ -603,7 +619,7 @@ func TestWebhookCalledEventNullChar(t *testing.T) {
 	call, err := svc.Call(request)
 	require.NoError(t, err)
 
-	event := events.NewWebhookCalled(call, core.CallStatusSuccess, "")
+	event := events.NewWebhookCalled(call, core.CallStatusSuccess, "", nil)
 
 	// actual null will have been stripped, escaped null will remain
 	assert.Equal(t, "http://temba.io/", event.URL)
@@ -624,7 +640,7 @@ func TestWebhookCalledEventBadUTF8(t *testing.T) {
 	call, err := svc.Call(request)
 	require.NoError(t, err)
 
-	event := events.NewWebhookCalled(call, core.CallStatusSuccess, "")
+	event := events.NewWebhookCalled(call, core.CallStatusSuccess, "", nil)
 
 	assert.Equal(t, "http://temba.io/", event.URL)
 	assert.Equal(t, "HTTP/1.0 200 OK\r\nContent-Length: 13\r\nBad-Header: �\r\n\r\n...", event.Response)
@@ -1,6 +1,8 @@
 package events
 
 import (
+	"time"
+
 	"github.com/nyaruka/gocommon/i18n"
 	"github.com/nyaruka/gocommon/urns"
 	"github.com/nyaruka/goflow/assets"
@@ -14,7 +16,9 @@ func init() {
 // TypeBroadcastCreated is a constant for outgoing message events
 const TypeBroadcastCreated string = "broadcast_created"
 
-// BroadcastCreated events are created when a message is being broadcast to contacts besides the session contact.
+// BroadcastCreated events are created when a message is being broadcast to contacts besides the session contact. If
+// it's created outside of the send windows of the flow or environment, `send_after` is the time when the next window
+// opens in the environment's timezone, as recipients may be in different timezones.
 //
 //	{
 //	  "uuid": "0197b335-6ded-79a4-95a6-3af85b57f108",
@@ -51,6 +55,7 @@ type BroadcastCreated struct {
 	URNs              []urns.URN                 `json:"urns,omitempty" validate:"dive,urn"`
 	Template          *assets.TemplateReference  `json:"template,omitempty"`
 	TemplateVariables []string                   `json:"template_variables,omitempty"`
+	SendAfter         *time.Time                 `json:"send_after,omitempty"`
 }
 
 // NewBroadcastCreated creates a new outgoing msg event for the given recipients
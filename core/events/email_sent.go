@@ -22,9 +22,14 @@ const TypeEmailSent string = "email_sent"
 type EmailSent struct {
 	BaseEvent
 
-	To      []string `json:"to" validate:"required,min=1"`
-	Subject string   `json:"subject" validate:"required"`
-	Body    string   `json:"body"`
+	To          []string `json:"to" validate:"required,min=1"`
+	CC          []string `json:"cc,omitempty"`
+	BCC         []string `json:"bcc,omitempty"`
+	ReplyTo     string   `json:"reply_to,omitempty"`
+	Subject     string   `json:"subject" validate:"required"`
+	Body        string   `json:"body"`
+	HTMLBody    string   `json:"html_body,omitempty"`
+	Attachments []string `json:"attachments,omitempty"`
 }
 
 // NewEmailSent returns a new email event with the passed in subject, body and emails
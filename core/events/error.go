@@ -15,9 +15,12 @@ const TypeError string = "error"
 
 const (
 	ErrorCodeActionUnsupported    = "action:unsupported"
+	ErrorCodeDatetimeInvalid      = "datetime:invalid"
 	ErrorCodeDependencyMissing    = "dependency:missing"
+	ErrorCodeFieldValueInvalid    = "field:value_invalid"
 	ErrorCodeGroupMissing         = "group:missing"
 	ErrorCodeLabelMissing         = "label:missing"
+	ErrorCodeRunFailureHandled    = "run:failure_handled"
 	ErrorCodeTimezoneInvalid      = "timezone:invalid"
 	ErrorCodeURLBlocked           = "url:blocked"
 	ErrorCodeURLInvalid           = "url:invalid"
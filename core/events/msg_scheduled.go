@@ -0,0 +1,47 @@
+package events
+
+import (
+	"time"
+
+	"github.com/nyaruka/goflow/core"
+)
+
+func init() {
+	registerType(TypeMsgScheduled, func() Event { return &MsgScheduled{} })
+}
+
+// TypeMsgScheduled is a constant for outgoing messages which should be sent later
+const TypeMsgScheduled string = "msg_scheduled"
+
+// MsgScheduled events are created when an action wants to send a message to the current contact at a later time.
+// It's up to the caller to deliver the message when `fire_on` is reached, without the session waiting for it.
+//
+//	{
+//	  "uuid": "0197b335-6ded-79a4-95a6-3af85b57f108",
+//	  "type": "msg_scheduled",
+//	  "created_on": "2006-01-02T15:04:05Z",
+//	  "msg": {
+//	    "uuid": "2d611e17-fb22-457f-b802-b8f7ec5cda5b",
+//	    "channel": {"uuid": "61602f3e-f603-4c70-8a8f-c477505bf4bf", "name": "Twilio"},
+//	    "urn": "tel:+12065551212",
+//	    "text": "Don't forget your appointment tomorrow"
+//	  },
+//	  "fire_on": "2006-01-02T17:04:05Z"
+//	}
+//
+// @event msg_scheduled
+type MsgScheduled struct {
+	BaseEvent
+
+	Msg    *core.MsgOut `json:"msg"     validate:"required"`
+	FireOn time.Time    `json:"fire_on" validate:"required"`
+}
+
+// NewMsgScheduled creates a new scheduled outgoing msg event
+func NewMsgScheduled(msg *core.MsgOut, fireOn time.Time) *MsgScheduled {
+	return &MsgScheduled{
+		BaseEvent: NewBaseEvent(TypeMsgScheduled),
+		Msg:       msg,
+		FireOn:    fireOn,
+	}
+}
@@ -2,6 +2,7 @@ package events
 
 import (
 	"github.com/nyaruka/gocommon/httpx"
+	"github.com/nyaruka/gocommon/stringsx"
 	"github.com/nyaruka/goflow/core"
 )
 
@@ -39,11 +40,11 @@ type WebhookCalled struct {
 	Resthook string `json:"resthook,omitempty"`
 }
 
-// NewWebhookCalled returns a new webhook called event
-func NewWebhookCalled(trace *httpx.Trace, status core.CallStatus, resthook string) *WebhookCalled {
+// NewWebhookCalled returns a new webhook called event, using the given redactor (if any) on the URL and traces
+func NewWebhookCalled(trace *httpx.Trace, status core.CallStatus, resthook string, redact stringsx.Redactor) *WebhookCalled {
 	return &WebhookCalled{
 		BaseEvent:          NewBaseEvent(TypeWebhookCalled),
-		HTTPLogWithoutTime: core.NewHTTPLogWithoutTime(trace, status, nil),
+		HTTPLogWithoutTime: core.NewHTTPLogWithoutTime(trace, status, redact),
 		Resthook:           resthook,
 	}
 }
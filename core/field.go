@@ -1,13 +1,22 @@
 package core
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/nyaruka/goflow/assets"
 	"github.com/nyaruka/goflow/envs"
 	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/utils"
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 )
 
 // Field represents a contact field
@@ -31,6 +40,165 @@ func (f *Field) Reference() *assets.FieldReference {
 	return assets.NewFieldReference(f.Key(), f.Name())
 }
 
+// Choices returns the allowed values of this field if it's a choice field
+func (f *Field) Choices() []string { return assets.FieldChoices(f.Field) }
+
+// Rules returns the rules that values of this field must follow, if any
+func (f *Field) Rules() *assets.FieldRules { return assets.FieldRulesOf(f.Field) }
+
+// Choice gets the allowed choice of this field which matches the given value ignoring case, or empty string
+func (f *Field) Choice(value string) string {
+	value = strings.TrimSpace(value)
+	for _, c := range f.Choices() {
+		if strings.EqualFold(c, value) {
+			return c
+		}
+	}
+	return ""
+}
+
+// Normalize applies the normalizers of this field to the given raw value and then checks that the result is valid
+// for the type and rules of this field, returning the value to be saved. An empty value is always valid as it clears
+// the field.
+func (f *Field) Normalize(env envs.Environment, rawValue string) (string, error) {
+	if rawValue == "" {
+		return "", nil
+	}
+
+	rules := f.Rules()
+
+	if rules != nil {
+		for _, n := range rules.Normalize {
+			switch n {
+			case assets.FieldNormalizerPhone:
+				phone := utils.ParsePhoneNumber(rawValue, env.DefaultCountry())
+				if phone == "" {
+					return "", f.ruleError("'%s' is not a valid phone number", rawValue)
+				}
+				rawValue = phone
+			case assets.FieldNormalizerTitleCase:
+				rawValue = cases.Title(language.Und).String(rawValue)
+			case assets.FieldNormalizerUpperCase:
+				rawValue = strings.ToUpper(rawValue)
+			case assets.FieldNormalizerLowerCase:
+				rawValue = strings.ToLower(rawValue)
+			}
+		}
+	}
+
+	if err := f.validateType(rawValue); err != nil {
+		return "", err
+	}
+
+	if rules != nil {
+		if err := f.validateRules(env, rules, rawValue); err != nil {
+			return "", err
+		}
+	}
+
+	return rawValue, nil
+}
+
+// checks that the given raw value is valid for the type of this field. Only fields of types with a fixed set of
+// values or a particular format reject values.
+func (f *Field) validateType(rawValue string) error {
+	switch f.Type() {
+	case assets.FieldTypeBoolean:
+		if _, err := utils.ParseBoolean(rawValue); err != nil {
+			return err
+		}
+	case assets.FieldTypeChoice:
+		if f.Choice(rawValue) == "" {
+			return fmt.Errorf("'%s' is not a valid choice for field '%s'", rawValue, f.Key())
+		}
+	case assets.FieldTypeMultiChoice:
+		for _, v := range strings.Split(rawValue, ",") {
+			if strings.TrimSpace(v) != "" && f.Choice(v) == "" {
+				return fmt.Errorf("'%s' is not a valid choice for field '%s'", strings.TrimSpace(v), f.Key())
+			}
+		}
+	case assets.FieldTypeJSON:
+		if !isJSONObject([]byte(rawValue)) {
+			return fmt.Errorf("'%s' is not a valid JSON object", rawValue)
+		}
+	}
+	return nil
+}
+
+// checks that the given raw value follows the given rules of this field
+func (f *Field) validateRules(env envs.Environment, rules *assets.FieldRules, rawValue string) error {
+	if rules.MaxLength > 0 && utf8.RuneCountInString(rawValue) > rules.MaxLength {
+		return f.ruleError("'%s' is longer than %d characters", rawValue, rules.MaxLength)
+	}
+	if rules.Regex != "" {
+		// regexes of assets are validated when they're loaded so an invalid one here was constructed directly
+		if re, err := regexp.Compile(rules.Regex); err == nil && !re.MatchString(rawValue) {
+			return f.ruleError("'%s' doesn't match the required format", rawValue)
+		}
+	}
+
+	if rules.Min == "" && rules.Max == "" {
+		return nil
+	}
+
+	switch f.Type() {
+	case assets.FieldTypeNumber:
+		num, xerr := types.ToXNumber(env, types.NewXText(rawValue))
+		if xerr != nil {
+			return f.ruleError("'%s' is not a valid number", rawValue)
+		}
+		if min, err := types.NewXNumberFromString(rules.Min); err == nil && num.Compare(min) < 0 {
+			return f.ruleError("'%s' is less than the minimum of %s", rawValue, rules.Min)
+		}
+		if max, err := types.NewXNumberFromString(rules.Max); err == nil && num.Compare(max) > 0 {
+			return f.ruleError("'%s' is greater than the maximum of %s", rawValue, rules.Max)
+		}
+	case assets.FieldTypeDatetime:
+		dt, xerr := types.ToXDateTimeWithTimeFill(env, types.NewXText(rawValue))
+		if xerr != nil {
+			return f.ruleError("'%s' is not a valid date", rawValue)
+		}
+		if min, err := envs.DateTimeFromString(env, rules.Min, false); err == nil && dt.Native().Before(min) {
+			return f.ruleError("'%s' is before the minimum of %s", rawValue, rules.Min)
+		}
+		// the maximum date is inclusive so values anytime on that day are allowed
+		if max, err := envs.DateTimeFromString(env, rules.Max, false); err == nil && !dt.Native().Before(max.AddDate(0, 0, 1)) {
+			return f.ruleError("'%s' is after the maximum of %s", rawValue, rules.Max)
+		}
+	}
+	return nil
+}
+
+// creates an error for a value which breaks a rule of this field, using the field's own message if it has one
+func (f *Field) ruleError(format string, args ...any) error {
+	if rules := f.Rules(); rules != nil && rules.Message != "" {
+		return errors.New(rules.Message)
+	}
+	return fmt.Errorf(format+" for field '%s'", append(args, f.Key())...)
+}
+
+// gets the choices selected by the given raw value of a multi-choice field, in the order the field defines them
+func (f *Field) selectedChoices(rawValue string) []string {
+	selected := make(map[string]bool)
+	for _, v := range strings.Split(rawValue, ",") {
+		if c := f.Choice(v); c != "" {
+			selected[c] = true
+		}
+	}
+
+	choices := make([]string, 0, len(selected))
+	for _, c := range f.Choices() {
+		if selected[c] {
+			choices = append(choices, c)
+		}
+	}
+	return choices
+}
+
+func isJSONObject(data []byte) bool {
+	return json.Valid(data) && bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
 // FieldValue represents a field and a set of values for that field
 type FieldValue struct {
 	field *Field
@@ -65,22 +233,42 @@ func (v *FieldValue) ToXValue(env envs.Environment) types.XValue {
 		}
 	case assets.FieldTypeState:
 		if v.State != "" {
-			return types.NewXText(string(v.State))
+			return locationToXValue(env, v.State)
 		}
 	case assets.FieldTypeDistrict:
 		if v.District != "" {
-			return types.NewXText(string(v.District))
+			return locationToXValue(env, v.District)
 		}
 	case assets.FieldTypeWard:
 		if v.Ward != "" {
-			return types.NewXText(string(v.Ward))
+			return locationToXValue(env, v.Ward)
+		}
+	case assets.FieldTypeBoolean:
+		if v.Boolean != nil {
+			return v.Boolean
+		}
+	case assets.FieldTypeChoice:
+		if v.field.Choice(v.Text.Native()) != "" {
+			return v.Text
+		}
+	case assets.FieldTypeMultiChoice:
+		if len(v.Choices) > 0 {
+			choices := make([]types.XValue, len(v.Choices))
+			for i, c := range v.Choices {
+				choices[i] = types.NewXText(c)
+			}
+			return types.NewXArray(choices...)
+		}
+	case assets.FieldTypeJSON:
+		if len(v.JSON) > 0 {
+			return types.JSONToXValue(v.JSON)
 		}
 	}
 	return nil
 }
 
-// QueryValue returns the value for use in contact queries
-func (v *FieldValue) QueryValue() any {
+// QueryValues returns the values for use in contact queries, which is multiple values for multi-choice fields
+func (v *FieldValue) QueryValues() []any {
 	// the typed value of no value is nil
 	if v == nil {
 		return nil
@@ -88,33 +276,100 @@ func (v *FieldValue) QueryValue() any {
 
 	switch v.field.Type() {
 	case assets.FieldTypeText:
-		return v.Text.Native()
+		return []any{v.Text.Native()}
 	case assets.FieldTypeDatetime:
 		if v.Datetime != nil {
-			return (*v.Datetime).Native()
+			return []any{(*v.Datetime).Native()}
 		}
 	case assets.FieldTypeNumber:
 		if v.Number != nil {
-			return v.Number
+			return []any{v.Number}
 		}
 
 	// we only search against location names and not full paths
 	case assets.FieldTypeState:
 		if v.State != "" {
-			return v.State.Name()
+			return []any{v.State.Name()}
 		}
 	case assets.FieldTypeDistrict:
 		if v.District != "" {
-			return v.District.Name()
+			return []any{v.District.Name()}
 		}
 	case assets.FieldTypeWard:
 		if v.Ward != "" {
-			return v.Ward.Name()
+			return []any{v.Ward.Name()}
+		}
+	case assets.FieldTypeBoolean:
+		if v.Boolean != nil {
+			return []any{v.Boolean.Native()}
+		}
+	case assets.FieldTypeChoice:
+		if c := v.field.Choice(v.Text.Native()); c != "" {
+			return []any{c}
+		}
+	case assets.FieldTypeMultiChoice:
+		vals := make([]any, len(v.Choices))
+		for i, c := range v.Choices {
+			vals[i] = c
+		}
+		return vals
+	case assets.FieldTypeJSON:
+		if len(v.JSON) > 0 {
+			return []any{string(v.JSON)}
 		}
 	}
 	return nil
 }
 
+// converts a location value to an object which defaults to the path of the location, and which is still marshaled as
+// just that path as location values were previously text
+func locationToXValue(env envs.Environment, path envs.LocationPath) types.XValue {
+	var location *envs.Location
+	if locations := env.LocationResolver(); locations != nil {
+		location = locations.LookupLocation(path)
+	}
+
+	obj := types.NewXObject(locationContext(path, location))
+	obj.SetMarshalAsDefault(true)
+	return obj
+}
+
+// locationContext returns the properties of a location field value available in expressions
+//
+//	__default__:text -> the path of the location
+//	name:text -> the name of the location
+//	latitude:number -> the latitude of the centroid of the location if known
+//	longitude:number -> the longitude of the centroid of the location if known
+//	bbox:[]number -> the bounding box of the location as its west, south, east and north edges if known
+//
+// @context location
+func locationContext(path envs.LocationPath, location *envs.Location) map[string]types.XValue {
+	var latitude, longitude, bbox types.XValue
+
+	if location != nil {
+		if c := location.Centroid(); c != nil {
+			latitude = types.NewXNumber(decimal.NewFromFloat(c.Lat))
+			longitude = types.NewXNumber(decimal.NewFromFloat(c.Lng))
+		}
+		if b := location.BBox(); b != nil {
+			bbox = types.NewXArray(
+				types.NewXNumber(decimal.NewFromFloat(b.Min.Lng)),
+				types.NewXNumber(decimal.NewFromFloat(b.Min.Lat)),
+				types.NewXNumber(decimal.NewFromFloat(b.Max.Lng)),
+				types.NewXNumber(decimal.NewFromFloat(b.Max.Lat)),
+			)
+		}
+	}
+
+	return map[string]types.XValue{
+		"__default__": types.NewXText(string(path)),
+		"name":        types.NewXText(path.Name()),
+		"latitude":    latitude,
+		"longitude":   longitude,
+		"bbox":        bbox,
+	}
+}
+
 // FieldValues is the set of all field values for a contact
 type FieldValues map[string]*FieldValue
 
@@ -171,6 +426,36 @@ func (f FieldValues) Parse(env envs.Environment, fields *FieldAssets, field *Fie
 		return nil
 	}
 
+	var asBoolean *types.XBoolean
+	var asChoices []string
+	var asJSON json.RawMessage
+
+	// values of fields with a fixed set of values or a particular format are normalized
+	switch field.Type() {
+	case assets.FieldTypeBoolean:
+		if b, err := utils.ParseBoolean(rawValue); err == nil {
+			asBoolean = types.NewXBoolean(b)
+			rawValue = asBoolean.Render()
+		}
+	case assets.FieldTypeChoice:
+		if c := field.Choice(rawValue); c != "" {
+			rawValue = c
+		}
+	case assets.FieldTypeMultiChoice:
+		if asChoices = field.selectedChoices(rawValue); len(asChoices) > 0 {
+			rawValue = strings.Join(asChoices, ", ")
+		} else {
+			asChoices = nil
+		}
+	case assets.FieldTypeJSON:
+		if isJSONObject([]byte(rawValue)) {
+			b := &bytes.Buffer{}
+			json.Compact(b, []byte(rawValue))
+			asJSON = b.Bytes()
+			rawValue = b.String()
+		}
+	}
+
 	var asText = types.NewXText(rawValue)
 	var asDateTime *types.XDateTime
 	var asNumber *types.XNumber
@@ -246,6 +531,9 @@ func (f FieldValues) Parse(env envs.Environment, fields *FieldAssets, field *Fie
 		State:    asState,
 		District: asDistrict,
 		Ward:     asWard,
+		Boolean:  asBoolean,
+		Choices:  asChoices,
+		JSON:     asJSON,
 	}
 }
 
@@ -281,7 +569,7 @@ func (f FieldValues) getFirstLocationValue(env envs.Environment, fields *FieldAs
 		return nil
 	}
 
-	return env.LocationResolver().LookupLocation(envs.LocationPath(value.(*types.XText).Native()))
+	return env.LocationResolver().LookupLocation(envs.LocationPath(types.Render(value)))
 }
 
 // FieldAssets provides access to all field assets
@@ -3,10 +3,12 @@ package core_test
 import (
 	"testing"
 
+	"github.com/nyaruka/gocommon/jsonx"
 	"github.com/nyaruka/goflow/assets"
 	"github.com/nyaruka/goflow/assets/static"
 	"github.com/nyaruka/goflow/core"
 	"github.com/nyaruka/goflow/envs"
+	"github.com/nyaruka/goflow/excellent"
 	"github.com/nyaruka/goflow/excellent/types"
 	"github.com/nyaruka/goflow/test"
 
@@ -165,3 +167,201 @@ func TestFieldValueParseWithIncompleteLocationHierarchy(t *testing.T) {
 			"parse mismatch at location level %d", tc.level)
 	}
 }
+
+func TestLocationFieldValues(t *testing.T) {
+	env := envs.NewBuilder().Build()
+	hierarchy, err := envs.ReadLocationHierarchy(env, []byte(`{
+		"name": "Rwanda",
+		"children": [
+			{
+				"name": "Kigali City",
+				"children": [
+					{"name": "Gasabo", "centroid": [30.15, -1.925], "bbox": [30.05, -2.0, 30.25, -1.85]},
+					{"name": "Nyarugenge"}
+				]
+			}
+		]
+	}`))
+	require.NoError(t, err)
+
+	env = envs.NewBuilder().WithLocationResolver(core.NewLocationAssets([]assets.LocationHierarchy{hierarchy})).Build()
+	fields := core.NewFieldAssets([]assets.Field{
+		static.NewField("f4a0d0c1-4d0e-4e9c-9b2b-1a3e6f1b2c3d", "state", "State", assets.FieldTypeState),
+		static.NewField("2c7f1b8e-9d3a-4b6c-8e5f-0a1b2c3d4e5f", "district", "District", assets.FieldTypeDistrict),
+	})
+	state, district := fields.Get("state"), fields.Get("district")
+
+	values := core.FieldValues{}
+	values[state.Key()] = core.NewFieldValue(state, values.Parse(env, fields, state, "Kigali City"))
+	values[district.Key()] = core.NewFieldValue(district, values.Parse(env, fields, district, "gasabo"))
+
+	// location values are objects which default to their paths
+	test.AssertXEqual(t, types.NewXObject(map[string]types.XValue{
+		"__default__": types.NewXText("Rwanda > Kigali City"),
+		"name":        types.NewXText("Kigali City"),
+		"latitude":    nil,
+		"longitude":   nil,
+		"bbox":        nil,
+	}), values[state.Key()].ToXValue(env))
+
+	// and include coordinates if the location has them
+	test.AssertXEqual(t, types.NewXObject(map[string]types.XValue{
+		"__default__": types.NewXText("Rwanda > Kigali City > Gasabo"),
+		"name":        types.NewXText("Gasabo"),
+		"latitude":    types.RequireXNumberFromString("-1.925"),
+		"longitude":   types.RequireXNumberFromString("30.15"),
+		"bbox": types.NewXArray(
+			types.RequireXNumberFromString("30.05"),
+			types.RequireXNumberFromString("-2"),
+			types.RequireXNumberFromString("30.25"),
+			types.RequireXNumberFromString("-1.85"),
+		),
+	}), values[district.Key()].ToXValue(env))
+
+	// they're still rendered and marshaled as just their paths
+	assert.Equal(t, "Rwanda > Kigali City > Gasabo", types.Render(values[district.Key()].ToXValue(env)))
+
+	marshaled, err := jsonx.Marshal(values[district.Key()].ToXValue(env))
+	require.NoError(t, err)
+	assert.Equal(t, `"Rwanda > Kigali City > Gasabo"`, string(marshaled))
+
+	// and can be used in expressions
+	eval := excellent.NewEvaluator(excellent.DefaultEvaluationBudget)
+	root := types.NewXObject(map[string]types.XValue{"fields": types.NewXObject(values.Context(env))})
+	for tpl, expected := range map[string]string{
+		"@fields.district":           "Rwanda > Kigali City > Gasabo",
+		"@fields.district.name":      "Gasabo",
+		"@fields.district.latitude":  "-1.925",
+		"@fields.district.longitude": "30.15",
+		"@fields.state.latitude":     "",
+		"@(json(fields.district))":   `"Rwanda > Kigali City > Gasabo"`,
+	} {
+		actual, _, err := eval.Template(t.Context(), env, root, tpl, nil)
+		assert.NoError(t, err, "error evaluating %s", tpl)
+		assert.Equal(t, expected, actual, "output mismatch for %s", tpl)
+	}
+
+	// if we don't have locations, they don't have coordinates
+	test.AssertXEqual(t, types.NewXObject(map[string]types.XValue{
+		"__default__": types.NewXText("Rwanda > Kigali City > Gasabo"),
+		"name":        types.NewXText("Gasabo"),
+		"latitude":    nil,
+		"longitude":   nil,
+		"bbox":        nil,
+	}), values[district.Key()].ToXValue(envs.NewBuilder().Build()))
+}
+
+func TestTypedFieldValues(t *testing.T) {
+	env := envs.NewBuilder().Build()
+	fields := core.NewFieldAssets([]assets.Field{
+		static.NewField("a41f7f7f-2a0d-4bf4-9c5b-3b3c6e6f7a21", "vip", "VIP", assets.FieldTypeBoolean),
+		static.NewChoiceField("3c6b5a1e-8f0d-4e55-a8b6-1d6c3a9f2e44", "tier", "Tier", assets.FieldTypeChoice, []string{"Bronze", "Silver", "Gold"}),
+		static.NewChoiceField("6e2d9c4b-7a1f-4b3e-9d8c-5f4a2b1c0e99", "tags", "Tags", assets.FieldTypeMultiChoice, []string{"Donor", "Volunteer", "Staff"}),
+		static.NewField("0f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a", "profile", "Profile", assets.FieldTypeJSON),
+	})
+	vip, tier, tags, profile := fields.Get("vip"), fields.Get("tier"), fields.Get("tags"), fields.Get("profile")
+
+	tcs := []struct {
+		field       *core.Field
+		value       string
+		err         string
+		expected    *core.Value
+		xValue      types.XValue
+		queryValues []any
+	}{
+		{field: vip, value: "", expected: nil, xValue: nil, queryValues: nil},
+		{field: vip, value: "YES", expected: &core.Value{Text: types.NewXText("true"), Boolean: types.XBooleanTrue}, xValue: types.XBooleanTrue, queryValues: []any{true}},
+		{field: vip, value: "0", expected: &core.Value{Text: types.NewXText("false"), Boolean: types.XBooleanFalse}, xValue: types.XBooleanFalse, queryValues: []any{false}},
+		{field: vip, value: "maybe", err: "'maybe' is not a valid boolean"},
+		{field: tier, value: " silver", expected: &core.Value{Text: types.NewXText("Silver")}, xValue: types.NewXText("Silver"), queryValues: []any{"Silver"}},
+		{field: tier, value: "Platinum", err: "'Platinum' is not a valid choice for field 'tier'"},
+		{field: tags, value: "staff,donor, ,Donor", expected: &core.Value{Text: types.NewXText("Donor, Staff"), Choices: []string{"Donor", "Staff"}}, xValue: types.NewXArray(types.NewXText("Donor"), types.NewXText("Staff")), queryValues: []any{"Donor", "Staff"}},
+		{field: tags, value: "Donor, Customer", err: "'Customer' is not a valid choice for field 'tags'"},
+		{field: profile, value: `{"city": "Kigali"}`, expected: &core.Value{Text: types.NewXText(`{"city":"Kigali"}`), JSON: []byte(`{"city":"Kigali"}`)}, xValue: types.NewXObject(map[string]types.XValue{"city": types.NewXText("Kigali")}), queryValues: []any{`{"city":"Kigali"}`}},
+		{field: profile, value: `"Kigali"`, err: `'"Kigali"' is not a valid JSON object`},
+		{field: profile, value: `{"city": `, err: `'{"city": ' is not a valid JSON object`},
+	}
+
+	for _, tc := range tcs {
+		value, err := tc.field.Normalize(env, tc.value)
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err, "validation error mismatch for field %s and value '%s'", tc.field.Key(), tc.value)
+			continue
+		}
+		assert.NoError(t, err, "unexpected validation error for field %s and value '%s'", tc.field.Key(), tc.value)
+
+		fieldVals := core.FieldValues{}
+		actual := fieldVals.Parse(env, fields, tc.field, value)
+		assert.Equal(t, tc.expected, actual, "parse mismatch for field %s and value '%s'", tc.field.Key(), tc.value)
+
+		fieldVals.Set(tc.field, actual)
+		test.AssertXEqual(t, tc.xValue, fieldVals[tc.field.Key()].ToXValue(env), "xvalue mismatch for field %s and value '%s'", tc.field.Key(), tc.value)
+		assert.Equal(t, tc.queryValues, fieldVals[tc.field.Key()].QueryValues(), "query values mismatch for field %s and value '%s'", tc.field.Key(), tc.value)
+	}
+
+	// values are compared including their typed parts
+	v1 := &core.Value{Text: types.NewXText("Donor"), Choices: []string{"Donor"}}
+	v2 := &core.Value{Text: types.NewXText("Donor"), Choices: []string{"Donor", "Staff"}}
+	v3 := &core.Value{Text: types.NewXText("true"), Boolean: types.XBooleanTrue}
+	v4 := &core.Value{Text: types.NewXText("true")}
+	assert.True(t, v1.Equals(v1))
+	assert.False(t, v1.Equals(v2))
+	assert.True(t, v3.Equals(v3))
+	assert.False(t, v3.Equals(v4))
+}
+
+func TestFieldRules(t *testing.T) {
+	env := envs.NewBuilder().WithDefaultCountry("RW").WithDateFormat(envs.DateFormatYearMonthDay).Build()
+	fields := core.NewFieldAssets([]assets.Field{
+		static.NewFieldWithRules("0e5aa7d0-6e2c-4cc8-8f2d-6e2f3b0c2a57", "national_id", "National ID", assets.FieldTypeText, &assets.FieldRules{
+			Normalize: []assets.FieldNormalizer{assets.FieldNormalizerUpperCase},
+			Regex:     `^[A-Z]{2}\d{6}$`,
+			Message:   "ID must be 2 letters and 6 digits",
+		}),
+		static.NewFieldWithRules("9a2c2e6b-3d8e-4f63-a5c4-2f1f6b5d7e10", "nickname", "Nickname", assets.FieldTypeText, &assets.FieldRules{
+			Normalize: []assets.FieldNormalizer{assets.FieldNormalizerTitleCase},
+			MaxLength: 10,
+		}),
+		static.NewFieldWithRules("4b7f0c1d-6a3e-4e2b-9c8d-1e0f2a3b4c5d", "work_phone", "Work Phone", assets.FieldTypeText, &assets.FieldRules{
+			Normalize: []assets.FieldNormalizer{assets.FieldNormalizerPhone},
+		}),
+		static.NewFieldWithRules("f1b5aea6-6586-41c7-9020-1a6326cc6565", "age", "Age", assets.FieldTypeNumber, &assets.FieldRules{Min: "0", Max: "120"}),
+		static.NewFieldWithRules("3810a485-3fda-4011-a589-7320c0b8dbef", "dob", "DOB", assets.FieldTypeDatetime, &assets.FieldRules{Min: "1900-01-01", Max: "2020-12-31"}),
+		static.NewField("d66a7823-eada-40e5-9a3a-57239d4690bf", "gender", "Gender", assets.FieldTypeText),
+	})
+
+	tcs := []struct {
+		field    string
+		value    string
+		expected string
+		err      string
+	}{
+		{"national_id", "", "", ""},
+		{"national_id", "ab123456", "AB123456", ""},
+		{"national_id", "ab12345", "", "ID must be 2 letters and 6 digits"},
+		{"nickname", "bobby SMITH", "", "'Bobby Smith' is longer than 10 characters for field 'nickname'"},
+		{"nickname", "bobby", "Bobby", ""},
+		{"work_phone", "0788 123 123", "+250788123123", ""},
+		{"work_phone", "call me", "", "'call me' is not a valid phone number for field 'work_phone'"},
+		{"age", "36", "36", ""},
+		{"age", "-1", "", "'-1' is less than the minimum of 0 for field 'age'"},
+		{"age", "121", "", "'121' is greater than the maximum of 120 for field 'age'"},
+		{"age", "old", "", "'old' is not a valid number for field 'age'"},
+		{"dob", "1981-05-28", "1981-05-28", ""},
+		{"dob", "2020-12-31 18:30", "2020-12-31 18:30", ""},
+		{"dob", "1899-12-31", "", "'1899-12-31' is before the minimum of 1900-01-01 for field 'dob'"},
+		{"dob", "2021-01-01", "", "'2021-01-01' is after the maximum of 2020-12-31 for field 'dob'"},
+		{"dob", "soon", "", "'soon' is not a valid date for field 'dob'"},
+		{"gender", "anything goes", "anything goes", ""},
+	}
+
+	for _, tc := range tcs {
+		actual, err := fields.Get(tc.field).Normalize(env, tc.value)
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err, "error mismatch for field %s and value '%s'", tc.field, tc.value)
+		} else {
+			assert.NoError(t, err, "unexpected error for field %s and value '%s'", tc.field, tc.value)
+			assert.Equal(t, tc.expected, actual, "normalized value mismatch for field %s and value '%s'", tc.field, tc.value)
+		}
+	}
+}
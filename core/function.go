@@ -0,0 +1,87 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/nyaruka/goflow/assets"
+	"github.com/nyaruka/goflow/excellent"
+)
+
+// Function represents a custom function which can be called in expressions.
+type Function struct {
+	assets.Function
+
+	compiled *excellent.CustomFunction
+}
+
+// NewFunction returns a new function object from the given function asset, which will be called in contexts with the
+// given top-levels
+func NewFunction(asset assets.Function, topLevels []string) (*Function, error) {
+	compiled, err := excellent.NewCustomFunction(asset.Name(), asset.Params(), asset.Body(), topLevels)
+	if err != nil {
+		return nil, err
+	}
+	return &Function{Function: asset, compiled: compiled}, nil
+}
+
+// Asset returns the underlying asset
+func (f *Function) Asset() assets.Function { return f.Function }
+
+// Reference returns a reference to this function
+func (f *Function) Reference() *assets.FunctionReference {
+	return assets.NewFunctionReference(f.compiled.Name())
+}
+
+// FunctionAssets provides access to all function assets
+type FunctionAssets struct {
+	all     []*Function
+	byName  map[string]*Function
+	invalid map[string]error
+	custom  *excellent.CustomFunctions
+}
+
+// NewFunctionAssets creates a new set of function assets, skipping any which aren't valid but recording why so that
+// flows which call them can be reported
+func NewFunctionAssets(functions []assets.Function, topLevels []string) *FunctionAssets {
+	s := &FunctionAssets{
+		all:     make([]*Function, 0, len(functions)),
+		byName:  make(map[string]*Function, len(functions)),
+		invalid: make(map[string]error),
+	}
+	compiled := make([]*excellent.CustomFunction, 0, len(functions))
+
+	for _, asset := range functions {
+		function, err := NewFunction(asset, topLevels)
+		if err != nil {
+			s.invalid[strings.ToLower(asset.Name())] = err
+			continue
+		}
+		s.all = append(s.all, function)
+		s.byName[function.compiled.Name()] = function
+		compiled = append(compiled, function.compiled)
+	}
+
+	s.custom = excellent.NewCustomFunctions(compiled)
+	return s
+}
+
+// All returns all the valid functions
+func (s *FunctionAssets) All() []*Function {
+	return s.all
+}
+
+// Get returns the function with the given name (case-insensitive)
+func (s *FunctionAssets) Get(name string) *Function {
+	return s.byName[strings.ToLower(name)]
+}
+
+// Invalid returns the error for the function asset with the given name (case-insensitive) if it was skipped because it
+// isn't valid
+func (s *FunctionAssets) Invalid(name string) error {
+	return s.invalid[strings.ToLower(name)]
+}
+
+// CustomFunctions returns these functions in the form used by the expression evaluator
+func (s *FunctionAssets) CustomFunctions() *excellent.CustomFunctions {
+	return s.custom
+}
@@ -0,0 +1,38 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/nyaruka/goflow/assets"
+	"github.com/nyaruka/goflow/assets/static"
+	"github.com/nyaruka/goflow/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctions(t *testing.T) {
+	fa1 := static.NewFunction("National_ID", []string{"value"}, `upper(replace(value, "-", ""))`)
+	fa2 := static.NewFunction("upper", []string{"value"}, `value`) // clashes with builtin
+	fa3 := static.NewFunction("broken", []string{"value"}, `value +`)
+	fa4 := static.NewFunction("Contact", nil, `1`) // clashes with context top-level
+
+	fa := core.NewFunctionAssets([]assets.Function{fa1, fa2, fa3, fa4}, []string{"contact", "results"})
+
+	assert.Len(t, fa.All(), 1)
+	assert.Nil(t, fa.Get("upper"))
+	assert.Nil(t, fa.Get("broken"))
+	assert.Nil(t, fa.Get("contact"))
+	assert.Error(t, fa.Invalid("upper"))
+	assert.Error(t, fa.Invalid("Broken"))
+	assert.EqualError(t, fa.Invalid("contact"), "'contact' is the name of a top-level context value")
+	assert.NoError(t, fa.Invalid("national_id"))
+	assert.NoError(t, fa.Invalid("unknown"))
+
+	f1 := fa.Get("national_id")
+	assert.Equal(t, fa1, f1.Asset())
+	assert.Equal(t, assets.NewFunctionReference("national_id"), f1.Reference())
+	assert.Equal(t, f1, fa.Get("NATIONAL_ID"))
+
+	assert.NotNil(t, fa.CustomFunctions().Lookup("national_id"))
+	assert.Nil(t, fa.CustomFunctions().Lookup("upper"))
+}
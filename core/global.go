@@ -1,9 +1,11 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/nyaruka/gocommon/stringsx"
 	"github.com/nyaruka/goflow/assets"
 	"github.com/nyaruka/goflow/envs"
 	"github.com/nyaruka/goflow/excellent/types"
@@ -22,6 +24,9 @@ func NewGlobal(asset assets.Global) *Global {
 // Asset returns the underlying asset
 func (g *Global) Asset() assets.Global { return g.Global }
 
+// Secret returns whether the value of this global is secret
+func (g *Global) Secret() bool { return assets.IsSecretGlobal(g.Global) }
+
 // Reference returns a reference to this global
 func (g *Global) Reference() *assets.GlobalReference {
 	return assets.NewGlobalReference(g.Key(), g.Name())
@@ -52,21 +57,57 @@ func (s *GlobalAssets) Get(key string) *Global {
 	return s.byKey[key]
 }
 
-// Context returns the properties available in expressions
+// Redactor returns a redactor which masks the values of secret globals, or nil if there are none
+func (s *GlobalAssets) Redactor() stringsx.Redactor {
+	secrets := make([]string, 0)
+	for _, g := range s.all {
+		if g.Secret() && g.Value() != "" {
+			secrets = append(secrets, g.Value())
+		}
+	}
+	if len(secrets) == 0 {
+		return nil
+	}
+	return stringsx.NewRedactor(RedactionMask, secrets...)
+}
+
+// Context returns the properties available in expressions, with the values of secret globals masked
 func (s *GlobalAssets) Context(env envs.Environment) map[string]types.XValue {
+	return s.context(false)
+}
+
+// RevealedContext returns the properties available in expressions, including the values of secret globals
+func (s *GlobalAssets) RevealedContext(env envs.Environment) map[string]types.XValue {
+	return s.context(true)
+}
+
+func (s *GlobalAssets) context(reveal bool) map[string]types.XValue {
 	entries := make(map[string]types.XValue, len(s.all)+1)
-	entries["__default__"] = types.NewXText(s.format())
+	lines := make([]string, 0, len(s.all))
 
 	for _, g := range s.all {
-		entries[g.Key()] = types.NewXText(g.Value())
+		value := g.Value()
+		if g.Secret() && !reveal {
+			value = RedactionMask
+		}
+
+		entries[g.Key()] = types.NewXText(value)
+		lines = append(lines, fmt.Sprintf("%s: %s", g.Name(), value))
 	}
+
+	entries["__default__"] = types.NewXText(strings.Join(lines, "\n"))
 	return entries
 }
 
-func (s *GlobalAssets) format() string {
-	lines := make([]string, 0, len(s.all))
-	for _, g := range s.all {
-		lines = append(lines, fmt.Sprintf("%s: %s", g.Name(), g.Value()))
-	}
-	return strings.Join(lines, "\n")
+type secretsRevealedKey struct{}
+
+// WithSecretsRevealed returns a copy of ctx in which templates are evaluated with the actual values of secret globals
+func WithSecretsRevealed(ctx context.Context) context.Context {
+	return context.WithValue(ctx, secretsRevealedKey{}, true)
+}
+
+// SecretsRevealed returns whether templates should be evaluated with the actual values of secret globals
+func SecretsRevealed(ctx context.Context) bool {
+	revealed, _ := ctx.Value(secretsRevealedKey{}).(bool)
+	return revealed
 }
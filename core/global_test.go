@@ -15,7 +15,7 @@ import (
 
 func TestGlobals(t *testing.T) {
 	ga1 := static.NewGlobal("org_name", "Org Name", "U-Report")
-	ga2 := static.NewGlobal("access_token", "Access Token", "674372272")
+	ga2 := static.NewSecretGlobal("access_token", "Access Token", "674372272")
 
 	ga := core.NewGlobalAssets([]assets.Global{ga1, ga2})
 
@@ -27,10 +27,38 @@ func TestGlobals(t *testing.T) {
 
 	env := envs.NewBuilder().Build()
 
-	// check use in expressions
+	// check use in expressions where secret values are masked
+	test.AssertXEqual(t, types.NewXObject(map[string]types.XValue{
+		"__default__":  types.NewXText("Org Name: U-Report\nAccess Token: ****************"),
+		"access_token": types.NewXText("****************"),
+		"org_name":     types.NewXText("U-Report"),
+	}), core.Context(env, ga))
+
+	// and where they're revealed
 	test.AssertXEqual(t, types.NewXObject(map[string]types.XValue{
 		"__default__":  types.NewXText("Org Name: U-Report\nAccess Token: 674372272"),
 		"access_token": types.NewXText("674372272"),
 		"org_name":     types.NewXText("U-Report"),
-	}), core.Context(env, ga))
+	}), core.ContextFunc(env, ga.RevealedContext))
+
+	assert.Equal(t, "token=****************", ga.Redactor()("token=674372272"))
+	assert.Nil(t, core.NewGlobalAssets([]assets.Global{ga1}).Redactor())
+
+	ctx := t.Context()
+	assert.False(t, core.SecretsRevealed(ctx))
+	assert.True(t, core.SecretsRevealed(core.WithSecretsRevealed(ctx)))
+}
+
+// a global asset which doesn't implement assets.SecretGlobal
+type plainGlobal struct{}
+
+func (g *plainGlobal) Key() string   { return "org_name" }
+func (g *plainGlobal) Name() string  { return "Org Name" }
+func (g *plainGlobal) Value() string { return "U-Report" }
+
+func TestPlainGlobalsAreNotSecret(t *testing.T) {
+	ga := core.NewGlobalAssets([]assets.Global{&plainGlobal{}})
+
+	assert.False(t, ga.Get("org_name").Secret())
+	assert.Nil(t, ga.Redactor())
 }
@@ -9,12 +9,12 @@ const TypeAudio string = "audio"
 
 // Audio requests a message with an audio attachment
 type Audio struct {
-	baseHint
+	baseMediaHint
 }
 
 // NewAudio creates a new audio hint
 func NewAudio() *Audio {
 	return &Audio{
-		baseHint: newBaseHint(TypeAudio),
+		baseMediaHint: newBaseMediaHint(TypeAudio),
 	}
 }
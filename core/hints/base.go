@@ -2,6 +2,8 @@ package hints
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/nyaruka/goflow/utils"
 )
@@ -30,6 +32,40 @@ func newBaseHint(typeName string) baseHint {
 // Type returns the type of this hint
 func (h *baseHint) Type() string { return h.Type_ }
 
+// MediaHint is a hint which requests a media attachment and can check whether an attachment satisfies it
+type MediaHint interface {
+	Hint
+
+	Accepts(utils.Attachment) bool
+}
+
+// the base of the image, video and audio hints which can optionally be limited by content type and size
+type baseMediaHint struct {
+	baseHint
+
+	ContentTypes []string `json:"content_types,omitempty" validate:"max=10,dive,max=100"`
+	MaxSize      int      `json:"max_size,omitempty"      validate:"min=0"`
+}
+
+func newBaseMediaHint(typeName string) baseMediaHint {
+	return baseMediaHint{baseHint: newBaseHint(typeName)}
+}
+
+// Accepts returns whether the given attachment is of our media type and satisfies any content type or size limits.
+// Attachments whose size isn't known are only rejected by content type.
+func (h *baseMediaHint) Accepts(a utils.Attachment) bool {
+	if a.URL() == "" || a.MediaType() != h.Type_ {
+		return false
+	}
+	if len(h.ContentTypes) > 0 && !slices.ContainsFunc(h.ContentTypes, func(t string) bool { return strings.EqualFold(t, a.ContentType()) }) {
+		return false
+	}
+	if h.MaxSize > 0 && a.Size() > h.MaxSize {
+		return false
+	}
+	return true
+}
+
 //------------------------------------------------------------------------------------------
 // JSON Encoding / Decoding
 //------------------------------------------------------------------------------------------
@@ -5,6 +5,7 @@ import (
 
 	"github.com/nyaruka/gocommon/jsonx"
 	"github.com/nyaruka/goflow/core/hints"
+	"github.com/nyaruka/goflow/utils"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -69,3 +70,35 @@ func TestReadHint(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, `{"type":"digits","count":1}`, string(data))
 }
+
+func TestMediaHints(t *testing.T) {
+	// read image hint with content type and size limits
+	hint, err := hints.Read([]byte(`{"type": "image", "content_types": ["image/jpeg", "image/png"], "max_size": 1000}`))
+	assert.NoError(t, err)
+
+	image := hint.(*hints.Image)
+	assert.Equal(t, []string{"image/jpeg", "image/png"}, image.ContentTypes)
+	assert.Equal(t, 1000, image.MaxSize)
+
+	// marshal back to JSON
+	data, err := jsonx.Marshal(hint)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"image","content_types":["image/jpeg","image/png"],"max_size":1000}`, string(data))
+
+	assert.True(t, image.Accepts(utils.Attachment("image/jpeg:http://example.com/photo.jpg")))
+	assert.True(t, image.Accepts(utils.Attachment("IMAGE/PNG;size=1000:http://example.com/photo.png")))
+	assert.False(t, image.Accepts(utils.Attachment("image/png;size=1001:http://example.com/photo.png")))
+	assert.False(t, image.Accepts(utils.Attachment("image/webp:http://example.com/sticker.webp")))
+	assert.False(t, image.Accepts(utils.Attachment("video/mp4:http://example.com/clip.mp4")))
+	assert.False(t, image.Accepts(utils.Attachment("http://example.com/photo.jpg")))
+
+	// without limits any attachment of the right media type is accepted
+	audio := hints.NewAudio()
+	assert.True(t, audio.Accepts(utils.Attachment("audio/mp4;size=9999999:http://example.com/note.m4a")))
+	assert.True(t, audio.Accepts(utils.Attachment("audio:http://example.com/note.m4a")))
+	assert.False(t, audio.Accepts(utils.Attachment("image/jpeg:http://example.com/photo.jpg")))
+
+	// error if limits are invalid
+	_, err = hints.Read([]byte(`{"type": "video", "max_size": -1}`))
+	assert.EqualError(t, err, "field 'max_size' must be greater than or equal to 0")
+}
@@ -9,12 +9,12 @@ const TypeImage string = "image"
 
 // Image requests a message with an image attachment
 type Image struct {
-	baseHint
+	baseMediaHint
 }
 
 // NewImage creates a new image hint
 func NewImage() *Image {
 	return &Image{
-		baseHint: newBaseHint(TypeImage),
+		baseMediaHint: newBaseMediaHint(TypeImage),
 	}
 }
@@ -9,12 +9,12 @@ const TypeVideo string = "video"
 
 // Video requests a message with an video attachment
 type Video struct {
-	baseHint
+	baseMediaHint
 }
 
 // NewVideo creates a new video hint
 func NewVideo() *Video {
 	return &Video{
-		baseHint: newBaseHint(TypeVideo),
+		baseMediaHint: newBaseMediaHint(TypeVideo),
 	}
 }
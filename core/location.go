@@ -6,6 +6,7 @@ import (
 
 	"github.com/nyaruka/goflow/assets"
 	"github.com/nyaruka/goflow/envs"
+	"github.com/nyaruka/goflow/utils"
 )
 
 // location levels which can be field types
@@ -71,6 +72,17 @@ func (s *LocationAssets) FindLocationsFuzzy(env envs.Environment, text string, l
 	return nil
 }
 
+// FindLocationsByPoint returns locations with the given level and parent (optional) whose boundaries contain the
+// given point
+func (s *LocationAssets) FindLocationsByPoint(env envs.Environment, point utils.GeoPoint, level envs.LocationLevel, parent *envs.Location) []*envs.Location {
+	if len(s.hierarchies) > 0 {
+		if h, ok := s.hierarchies[0].(assets.PointLocationHierarchy); ok {
+			return h.FindByPoint(point, level, parent)
+		}
+	}
+	return nil
+}
+
 func (s *LocationAssets) LookupLocation(path envs.LocationPath) *envs.Location {
 	if len(s.hierarchies) > 0 {
 		return s.hierarchies[0].FindByPath(path)
@@ -78,4 +90,4 @@ func (s *LocationAssets) LookupLocation(path envs.LocationPath) *envs.Location {
 	return nil
 }
 
-var _ envs.LocationResolver = (*LocationAssets)(nil)
+var _ envs.PointLocationResolver = (*LocationAssets)(nil)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/nyaruka/gocommon/i18n"
 	"github.com/nyaruka/gocommon/jsonx"
@@ -25,6 +26,11 @@ const (
 	MaxQuickReplyTextLength  = 64
 	MaxQuickReplyExtraLength = 1000
 
+	// max lengths of interactive buttons and list rows
+	MaxButtonTextLength         = 64
+	MaxButtonPayloadLength      = 1000
+	MaxListRowDescriptionLength = 1000
+
 	UnsendableReasonNoRoute         UnsendableReason = "no_route"         // no sendable channel+URN pair
 	UnsendableReasonContactBlocked  UnsendableReason = "contact_blocked"  // contact is blocked
 	UnsendableReasonContactStopped  UnsendableReason = "contact_stopped"  // contact is stopped
@@ -52,9 +58,12 @@ type MsgOut struct {
 	BaseMsg
 
 	QuickReplies_     []QuickReply     `json:"quick_replies,omitempty"`
+	Buttons_          []MsgButton      `json:"buttons,omitempty"`
+	List_             *MsgList         `json:"list,omitempty"`
 	Templating_       *MsgTemplating   `json:"templating,omitempty"`
 	Locale_           i18n.Locale      `json:"locale,omitempty"`
 	UnsendableReason_ UnsendableReason `json:"unsendable_reason,omitempty"`
+	SendAfter_        *time.Time       `json:"send_after,omitempty"`
 }
 
 // NewMsgIn creates a new incoming message
@@ -81,6 +90,8 @@ func NewMsgOut(urn urns.URN, channel *assets.ChannelReference, content *MsgConte
 			Attachments_: content.Attachments,
 		},
 		QuickReplies_:     content.QuickReplies,
+		Buttons_:          content.Buttons,
+		List_:             content.List,
 		Templating_:       templating,
 		Locale_:           locale,
 		UnsendableReason_: reason,
@@ -131,6 +142,12 @@ func (m *MsgIn) Payload() json.RawMessage { return m.Payload_ }
 // QuickReplies returns the quick replies of this outgoing message
 func (m *MsgOut) QuickReplies() []QuickReply { return m.QuickReplies_ }
 
+// Buttons returns the reply and call-to-action buttons of this outgoing message
+func (m *MsgOut) Buttons() []MsgButton { return m.Buttons_ }
+
+// List returns the interactive list of this outgoing message (if any)
+func (m *MsgOut) List() *MsgList { return m.List_ }
+
 // Templating returns the templating to use to send this message (if any)
 func (m *MsgOut) Templating() *MsgTemplating { return m.Templating_ }
 
@@ -140,6 +157,12 @@ func (m *MsgOut) Locale() i18n.Locale { return m.Locale_ }
 // UnsendableReason returns the reason this message can't be sent (if any)
 func (m *MsgOut) UnsendableReason() UnsendableReason { return m.UnsendableReason_ }
 
+// SendAfter returns the earliest time this message can be sent (if it has been deferred)
+func (m *MsgOut) SendAfter() *time.Time { return m.SendAfter_ }
+
+// SetSendAfter defers this message until the given time
+func (m *MsgOut) SetSendAfter(t time.Time) { m.SendAfter_ = &t }
+
 type TemplatingVariable struct {
 	Type  string `json:"type"`
 	Value string `json:"value"`
@@ -244,15 +267,45 @@ func (q *QuickReply) UnmarshalJSON(d []byte) error {
 	return jsonx.Unmarshal(d, (*alias)(q))
 }
 
+// MsgButton is an interactive button on an outgoing message. A reply button sends its payload back as the payload
+// of the contact's reply, and a url button opens its URL.
+type MsgButton struct {
+	Type    string `json:"type"`
+	Text    string `json:"text"`
+	Payload string `json:"payload,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// MsgList is an interactive list on an outgoing message which is opened by a button and lets the contact pick a row
+type MsgList struct {
+	Button   string           `json:"button"`
+	Sections []MsgListSection `json:"sections"`
+}
+
+// MsgListSection is a titled section of rows in an interactive list
+type MsgListSection struct {
+	Title string       `json:"title,omitempty"`
+	Rows  []MsgListRow `json:"rows"`
+}
+
+// MsgListRow is a row in an interactive list which sends its payload back when picked
+type MsgListRow struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Payload     string `json:"payload,omitempty"`
+}
+
 // MsgContent is message content in a particular language
 type MsgContent struct {
 	Text         string             `json:"text"`
 	Attachments  []utils.Attachment `json:"attachments,omitempty"`
 	QuickReplies []QuickReply       `json:"quick_replies,omitempty"`
+	Buttons      []MsgButton        `json:"buttons,omitempty"`
+	List         *MsgList           `json:"list,omitempty"`
 }
 
 func (c *MsgContent) Empty() bool {
-	return c.Text == "" && len(c.Attachments) == 0 && len(c.QuickReplies) == 0
+	return c.Text == "" && len(c.Attachments) == 0 && len(c.QuickReplies) == 0 && len(c.Buttons) == 0 && c.List == nil
 }
 
 type BroadcastUUID uuids.UUID
@@ -294,6 +347,12 @@ func (b BroadcastTranslations) ForContact(e envs.Environment, c *Contact, baseLa
 			if len(content.QuickReplies) == 0 && len(trans.QuickReplies) > 0 {
 				content.QuickReplies = trans.QuickReplies
 			}
+			if len(content.Buttons) == 0 && len(trans.Buttons) > 0 {
+				content.Buttons = trans.Buttons
+			}
+			if content.List == nil && trans.List != nil {
+				content.List = trans.List
+			}
 		}
 	}
 
@@ -84,6 +84,33 @@ func TestMsgOut(t *testing.T) {
 		"attachments": ["image/jpeg:https://example.com/test.jpg", "audio/mp3:https://example.com/test.mp3"],
 		"locale": "eng-US"
 	}`), marshaled, "JSON mismatch")
+
+	// with interactive buttons and a list
+	msg = core.NewMsgOut(
+		urns.URN("tel:+1234567890"),
+		nil,
+		&core.MsgContent{
+			Text:    "Ready?",
+			Buttons: []core.MsgButton{{Type: "reply", Text: "Yes", Payload: "yes"}, {Type: "url", Text: "More", URL: "https://example.com"}},
+			List:    &core.MsgList{Button: "Options", Sections: []core.MsgListSection{{Title: "Help", Rows: []core.MsgListRow{{Title: "Agent", Description: "Talk to someone", Payload: "agent"}}}}},
+		},
+		nil,
+		"eng-US",
+		"",
+	)
+	assert.Equal(t, []core.MsgButton{{Type: "reply", Text: "Yes", Payload: "yes"}, {Type: "url", Text: "More", URL: "https://example.com"}}, msg.Buttons())
+	assert.Equal(t, "Options", msg.List().Button)
+
+	marshaled, err = jsonx.Marshal(msg)
+	require.NoError(t, err)
+
+	test.AssertEqualJSON(t, []byte(`{
+		"urn": "tel:+1234567890",
+		"text": "Ready?",
+		"buttons": [{"type": "reply", "text": "Yes", "payload": "yes"}, {"type": "url", "text": "More", "url": "https://example.com"}],
+		"list": {"button": "Options", "sections": [{"title": "Help", "rows": [{"title": "Agent", "description": "Talk to someone", "payload": "agent"}]}]},
+		"locale": "eng-US"
+	}`), marshaled, "JSON mismatch")
 }
 
 func TestIVRMsgOut(t *testing.T) {
@@ -115,6 +142,8 @@ func TestMsgContent(t *testing.T) {
 	assert.False(t, (&core.MsgContent{Text: "hi"}).Empty())
 	assert.False(t, (&core.MsgContent{Attachments: []utils.Attachment{"image:https://test.jpg"}}).Empty())
 	assert.False(t, (&core.MsgContent{QuickReplies: []core.QuickReply{{Text: "Ok"}}}).Empty())
+	assert.False(t, (&core.MsgContent{Buttons: []core.MsgButton{{Type: "reply", Text: "Ok", Payload: "ok"}}}).Empty())
+	assert.False(t, (&core.MsgContent{List: &core.MsgList{Button: "Pick", Sections: []core.MsgListSection{{Rows: []core.MsgListRow{{Title: "Ok"}}}}}}).Empty())
 
 	// can unmarshal from object
 	var c core.MsgContent
@@ -199,6 +228,17 @@ func TestBroadcastTranslations(t *testing.T) {
 			expectedContent: &core.MsgContent{Text: "Muraho", Attachments: []utils.Attachment{"image/jpeg:https://example.com/hola.jpg"}, QuickReplies: []core.QuickReply{{Text: "Yes"}, {Text: "No"}}},
 			expectedLocale:  "kin-US",
 		},
+		{ // 6: merges buttons and lists from different translations
+			env: envs.NewBuilder().WithAllowedLanguages("eng", "spa").WithDefaultCountry("US").Build(),
+			translations: core.BroadcastTranslations{
+				"eng": &core.MsgContent{Text: "Hello", Buttons: []core.MsgButton{{Type: "reply", Text: "Yes", Payload: "yes"}}, List: &core.MsgList{Button: "Help"}},
+				"spa": &core.MsgContent{Text: "Hola", Buttons: []core.MsgButton{{Type: "reply", Text: "Si", Payload: "yes"}}},
+			},
+			baseLanguage:    "eng",
+			contactLanguage: "spa",
+			expectedContent: &core.MsgContent{Text: "Hola", Buttons: []core.MsgButton{{Type: "reply", Text: "Si", Payload: "yes"}}, List: &core.MsgList{Button: "Help"}},
+			expectedLocale:  "spa-US",
+		},
 	}
 
 	for i, tc := range tcs {
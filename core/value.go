@@ -1,6 +1,10 @@
 package core
 
 import (
+	"bytes"
+	"encoding/json"
+	"slices"
+
 	"github.com/nyaruka/goflow/envs"
 	"github.com/nyaruka/goflow/excellent/types"
 )
@@ -13,6 +17,9 @@ type Value struct {
 	State    envs.LocationPath `json:"state,omitempty"`
 	District envs.LocationPath `json:"district,omitempty"`
 	Ward     envs.LocationPath `json:"ward,omitempty"`
+	Boolean  *types.XBoolean   `json:"boolean,omitempty"`
+	Choices  []string          `json:"choices,omitempty"`
+	JSON     json.RawMessage   `json:"json,omitempty"`
 }
 
 // NewValue creates an empty value
@@ -38,6 +45,8 @@ func (v *Value) Equals(o *Value) bool {
 
 	dateEqual := (v.Datetime == nil && o.Datetime == nil) || (v.Datetime != nil && o.Datetime != nil && v.Datetime.Equals(o.Datetime))
 	numEqual := (v.Number == nil && o.Number == nil) || (v.Number != nil && o.Number != nil && v.Number.Equals(o.Number))
+	boolEqual := (v.Boolean == nil && o.Boolean == nil) || (v.Boolean != nil && o.Boolean != nil && v.Boolean.Equals(o.Boolean))
 
-	return v.Text.Equals(o.Text) && dateEqual && numEqual && v.State == o.State && v.District == o.District && v.Ward == o.Ward
+	return v.Text.Equals(o.Text) && dateEqual && numEqual && v.State == o.State && v.District == o.District && v.Ward == o.Ward &&
+		boolEqual && slices.Equal(v.Choices, o.Choices) && bytes.Equal(v.JSON, o.JSON)
 }
@@ -3,6 +3,7 @@ package envs
 import (
 	"strings"
 
+	"github.com/nyaruka/gocommon/i18n"
 	"github.com/nyaruka/gocommon/stringsx"
 	"golang.org/x/text/unicode/norm"
 )
@@ -13,9 +14,15 @@ const (
 	CollationDefault        Collation = "default"
 	CollationConfusables    Collation = "confusables"
 	CollationArabicVariants Collation = "arabic_variants"
+	CollationTransliterate  Collation = "transliterate"
+
+	// CollationPhonetic replaces each word with a single phonetic key so that words which sound alike match. For most
+	// languages that's a simplified Metaphone key, which is weaker than Double Metaphone as it doesn't consider
+	// alternate pronunciations. For Swahili it's a Soundex-like key.
+	CollationPhonetic Collation = "phonetic"
 )
 
-type collateTransformer func(string) string
+type collateTransformer func(Environment, string) string
 
 // Based on https://en.wikipedia.org/wiki/Persian_alphabet#Deviations_from_the_Arabic_script
 // and feedback from UNICEF Afghanistan
@@ -46,15 +53,43 @@ var arabicVariants = map[rune]rune{
 }
 
 var transformers = map[Collation]collateTransformer{
-	CollationDefault: func(s string) string {
+	CollationDefault: func(env Environment, s string) string {
 		return strings.ToLower(s)
 	},
-	CollationConfusables: func(s string) string {
+	CollationConfusables: func(env Environment, s string) string {
 		return strings.ToLower(stringsx.Skeleton(s))
 	},
-	CollationArabicVariants: func(s string) string {
+	CollationArabicVariants: func(env Environment, s string) string {
 		return strings.ToLower(replaceRunes(norm.NFKC.String(s), arabicVariants))
 	},
+	CollationTransliterate: func(env Environment, s string) string {
+		return transliterate(s)
+	},
+	CollationPhonetic: func(env Environment, s string) string {
+		return phoneticKeys(env, transliterate(s))
+	},
+}
+
+// phonetic key algorithms by language, with a simplified Metaphone used for all other languages
+var phoneticAlgorithms = map[i18n.Language]func(string) string{
+	"swa": swahiliSoundex,
+}
+
+// replaces each word in the given transliterated string with its phonetic key, or leaves it as is if it doesn't
+// have a key, i.e. isn't written with Latin letters
+func phoneticKeys(env Environment, s string) string {
+	algorithm := phoneticAlgorithms[env.DefaultLanguage()]
+	if algorithm == nil {
+		algorithm = simpleMetaphone
+	}
+
+	words := strings.Fields(s)
+	for i, word := range words {
+		if key := algorithm(word); key != "" {
+			words[i] = key
+		}
+	}
+	return strings.Join(words, " ")
 }
 
 // CollateEquals returns true if the given strings are equal in the given environment's collation
@@ -64,7 +99,7 @@ func CollateEquals(env Environment, s, t string) bool {
 
 // CollateTransform transforms the given string into it's form to be used for collation.
 func CollateTransform(env Environment, s string) string {
-	return transformers[env.InputCollation()](s)
+	return transformers[env.InputCollation()](env, s)
 }
 
 func replaceRunes(s string, mapping map[rune]rune) string {
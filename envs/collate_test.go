@@ -51,6 +51,27 @@ func TestCollation(t *testing.T) {
 		{envs.CollationArabicVariants, "\u0622", "\u0627", map[string]bool{}},
 		{envs.CollationArabicVariants, "\uFE8F\uFEDD\uFBFC", "\u0628\u0644\u06CC", map[string]bool{}}, // Arabic Presentation forms
 		{envs.CollationArabicVariants, "YES", "yes", map[string]bool{"yes": true, "no": false}},
+		{envs.CollationTransliterate, "Привет", "privet", map[string]bool{
+			"privet":  true,
+			"PRIVET":  true,
+			"privyet": false,
+		}},
+		{envs.CollationTransliterate, "Щука Ёж", "shchuka ezh", map[string]bool{}},
+		{envs.CollationTransliterate, "नमस्ते", "namaste", map[string]bool{"Namaste": true}},
+		{envs.CollationTransliterate, "हाँ नहीं १२", "han nahin 12", map[string]bool{}},
+		{envs.CollationTransliterate, "ሰላም", "selam", map[string]bool{"Selam": true}},
+		{envs.CollationTransliterate, "አዲስ አበባ", "adis abeba", map[string]bool{}},
+		{envs.CollationTransliterate, "Café Ñandú", "cafe nandu", map[string]bool{"cafe nandu": true}},
+		{envs.CollationPhonetic, "Please register", "pls rjstr", map[string]bool{
+			"pleese rejister": true,
+			"PLZ REGISTER":    true,
+			"please stop":     false,
+		}},
+		{envs.CollationPhonetic, "Knight phone school", "nt fn skl", map[string]bool{"nite fone skool": true}},
+		{envs.CollationPhonetic, "Thanks judge xray", "0nks jj sr", map[string]bool{}},
+		{envs.CollationPhonetic, "Thomas", "0ms", map[string]bool{"tomas": false}}, // only one key per word
+		{envs.CollationPhonetic, "Привет", "prft", map[string]bool{"privet": true}},
+		{envs.CollationPhonetic, "123 📴", "123 📴", map[string]bool{"123 📴": true}},
 	}
 
 	for _, tc := range tcs {
@@ -63,4 +84,13 @@ func TestCollation(t *testing.T) {
 			assert.Equal(t, eqResult, envs.CollateEquals(env, tc.input, eqStr))
 		}
 	}
+
+	// phonetic collation in Swahili uses Soundex-like keys
+	env := envs.NewBuilder().WithInputCollation(envs.CollationPhonetic).WithAllowedLanguages("swa").Build()
+
+	assert.Equal(t, "l6 m63 a365", envs.CollateTransform(env, "Rehema Mwanaisha Ismail"))
+	assert.True(t, envs.CollateEquals(env, "Rehema", "Lehema"))
+	assert.True(t, envs.CollateEquals(env, "Mwanaisha", "Mwanaesha"))
+	assert.True(t, envs.CollateEquals(env, "Halima", "harima"))
+	assert.False(t, envs.CollateEquals(env, "Halima", "Juma"))
 }
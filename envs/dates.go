@@ -169,6 +169,10 @@ func parseDate(env Environment, str string) (dates.Date, string, error) {
 	}
 
 	// otherwise, try to parse according to their env settings
+	return parseFormattedDate(env, str)
+}
+
+func parseFormattedDate(env Environment, str string) (dates.Date, string, error) {
 	currentYear := dates.Now().Year()
 
 	switch env.DateFormat() {
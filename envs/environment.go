@@ -53,8 +53,11 @@ type Environment interface {
 	DefaultCountry() i18n.Country
 	NumberFormat() *NumberFormat
 	InputCollation() Collation
+	InputNumberWords() bool
+	InputRelativeDates() bool
 	RedactionPolicy() RedactionPolicy
 	ObfuscationKey() [4]uint32
+	SendWindows() SendWindows
 
 	// non-marshalled properties
 	LocationResolver() LocationResolver
@@ -67,17 +70,20 @@ type Environment interface {
 }
 
 type environment struct {
-	dateFormat       DateFormat
-	timeFormat       TimeFormat
-	timezone         *time.Location
-	allowedLanguages []i18n.Language
-	defaultCountry   i18n.Country
-	numberFormat     *NumberFormat
-	redactionPolicy  RedactionPolicy
-	obfuscationKey   [4]uint32
-	inputCollation   Collation
-	locationResolver LocationResolver
-	promptResolver   PromptResolver
+	dateFormat         DateFormat
+	timeFormat         TimeFormat
+	timezone           *time.Location
+	allowedLanguages   []i18n.Language
+	defaultCountry     i18n.Country
+	numberFormat       *NumberFormat
+	redactionPolicy    RedactionPolicy
+	obfuscationKey     [4]uint32
+	sendWindows        SendWindows
+	inputCollation     Collation
+	inputNumberWords   bool
+	inputRelativeDates bool
+	locationResolver   LocationResolver
+	promptResolver     PromptResolver
 }
 
 func (e *environment) DateFormat() DateFormat                   { return e.dateFormat }
@@ -87,8 +93,11 @@ func (e *environment) AllowedLanguages() []i18n.Language        { return e.allow
 func (e *environment) DefaultCountry() i18n.Country             { return e.defaultCountry }
 func (e *environment) NumberFormat() *NumberFormat              { return e.numberFormat }
 func (e *environment) InputCollation() Collation                { return e.inputCollation }
+func (e *environment) InputNumberWords() bool                   { return e.inputNumberWords }
+func (e *environment) InputRelativeDates() bool                 { return e.inputRelativeDates }
 func (e *environment) RedactionPolicy() RedactionPolicy         { return e.redactionPolicy }
 func (e *environment) ObfuscationKey() [4]uint32                { return e.obfuscationKey }
+func (e *environment) SendWindows() SendWindows                 { return e.sendWindows }
 func (e *environment) LocationResolver() LocationResolver       { return e.locationResolver }
 func (e *environment) LLMPrompt(name string) *template.Template { return e.promptResolver(name) }
 
@@ -113,15 +122,18 @@ func (e *environment) Now() time.Time { return dates.Now().In(e.Timezone()) }
 //------------------------------------------------------------------------------------------
 
 type envEnvelope struct {
-	DateFormat       DateFormat      `json:"date_format" validate:"date_format"`
-	TimeFormat       TimeFormat      `json:"time_format" validate:"time_format"`
-	Timezone         string          `json:"timezone"`
-	AllowedLanguages []i18n.Language `json:"allowed_languages,omitempty" validate:"omitempty,dive,language"`
-	NumberFormat     *NumberFormat   `json:"number_format,omitempty"`
-	DefaultCountry   i18n.Country    `json:"default_country,omitempty" validate:"omitempty,country"`
-	InputCollation   Collation       `json:"input_collation" validate:"eq=default|eq=confusables|eq=arabic_variants"`
-	RedactionPolicy  RedactionPolicy `json:"redaction_policy" validate:"omitempty,eq=none|eq=urns"`
-	ObfuscationKey   [4]uint32       `json:"obfuscation_key"`
+	DateFormat         DateFormat      `json:"date_format" validate:"date_format"`
+	TimeFormat         TimeFormat      `json:"time_format" validate:"time_format"`
+	Timezone           string          `json:"timezone"`
+	AllowedLanguages   []i18n.Language `json:"allowed_languages,omitempty" validate:"omitempty,dive,language"`
+	NumberFormat       *NumberFormat   `json:"number_format,omitempty"`
+	DefaultCountry     i18n.Country    `json:"default_country,omitempty" validate:"omitempty,country"`
+	InputCollation     Collation       `json:"input_collation" validate:"eq=default|eq=confusables|eq=arabic_variants|eq=transliterate|eq=phonetic"`
+	InputNumberWords   bool            `json:"input_number_words,omitempty"`
+	InputRelativeDates bool            `json:"input_relative_dates,omitempty"`
+	RedactionPolicy    RedactionPolicy `json:"redaction_policy" validate:"omitempty,eq=none|eq=urns"`
+	ObfuscationKey     [4]uint32       `json:"obfuscation_key"`
+	SendWindows        SendWindows     `json:"send_windows,omitempty"`
 }
 
 // ReadEnvironment reads an environment from the given JSON
@@ -143,8 +155,11 @@ func ReadEnvironment(data []byte) (Environment, error) {
 		env.numberFormat = envelope.NumberFormat
 	}
 	env.inputCollation = envelope.InputCollation
+	env.inputNumberWords = envelope.InputNumberWords
+	env.inputRelativeDates = envelope.InputRelativeDates
 	env.redactionPolicy = envelope.RedactionPolicy
 	env.obfuscationKey = envelope.ObfuscationKey
+	env.sendWindows = envelope.SendWindows
 
 	tz, err := time.LoadLocation(envelope.Timezone)
 	if err != nil {
@@ -157,15 +172,18 @@ func ReadEnvironment(data []byte) (Environment, error) {
 
 func (e *environment) toEnvelope() *envEnvelope {
 	return &envEnvelope{
-		DateFormat:       e.dateFormat,
-		TimeFormat:       e.timeFormat,
-		Timezone:         e.timezone.String(),
-		AllowedLanguages: e.allowedLanguages,
-		DefaultCountry:   e.defaultCountry,
-		NumberFormat:     e.numberFormat,
-		InputCollation:   e.inputCollation,
-		RedactionPolicy:  e.redactionPolicy,
-		ObfuscationKey:   e.obfuscationKey,
+		DateFormat:         e.dateFormat,
+		TimeFormat:         e.timeFormat,
+		Timezone:           e.timezone.String(),
+		AllowedLanguages:   e.allowedLanguages,
+		DefaultCountry:     e.defaultCountry,
+		NumberFormat:       e.numberFormat,
+		InputCollation:     e.inputCollation,
+		InputNumberWords:   e.inputNumberWords,
+		InputRelativeDates: e.inputRelativeDates,
+		RedactionPolicy:    e.redactionPolicy,
+		ObfuscationKey:     e.obfuscationKey,
+		SendWindows:        e.sendWindows,
 	}
 }
 
@@ -238,6 +256,16 @@ func (b *EnvironmentBuilder) WithInputCollation(col Collation) *EnvironmentBuild
 	return b
 }
 
+func (b *EnvironmentBuilder) WithInputNumberWords(enabled bool) *EnvironmentBuilder {
+	b.env.inputNumberWords = enabled
+	return b
+}
+
+func (b *EnvironmentBuilder) WithInputRelativeDates(enabled bool) *EnvironmentBuilder {
+	b.env.inputRelativeDates = enabled
+	return b
+}
+
 func (b *EnvironmentBuilder) WithRedactionPolicy(policy RedactionPolicy) *EnvironmentBuilder {
 	b.env.redactionPolicy = policy
 	return b
@@ -248,6 +276,11 @@ func (b *EnvironmentBuilder) WithObfuscationKey(key [4]uint32) *EnvironmentBuild
 	return b
 }
 
+func (b *EnvironmentBuilder) WithSendWindows(windows SendWindows) *EnvironmentBuilder {
+	b.env.sendWindows = windows
+	return b
+}
+
 func (b *EnvironmentBuilder) WithLocationResolver(resolver LocationResolver) *EnvironmentBuilder {
 	b.env.locationResolver = resolver
 	return b
@@ -45,7 +45,7 @@ func TestEnvironmentMarshaling(t *testing.T) {
 	assert.Error(t, err)
 
 	// but can create with any valid input collation
-	for _, col := range []envs.Collation{envs.CollationDefault, envs.CollationConfusables, envs.CollationArabicVariants} {
+	for _, col := range []envs.Collation{envs.CollationDefault, envs.CollationConfusables, envs.CollationArabicVariants, envs.CollationTransliterate, envs.CollationPhonetic} {
 		env, err := envs.ReadEnvironment([]byte(`{"date_format": "DD-MM-YYYY", "time_format": "tt:mm", "input_collation": "` + string(col) + `"}`))
 		assert.NoError(t, err)
 		assert.Equal(t, col, env.InputCollation())
@@ -63,6 +63,9 @@ func TestEnvironmentMarshaling(t *testing.T) {
 	assert.Nil(t, env.LocationResolver())
 	assert.Equal(t, envs.RedactionPolicyNone, env.RedactionPolicy())
 	assert.Equal(t, [4]uint32{0xA3B1C, 0xD2E3F, 0x1A2B3, 0xC0FFEE}, env.ObfuscationKey())
+	assert.Nil(t, env.SendWindows())
+	assert.False(t, env.InputNumberWords())
+	assert.False(t, env.InputRelativeDates())
 
 	// an explicit null number format doesn't clear the default
 	env, err = envs.ReadEnvironment([]byte(`{"number_format": null}`))
@@ -77,7 +80,10 @@ func TestEnvironmentMarshaling(t *testing.T) {
 		"default_country": "RW", 
 		"timezone": "Africa/Kigali",
 		"redaction_policy": "urns",
-		"obfuscation_key": [123456, 234567, 345678, 456789]
+		"obfuscation_key": [123456, 234567, 345678, 456789],
+		"send_windows": [{"start": "08:00", "end": "20:00"}],
+		"input_number_words": true,
+		"input_relative_dates": true
 	}`))
 	assert.NoError(t, err)
 	assert.Equal(t, envs.DateFormatDayMonthYear, env.DateFormat())
@@ -90,11 +96,18 @@ func TestEnvironmentMarshaling(t *testing.T) {
 	assert.Equal(t, envs.CollationDefault, env.InputCollation())
 	assert.Equal(t, envs.RedactionPolicyURNs, env.RedactionPolicy())
 	assert.Equal(t, [4]uint32{123456, 234567, 345678, 456789}, env.ObfuscationKey())
+	assert.Len(t, env.SendWindows(), 1)
+	assert.True(t, env.InputNumberWords())
+	assert.True(t, env.InputRelativeDates())
 	assert.Nil(t, env.LocationResolver())
 
 	data, err := jsonx.Marshal(env)
 	require.NoError(t, err)
-	assert.Equal(t, string(data), `{"date_format":"DD-MM-YYYY","time_format":"tt:mm:ss","timezone":"Africa/Kigali","allowed_languages":["eng","fra"],"number_format":{"decimal_symbol":".","digit_grouping_symbol":","},"default_country":"RW","input_collation":"default","redaction_policy":"urns","obfuscation_key":[123456,234567,345678,456789]}`)
+	assert.Equal(t, string(data), `{"date_format":"DD-MM-YYYY","time_format":"tt:mm:ss","timezone":"Africa/Kigali","allowed_languages":["eng","fra"],"number_format":{"decimal_symbol":".","digit_grouping_symbol":","},"default_country":"RW","input_collation":"default","input_number_words":true,"input_relative_dates":true,"redaction_policy":"urns","obfuscation_key":[123456,234567,345678,456789],"send_windows":[{"start":"08:00","end":"20:00"}]}`)
+
+	// can't create with invalid send windows
+	_, err = envs.ReadEnvironment([]byte(`{"send_windows": [{"start": "08:00", "end": "xx"}]}`))
+	assert.Error(t, err)
 }
 
 func TestEnvironmentBuilder(t *testing.T) {
@@ -110,6 +123,8 @@ func TestEnvironmentBuilder(t *testing.T) {
 		WithNumberFormat(&envs.NumberFormat{DecimalSymbol: "'"}).
 		WithRedactionPolicy(envs.RedactionPolicyURNs).
 		WithObfuscationKey([4]uint32{123456, 234567, 345678, 456789}).
+		WithInputNumberWords(true).
+		WithInputRelativeDates(true).
 		WithPromptResolver(envs.NewPromptResolver(map[string]*template.Template{"hello": template.Must(template.New("").Parse("Say hello"))})).
 		Build()
 
@@ -121,6 +136,8 @@ func TestEnvironmentBuilder(t *testing.T) {
 	assert.Equal(t, &envs.NumberFormat{DecimalSymbol: "'"}, env.NumberFormat())
 	assert.Equal(t, envs.RedactionPolicyURNs, env.RedactionPolicy())
 	assert.Equal(t, [4]uint32{123456, 234567, 345678, 456789}, env.ObfuscationKey())
+	assert.True(t, env.InputNumberWords())
+	assert.True(t, env.InputRelativeDates())
 	assert.Nil(t, env.LocationResolver())
 	assert.Nil(t, env.LLMPrompt("xxxx"))
 	assert.NotNil(t, env.LLMPrompt("hello"))
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/nyaruka/goflow/envs"
+	"github.com/nyaruka/goflow/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,6 +32,8 @@ var locationHierarchyJSON = `
 			"children": [
 				{
 					"name": "Gasabo",
+					"centroid": [30.15, -1.925],
+					"boundary": {"type": "Polygon", "coordinates": [[[30.05, -2.0], [30.25, -2.0], [30.25, -1.85], [30.05, -1.85], [30.05, -2.0]]]},
 					"children": [
 						{
 							"id": "575743222",
@@ -44,6 +47,8 @@ var locationHierarchyJSON = `
 				},
 				{
 					"name": "Nyarugenge",
+					"centroid": [30.05, -1.95],
+					"bbox": [29.98, -2.0, 30.1, -1.9],
 					"children": []
 				}
 			]
@@ -91,6 +96,19 @@ func TestLocationHierarchy(t *testing.T) {
 	assert.Equal(t, envs.LocationPath("Rwanda > Kigali City > Gasabo"), gasabo.Path())
 	assert.Equal(t, kigali, gasabo.Parent())
 	assert.Equal(t, 2, len(gasabo.Children()))
+	assert.Nil(t, kigali.Boundary())
+	assert.True(t, gasabo.Boundary().Contains(utils.GeoPoint{Lat: -1.93, Lng: 30.1}))
+	assert.False(t, gasabo.Boundary().Contains(utils.GeoPoint{Lat: -1.95, Lng: 30.04}))
+	assert.Equal(t, &utils.GeoPoint{Lat: -1.925, Lng: 30.15}, gasabo.Centroid())
+	assert.Equal(t, &utils.GeoBBox{Min: utils.GeoPoint{Lat: -2.0, Lng: 30.05}, Max: utils.GeoPoint{Lat: -1.85, Lng: 30.25}}, gasabo.BBox()) // from boundary
+	assert.True(t, gasabo.Contains(utils.GeoPoint{Lat: -1.93, Lng: 30.1}))
+	assert.Nil(t, kigali.Centroid())
+	assert.Nil(t, kigali.BBox())
+	assert.False(t, kigali.Contains(utils.GeoPoint{Lat: -1.93, Lng: 30.1}))
+
+	nyarugenge := kigali.Children()[1]
+	assert.Equal(t, &utils.GeoBBox{Min: utils.GeoPoint{Lat: -2.0, Lng: 29.98}, Max: utils.GeoPoint{Lat: -1.9, Lng: 30.1}}, nyarugenge.BBox())
+	assert.False(t, nyarugenge.Contains(utils.GeoPoint{Lat: -1.95, Lng: 30.04})) // no boundary
 
 	ndera := gasabo.Children()[1]
 	assert.Equal(t, envs.LocationLevel(3), ndera.Level())
@@ -112,6 +130,12 @@ func TestLocationHierarchy(t *testing.T) {
 	assert.Equal(t, []*envs.Location{}, hierarchy.FindByName(env, "kigari", envs.LocationLevel(2), nil))    // wrong level
 	assert.Equal(t, []*envs.Location{}, hierarchy.FindByName(env, "kigari", envs.LocationLevel(2), gasabo)) // wrong parent
 
+	assert.Equal(t, []*envs.Location{gasabo}, hierarchy.FindByPoint(utils.GeoPoint{Lat: -1.93, Lng: 30.1}, envs.LocationLevel(2), nil))
+	assert.Equal(t, []*envs.Location{gasabo}, hierarchy.FindByPoint(utils.GeoPoint{Lat: -1.93, Lng: 30.1}, envs.LocationLevel(2), kigali))
+	assert.Equal(t, []*envs.Location{}, hierarchy.FindByPoint(utils.GeoPoint{Lat: -1.93, Lng: 30.1}, envs.LocationLevel(1), nil))    // no boundary
+	assert.Equal(t, []*envs.Location{}, hierarchy.FindByPoint(utils.GeoPoint{Lat: -1.95, Lng: 30.04}, envs.LocationLevel(2), nil))   // outside
+	assert.Equal(t, []*envs.Location{}, hierarchy.FindByPoint(utils.GeoPoint{Lat: -1.93, Lng: 30.1}, envs.LocationLevel(2), gasabo)) // wrong parent
+
 	assert.Equal(t, rwanda, hierarchy.FindByPath(envs.LocationPath("RWANDA")))
 	assert.Equal(t, kigali, hierarchy.FindByPath("RWANDA > KIGALI 	 CITY"))
 	assert.Equal(t, kigali, hierarchy.FindByPath("RWANDA > KIGALI CITY."))
@@ -21,6 +21,13 @@ type LocationResolver interface {
 	LookupLocation(LocationPath) *Location
 }
 
+// PointLocationResolver is a location resolver which can also find locations by points inside their boundaries
+type PointLocationResolver interface {
+	LocationResolver
+
+	FindLocationsByPoint(Environment, utils.GeoPoint, LocationLevel, *Location) []*Location
+}
+
 const (
 	LocationPathSeparator = ">"
 )
@@ -68,6 +75,9 @@ type Location struct {
 	name     string
 	path     LocationPath
 	aliases  []string
+	centroid *utils.GeoPoint
+	bbox     *utils.GeoBBox
+	boundary *utils.GeoBoundary
 	parent   *Location
 	children []*Location
 }
@@ -89,6 +99,15 @@ func (l *Location) Path() LocationPath { return l.path }
 // Aliases gets the aliases of this location
 func (l *Location) Aliases() []string { return l.aliases }
 
+// Centroid gets the centroid of this location if it has one
+func (l *Location) Centroid() *utils.GeoPoint { return l.centroid }
+
+// BBox gets the bounding box of this location if it has one
+func (l *Location) BBox() *utils.GeoBBox { return l.bbox }
+
+// Boundary gets the boundary of this location if it has one
+func (l *Location) Boundary() *utils.GeoBoundary { return l.boundary }
+
 // Parent gets the parent of this location
 func (l *Location) Parent() *Location { return l.parent }
 
@@ -97,6 +116,17 @@ func (l *Location) Children() []*Location { return l.children }
 
 func (l *Location) String() string { return string(l.path) }
 
+// Contains returns whether the given point is inside this location. Locations without boundaries never contain points.
+func (l *Location) Contains(pt utils.GeoPoint) bool {
+	if l.boundary == nil {
+		return false
+	}
+	if l.bbox != nil && !l.bbox.Contains(pt) {
+		return false
+	}
+	return l.boundary.Contains(pt)
+}
+
 // utility for traversing the location hierarchy
 type locationVisitor func(Location *Location)
 
@@ -220,6 +250,19 @@ func (h *LocationHierarchy) FindByName(env Environment, name string, level Locat
 	return []*Location{}
 }
 
+// FindByPoint looks for all locations in the hierarchy with the given level whose boundaries contain the given point
+func (h *LocationHierarchy) FindByPoint(point utils.GeoPoint, level LocationLevel, parent *Location) []*Location {
+	matches := make([]*Location, 0)
+
+	h.root.visit(func(location *Location) {
+		if location.level == level && (parent == nil || location.parent == parent) && location.Contains(point) {
+			matches = append(matches, location)
+		}
+	})
+
+	return matches
+}
+
 // FindByPath looks for a location in the hierarchy with the given path
 func (h *LocationHierarchy) FindByPath(path LocationPath) *Location {
 	return h.pathLookup.lookup(path)
@@ -246,15 +289,26 @@ func (h *LocationHierarchy) UnmarshalJSON(data []byte) error {
 type locationEnvelope struct {
 	Name     string              `json:"name" validate:"required"`
 	Aliases  []string            `json:"aliases,omitempty"`
+	Centroid *utils.GeoPoint     `json:"centroid,omitempty"`
+	BBox     *utils.GeoBBox      `json:"bbox,omitempty"`
+	Boundary *utils.GeoBoundary  `json:"boundary,omitempty"`
 	Children []*locationEnvelope `json:"children,omitempty"`
 }
 
 func locationFromEnvelope(envelope *locationEnvelope, currentLevel LocationLevel, parent *Location) *Location {
 	location := &Location{
-		level:   LocationLevel(currentLevel),
-		name:    envelope.Name,
-		aliases: envelope.Aliases,
-		parent:  parent,
+		level:    LocationLevel(currentLevel),
+		name:     envelope.Name,
+		aliases:  envelope.Aliases,
+		centroid: envelope.Centroid,
+		bbox:     envelope.BBox,
+		boundary: envelope.Boundary,
+		parent:   parent,
+	}
+
+	// if we have a boundary but no bounding box, we can derive it
+	if location.bbox == nil && location.boundary != nil {
+		location.bbox = location.boundary.BBox()
 	}
 
 	location.children = make([]*Location, len(envelope.Children))
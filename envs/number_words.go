@@ -0,0 +1,327 @@
+package envs
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/nyaruka/gocommon/i18n"
+)
+
+// NumberWords is the vocabulary used to write out whole numbers in a language
+type NumberWords struct {
+	// words which add their value, e.g. "five" or "twenty"
+	Values map[string]int64
+
+	// words for one which are also indefinite articles, e.g. "un" in Spanish, and so are only numbers as part of a
+	// longer number, e.g. "treinta y un"
+	Articles []string
+
+	// words which multiply the preceding (or following) value, e.g. "hundred" or "thousand"
+	Multipliers map[string]int64
+
+	// words which can join number words, e.g. "and" as in "one hundred and five"
+	Connectors []string
+
+	// whether multipliers come before the values they multiply, e.g. "mia tano" (500) in Swahili
+	MultipliersFirst bool
+}
+
+// NumberWordsMatch is a spelled-out number found in text
+type NumberWordsMatch struct {
+	Start int // byte offset in the text where the number starts
+	End   int // byte offset in the text where the number ends
+	Value int64
+}
+
+var numberWords = map[i18n.Language]*NumberWords{}
+var numberWordsMutex sync.RWMutex
+
+// RegisterNumberWords registers the vocabulary of spelled-out numbers in the given language
+func RegisterNumberWords(lang i18n.Language, words *NumberWords) {
+	numberWordsMutex.Lock()
+	defer numberWordsMutex.Unlock()
+
+	numberWords[lang] = words
+}
+
+func getNumberWords(lang i18n.Language) *NumberWords {
+	numberWordsMutex.RLock()
+	defer numberWordsMutex.RUnlock()
+
+	return numberWords[lang]
+}
+
+// number words are letters and can be joined by hyphens, e.g. "vingt-cinq"
+var numberWordRegex = regexp.MustCompile(`[\pL\pM]+(-[\pL\pM]+)*`)
+
+type numberWordToken struct {
+	text  string
+	start int
+	end   int
+}
+
+// FindNumberWords finds all spelled-out numbers in the given text in the given language
+func FindNumberWords(lang i18n.Language, text string) []NumberWordsMatch {
+	words := getNumberWords(lang)
+	if words == nil {
+		return nil
+	}
+
+	// split into words, including the parts of hyphenated words
+	parts := make([]numberWordToken, 0)
+	for _, loc := range numberWordRegex.FindAllStringIndex(text, -1) {
+		offset := loc[0]
+		for _, part := range strings.Split(text[loc[0]:loc[1]], "-") {
+			parts = append(parts, numberWordToken{strings.ToLower(part), offset, offset + len(part)})
+			offset += len(part) + 1
+		}
+	}
+
+	// and join back together any pairs of words which are a single number word, e.g. "quatre-vingt"
+	tokens := make([]numberWordToken, 0, len(parts))
+	for i := 0; i < len(parts); i++ {
+		if i+1 < len(parts) {
+			if joined := parts[i].text + "-" + parts[i+1].text; words.isNumberWord(joined) {
+				tokens = append(tokens, numberWordToken{joined, parts[i].start, parts[i+1].end})
+				i++
+				continue
+			}
+		}
+		tokens = append(tokens, parts[i])
+	}
+
+	matches := make([]NumberWordsMatch, 0)
+	for i := 0; i < len(tokens); {
+		value, consumed := words.parse(tokens[i:])
+		if consumed == 0 {
+			i++
+			continue
+		}
+
+		matches = append(matches, NumberWordsMatch{Start: tokens[i].start, End: tokens[i+consumed-1].end, Value: value})
+		i += consumed
+	}
+	return matches
+}
+
+// ParseNumberWords parses the given text as a spelled-out number in the given language
+func ParseNumberWords(lang i18n.Language, text string) (int64, bool) {
+	text = strings.TrimSpace(text)
+	matches := FindNumberWords(lang, text)
+
+	if len(matches) == 1 && matches[0].Start == 0 && matches[0].End == len(text) {
+		return matches[0].Value, true
+	}
+	return 0, false
+}
+
+func (w *NumberWords) isNumberWord(word string) bool {
+	_, isValue := w.value(word)
+	_, isMultiplier := w.Multipliers[word]
+	return isValue || isMultiplier
+}
+
+func (w *NumberWords) value(word string) (int64, bool) {
+	if slices.Contains(w.Articles, word) {
+		return 1, true
+	}
+	value, isValue := w.Values[word]
+	return value, isValue
+}
+
+// parses the longest number that we can from the start of the given tokens, returning the number of tokens consumed
+func (w *NumberWords) parse(tokens []numberWordToken) (int64, int) {
+	var value int64
+	var consumed int
+
+	if w.MultipliersFirst {
+		value, consumed = w.parseMultipliersFirst(tokens)
+	} else {
+		value, consumed = w.parseMultipliersLast(tokens)
+	}
+
+	// an article on its own isn't a number, e.g. "un café"
+	if consumed == 1 && slices.Contains(w.Articles, tokens[0].text) {
+		return 0, 0
+	}
+	return value, consumed
+}
+
+// parses numbers in languages like English where multipliers come last, e.g. "five hundred" (500)
+func (w *NumberWords) parseMultipliersLast(tokens []numberWordToken) (int64, int) {
+	var total, current int64
+	consumed, numbers := 0, 0
+
+	for i, token := range tokens {
+		if slices.Contains(w.Connectors, token.text) {
+			// connectors can only join number words
+			if numbers == 0 || i+1 >= len(tokens) || !w.isNumberWord(tokens[i+1].text) {
+				break
+			}
+			continue
+		}
+
+		if value, isValue := w.value(token.text); isValue {
+			// a value can only be added if it doesn't overlap with what we have, e.g. "twenty five" but not "five twenty"
+			if !canAddValue(current, value) || (value == 0 && numbers > 0) {
+				break
+			}
+			current += value
+		} else if mult, isMultiplier := w.Multipliers[token.text]; isMultiplier {
+			if mult < 1000 {
+				if current >= mult {
+					break
+				}
+				current = max(current, 1) * mult
+			} else {
+				if total%(mult*1000) != 0 {
+					break
+				}
+				total += max(current, 1) * mult
+				current = 0
+			}
+		} else {
+			break
+		}
+
+		numbers++
+		consumed = i + 1
+
+		// zero can't be combined with anything
+		if numbers == 1 && current == 0 && total == 0 {
+			break
+		}
+	}
+
+	return total + current, consumed
+}
+
+func canAddValue(current, value int64) bool {
+	if value < 10 {
+		return current%10 == 0
+	}
+	if value < 100 {
+		return current%10 == 0 && current%100+value < 100
+	}
+	return current%1000+value < 1000
+}
+
+// parses numbers in languages like Swahili where multipliers come first, e.g. "elfu mbili mia tano na ishirini" (2520)
+func (w *NumberWords) parseMultipliersFirst(tokens []numberWordToken) (int64, int) {
+	var total, pending int64
+	consumed, numbers := 0, 0
+
+	for i, token := range tokens {
+		if slices.Contains(w.Connectors, token.text) {
+			if numbers == 0 || i+1 >= len(tokens) || !w.isNumberWord(tokens[i+1].text) {
+				break
+			}
+
+			// a connector after a multiplier means it stands alone, e.g. "mia na tano" (105)
+			total += pending
+			pending = 0
+			continue
+		}
+
+		if value, isValue := w.value(token.text); isValue {
+			if value == 0 && numbers > 0 {
+				break
+			}
+			if pending > 0 {
+				total += pending * value
+				pending = 0
+			} else {
+				if !canAddValue(total, value) {
+					break
+				}
+				total += value
+			}
+		} else if mult, isMultiplier := w.Multipliers[token.text]; isMultiplier {
+			if total > 0 && total%(mult*10) != 0 {
+				break
+			}
+			total += pending
+			pending = mult
+		} else {
+			break
+		}
+
+		numbers++
+		consumed = i + 1
+
+		// zero can't be combined with anything
+		if numbers == 1 && total == 0 && pending == 0 {
+			break
+		}
+	}
+
+	return total + pending, consumed
+}
+
+func init() {
+	RegisterNumberWords("eng", &NumberWords{
+		Values: map[string]int64{
+			"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9,
+			"ten": 10, "eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15, "sixteen": 16,
+			"seventeen": 17, "eighteen": 18, "nineteen": 19, "twenty": 20, "thirty": 30, "forty": 40, "fifty": 50,
+			"sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+		},
+		Multipliers: map[string]int64{"hundred": 100, "thousand": 1000, "million": 1000000},
+		Connectors:  []string{"and"},
+	})
+	RegisterNumberWords("spa", &NumberWords{
+		Values: map[string]int64{
+			"cero": 0, "uno": 1, "dos": 2, "tres": 3, "cuatro": 4, "cinco": 5, "seis": 6, "siete": 7,
+			"ocho": 8, "nueve": 9, "diez": 10, "once": 11, "doce": 12, "trece": 13, "catorce": 14, "quince": 15,
+			"dieciséis": 16, "dieciseis": 16, "diecisiete": 17, "dieciocho": 18, "diecinueve": 19, "veinte": 20,
+			"veintiuno": 21, "veintiún": 21, "veintiuna": 21, "veintidós": 22, "veintidos": 22, "veintitrés": 23,
+			"veintitres": 23, "veinticuatro": 24, "veinticinco": 25, "veintiséis": 26, "veintiseis": 26,
+			"veintisiete": 27, "veintiocho": 28, "veintinueve": 29, "treinta": 30, "cuarenta": 40, "cincuenta": 50,
+			"sesenta": 60, "setenta": 70, "ochenta": 80, "noventa": 90, "doscientos": 200, "doscientas": 200,
+			"trescientos": 300, "trescientas": 300, "cuatrocientos": 400, "cuatrocientas": 400, "quinientos": 500,
+			"quinientas": 500, "seiscientos": 600, "seiscientas": 600, "setecientos": 700, "setecientas": 700,
+			"ochocientos": 800, "ochocientas": 800, "novecientos": 900, "novecientas": 900,
+		},
+		Multipliers: map[string]int64{"cien": 100, "ciento": 100, "mil": 1000, "millón": 1000000, "millon": 1000000, "millones": 1000000},
+		Articles:    []string{"un", "una"},
+		Connectors:  []string{"y"},
+	})
+	RegisterNumberWords("fra", &NumberWords{
+		Values: map[string]int64{
+			"zéro": 0, "zero": 0, "deux": 2, "trois": 3, "quatre": 4, "cinq": 5, "six": 6, "sept": 7,
+			"huit": 8, "neuf": 9, "dix": 10, "onze": 11, "douze": 12, "treize": 13, "quatorze": 14, "quinze": 15,
+			"seize": 16, "vingt": 20, "trente": 30, "quarante": 40, "cinquante": 50, "soixante": 60,
+			"quatre-vingt": 80, "quatre-vingts": 80, "septante": 70, "huitante": 80, "octante": 80, "nonante": 90,
+		},
+		Multipliers: map[string]int64{"cent": 100, "cents": 100, "mille": 1000, "million": 1000000, "millions": 1000000},
+		Articles:    []string{"un", "une"},
+		Connectors:  []string{"et"},
+	})
+	RegisterNumberWords("por", &NumberWords{
+		Values: map[string]int64{
+			"zero": 0, "dois": 2, "duas": 2, "três": 3, "tres": 3, "quatro": 4, "cinco": 5,
+			"seis": 6, "sete": 7, "oito": 8, "nove": 9, "dez": 10, "onze": 11, "doze": 12, "treze": 13, "catorze": 14,
+			"quatorze": 14, "quinze": 15, "dezesseis": 16, "dezasseis": 16, "dezessete": 17, "dezassete": 17,
+			"dezoito": 18, "dezenove": 19, "dezanove": 19, "vinte": 20, "trinta": 30, "quarenta": 40, "cinquenta": 50,
+			"sessenta": 60, "setenta": 70, "oitenta": 80, "noventa": 90, "duzentos": 200, "duzentas": 200,
+			"trezentos": 300, "trezentas": 300, "quatrocentos": 400, "quatrocentas": 400, "quinhentos": 500,
+			"quinhentas": 500, "seiscentos": 600, "seiscentas": 600, "setecentos": 700, "setecentas": 700,
+			"oitocentos": 800, "oitocentas": 800, "novecentos": 900, "novecentas": 900,
+		},
+		Multipliers: map[string]int64{"cem": 100, "cento": 100, "mil": 1000, "milhão": 1000000, "milhao": 1000000, "milhões": 1000000, "milhoes": 1000000},
+		Articles:    []string{"um", "uma"},
+		Connectors:  []string{"e"},
+	})
+	RegisterNumberWords("swa", &NumberWords{
+		Values: map[string]int64{
+			"sifuri": 0, "moja": 1, "mbili": 2, "tatu": 3, "nne": 4, "tano": 5, "sita": 6, "saba": 7, "nane": 8,
+			"tisa": 9, "kumi": 10, "ishirini": 20, "thelathini": 30, "arobaini": 40, "hamsini": 50, "sitini": 60,
+			"sabini": 70, "themanini": 80, "tisini": 90,
+		},
+		Multipliers:      map[string]int64{"mia": 100, "elfu": 1000, "laki": 100000, "milioni": 1000000},
+		Connectors:       []string{"na"},
+		MultipliersFirst: true,
+	})
+}
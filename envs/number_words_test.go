@@ -0,0 +1,94 @@
+package envs_test
+
+import (
+	"testing"
+
+	"github.com/nyaruka/gocommon/i18n"
+	"github.com/nyaruka/goflow/envs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindNumberWords(t *testing.T) {
+	tcs := []struct {
+		lang     i18n.Language
+		text     string
+		expected []envs.NumberWordsMatch
+	}{
+		{"eng", "", []envs.NumberWordsMatch{}},
+		{"eng", "nothing here", []envs.NumberWordsMatch{}},
+		{"eng", "zero", []envs.NumberWordsMatch{{0, 4, 0}}},
+		{"eng", "I am twenty five", []envs.NumberWordsMatch{{5, 16, 25}}},
+		{"eng", "Twenty-Five", []envs.NumberWordsMatch{{0, 11, 25}}},
+		{"eng", "one hundred and five", []envs.NumberWordsMatch{{0, 20, 105}}},
+		{"eng", "two thousand three hundred and forty one", []envs.NumberWordsMatch{{0, 40, 2341}}},
+		{"eng", "three million", []envs.NumberWordsMatch{{0, 13, 3000000}}},
+		{"eng", "five twenty", []envs.NumberWordsMatch{{0, 4, 5}, {5, 11, 20}}},
+		{"eng", "one or two", []envs.NumberWordsMatch{{0, 3, 1}, {7, 10, 2}}},
+		{"eng", "bread and butter", []envs.NumberWordsMatch{}},
+		{"eng", "five and bread", []envs.NumberWordsMatch{{0, 4, 5}}},
+		{"spa", "tengo veinticinco años", []envs.NumberWordsMatch{{6, 17, 25}}},
+		{"spa", "treinta y dos", []envs.NumberWordsMatch{{0, 13, 32}}},
+		{"spa", "ciento cincuenta", []envs.NumberWordsMatch{{0, 16, 150}}},
+		{"spa", "dos mil quinientos", []envs.NumberWordsMatch{{0, 18, 2500}}},
+		{"spa", "quiero un café y 3 panes", []envs.NumberWordsMatch{}},
+		{"spa", "treinta y un días", []envs.NumberWordsMatch{{0, 12, 31}}},
+		{"spa", "un millón", []envs.NumberWordsMatch{{0, 10, 1000000}}},
+		{"fra", "vingt-cinq", []envs.NumberWordsMatch{{0, 10, 25}}},
+		{"fra", "quatre-vingt-dix-neuf", []envs.NumberWordsMatch{{0, 21, 99}}},
+		{"fra", "trois cents", []envs.NumberWordsMatch{{0, 11, 300}}},
+		{"fra", "vingt et un", []envs.NumberWordsMatch{{0, 11, 21}}},
+		{"fra", "une pomme", []envs.NumberWordsMatch{}},
+		{"por", "trinta e dois", []envs.NumberWordsMatch{{0, 13, 32}}},
+		{"por", "quinhentos", []envs.NumberWordsMatch{{0, 10, 500}}},
+		{"por", "vinte e um", []envs.NumberWordsMatch{{0, 10, 21}}},
+		{"por", "uma casa", []envs.NumberWordsMatch{}},
+		{"swa", "ishirini na tano", []envs.NumberWordsMatch{{0, 16, 25}}},
+		{"swa", "mia tano", []envs.NumberWordsMatch{{0, 8, 500}}},
+		{"swa", "mia na tano", []envs.NumberWordsMatch{{0, 11, 105}}},
+		{"swa", "elfu mbili mia tano na ishirini", []envs.NumberWordsMatch{{0, 31, 2520}}},
+		{"kin", "makumyabiri", nil}, // no vocabulary for this language
+	}
+
+	for _, tc := range tcs {
+		assert.Equal(t, tc.expected, envs.FindNumberWords(tc.lang, tc.text), "find mismatch for '%s' in %s", tc.text, tc.lang)
+	}
+}
+
+func TestParseNumberWords(t *testing.T) {
+	tcs := []struct {
+		lang     i18n.Language
+		text     string
+		expected int64
+		valid    bool
+	}{
+		{"eng", "forty two", 42, true},
+		{"eng", " Forty Two ", 42, true},
+		{"eng", "forty two people", 0, false},
+		{"eng", "one two", 0, false},
+		{"eng", "", 0, false},
+		{"spa", "cuarenta y dos", 42, true},
+		{"swa", "arobaini na mbili", 42, true},
+		{"kin", "mirongo ine na kabiri", 0, false},
+	}
+
+	for _, tc := range tcs {
+		value, valid := envs.ParseNumberWords(tc.lang, tc.text)
+
+		assert.Equal(t, tc.valid, valid, "valid mismatch for '%s' in %s", tc.text, tc.lang)
+		assert.Equal(t, tc.expected, value, "value mismatch for '%s' in %s", tc.text, tc.lang)
+	}
+}
+
+func TestRegisterNumberWords(t *testing.T) {
+	envs.RegisterNumberWords("kin", &envs.NumberWords{
+		Values:           map[string]int64{"rimwe": 1, "kabiri": 2, "icumi": 10},
+		Multipliers:      map[string]int64{"ijana": 100},
+		Connectors:       []string{"na"},
+		MultipliersFirst: true,
+	})
+	defer envs.RegisterNumberWords("kin", nil)
+
+	value, valid := envs.ParseNumberWords("kin", "icumi na kabiri")
+	assert.True(t, valid)
+	assert.Equal(t, int64(12), value)
+}
@@ -0,0 +1,205 @@
+package envs
+
+import (
+	"strings"
+)
+
+// gets the ASCII letters of the given word
+func asciiLetters(word string) []byte {
+	letters := make([]byte, 0, len(word))
+	for _, r := range word {
+		if r >= 'a' && r <= 'z' {
+			letters = append(letters, byte(r))
+		}
+	}
+	return letters
+}
+
+func isVowel(c byte) bool {
+	return c == 'a' || c == 'e' || c == 'i' || c == 'o' || c == 'u'
+}
+
+// simpleMetaphone returns a key of the given lowercase English word using a simplified version of the original
+// Metaphone algorithm, or empty string if it has no ASCII letters. This isn't Double Metaphone, so each word only has
+// one key, and words whose spelling has more than one common pronunciation, e.g. "Thomas" and "Tomas" or "Schmidt"
+// and "Smith", won't have the same key.
+func simpleMetaphone(word string) string {
+	letters := asciiLetters(word)
+	if len(letters) == 0 {
+		return ""
+	}
+
+	// remove duplicate adjacent letters except for c
+	w := letters[:1]
+	for _, c := range letters[1:] {
+		if c != w[len(w)-1] || c == 'c' {
+			w = append(w, c)
+		}
+	}
+
+	// handle initial letters which are silent or sound like something else
+	switch {
+	case len(w) > 1 && (string(w[:2]) == "kn" || string(w[:2]) == "gn" || string(w[:2]) == "pn" || string(w[:2]) == "ae" || string(w[:2]) == "wr"):
+		w = w[1:]
+	case w[0] == 'x':
+		w[0] = 's'
+	case len(w) > 1 && string(w[:2]) == "wh":
+		w = append([]byte{'w'}, w[2:]...)
+	}
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(w) {
+			return 0
+		}
+		return w[i]
+	}
+	oneOf := func(c byte, options string) bool { return c != 0 && strings.IndexByte(options, c) >= 0 }
+
+	var key strings.Builder
+
+	for i, c := range w {
+		prev, next, next2 := at(i-1), at(i+1), at(i+2)
+
+		switch c {
+		case 'a', 'e', 'i', 'o', 'u':
+			if i == 0 {
+				key.WriteByte(c)
+			}
+		case 'b':
+			if !(prev == 'm' && i == len(w)-1) {
+				key.WriteByte('b')
+			}
+		case 'c':
+			if next == 'i' && next2 == 'a' {
+				key.WriteByte('x')
+			} else if next == 'h' {
+				if prev == 's' {
+					key.WriteByte('k')
+				} else {
+					key.WriteByte('x')
+				}
+			} else if oneOf(next, "iey") {
+				if prev != 's' {
+					key.WriteByte('s')
+				}
+			} else {
+				key.WriteByte('k')
+			}
+		case 'd':
+			if next == 'g' && oneOf(next2, "iey") {
+				key.WriteByte('j')
+			} else {
+				key.WriteByte('t')
+			}
+		case 'g':
+			if next == 'h' && next2 != 0 && !isVowel(next2) {
+				continue
+			} else if next == 'n' && (i+2 == len(w) || string(w[i+1:]) == "ned") {
+				continue
+			} else if prev == 'd' && oneOf(next, "iey") {
+				continue
+			} else if oneOf(next, "iey") && prev != 'g' {
+				key.WriteByte('j')
+			} else {
+				key.WriteByte('k')
+			}
+		case 'h':
+			if oneOf(prev, "csptg") || (prev != 0 && isVowel(prev) && !isVowel(next)) {
+				continue
+			}
+			key.WriteByte('h')
+		case 'k':
+			if prev != 'c' {
+				key.WriteByte('k')
+			}
+		case 'p':
+			if next == 'h' {
+				key.WriteByte('f')
+			} else {
+				key.WriteByte('p')
+			}
+		case 'q':
+			key.WriteByte('k')
+		case 's':
+			if next == 'h' || (next == 'i' && oneOf(next2, "oa")) {
+				key.WriteByte('x')
+			} else {
+				key.WriteByte('s')
+			}
+		case 't':
+			if next == 'i' && oneOf(next2, "oa") {
+				key.WriteByte('x')
+			} else if next == 'h' {
+				key.WriteByte('0')
+			} else if !(next == 'c' && next2 == 'h') {
+				key.WriteByte('t')
+			}
+		case 'v':
+			key.WriteByte('f')
+		case 'w', 'y':
+			if next != 0 && isVowel(next) {
+				key.WriteByte(c)
+			}
+		case 'x':
+			key.WriteString("ks")
+		case 'z':
+			key.WriteByte('s')
+		default:
+			key.WriteByte(c)
+		}
+	}
+
+	return key.String()
+}
+
+// Soundex-like groups of consonants which sound alike in Swahili. Unlike Soundex, l and r are grouped together
+// as they're commonly interchanged.
+var swahiliSoundGroups = map[byte]byte{
+	'b': '1', 'f': '1', 'p': '1', 'v': '1',
+	'c': '2', 'g': '2', 'j': '2', 'k': '2', 'q': '2', 'x': '2',
+	's': '3', 'z': '3',
+	'd': '4', 't': '4',
+	'l': '5', 'r': '5',
+	'm': '6', 'n': '6',
+}
+
+// the letters which represent each group when they start a word
+var swahiliGroupLetters = map[byte]byte{'1': 'b', '2': 'k', '3': 's', '4': 'd', '5': 'l', '6': 'm'}
+
+// swahiliSoundex returns a Soundex-like key of the given lowercase Swahili word, or empty string if it has no ASCII
+// letters. Unlike Soundex, the key isn't truncated and a leading vowel is always a.
+func swahiliSoundex(word string) string {
+	w := asciiLetters(word)
+	if len(w) == 0 {
+		return ""
+	}
+
+	var key strings.Builder
+
+	first := w[0]
+	last := swahiliSoundGroups[first]
+	if isVowel(first) {
+		key.WriteByte('a')
+	} else if last != 0 {
+		key.WriteByte(swahiliGroupLetters[last])
+	} else {
+		key.WriteByte(first)
+	}
+
+	for _, c := range w[1:] {
+		code, found := swahiliSoundGroups[c]
+		if !found {
+			// vowels separate consonants of the same group but h, w and y don't
+			if isVowel(c) {
+				last = 0
+			}
+			continue
+		}
+		if code != last {
+			key.WriteByte(code)
+		}
+		last = code
+	}
+
+	return key.String()
+}
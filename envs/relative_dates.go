@@ -0,0 +1,353 @@
+package envs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/nyaruka/gocommon/i18n"
+)
+
+// RelativeDateWords is the vocabulary used to write dates relative to today in a language. Patterns can include the
+// placeholders {n} for an amount, {unit} for a unit of time and {weekday} for the name of a day of the week.
+type RelativeDateWords struct {
+	// phrases for days relative to today, e.g. "tomorrow" is 1
+	Days map[string]int
+
+	// names of the days of the week
+	Weekdays map[string]time.Weekday
+
+	// names of units of time mapped to D (days), W (weeks), M (months) or Y (years)
+	Units map[string]string
+
+	// patterns of dates in the future, e.g. "in {n} {unit}" or "next {weekday}"
+	Future []string
+
+	// patterns of dates in the past, e.g. "{n} {unit} ago" or "last {weekday}"
+	Past []string
+}
+
+const (
+	relativeDateAmount  = "{n}"
+	relativeDateUnit    = "{unit}"
+	relativeDateWeekday = "{weekday}"
+)
+
+// a pattern split into normalized words and placeholders
+type relativeDatePattern struct {
+	words []string
+	days  int // for fixed phrases, the days relative to today
+	sign  int // for other patterns, 1 for future dates and -1 for past dates
+}
+
+// vocabulary with all words normalized so they can be matched against normalized text
+type relativeDateVocab struct {
+	patterns []*relativeDatePattern
+	weekdays map[string]time.Weekday
+	units    map[string]string
+}
+
+var relativeDateVocabs = map[i18n.Language]*relativeDateVocab{}
+var relativeDateVocabsMutex sync.RWMutex
+
+// RegisterRelativeDateWords registers the vocabulary of relative dates in the given language
+func RegisterRelativeDateWords(lang i18n.Language, words *RelativeDateWords) {
+	relativeDateVocabsMutex.Lock()
+	defer relativeDateVocabsMutex.Unlock()
+
+	if words == nil {
+		delete(relativeDateVocabs, lang)
+		return
+	}
+
+	vocab := &relativeDateVocab{
+		patterns: make([]*relativeDatePattern, 0, len(words.Days)+len(words.Future)+len(words.Past)),
+		weekdays: make(map[string]time.Weekday, len(words.Weekdays)),
+		units:    make(map[string]string, len(words.Units)),
+	}
+	for phrase, days := range words.Days {
+		vocab.patterns = append(vocab.patterns, &relativeDatePattern{words: splitRelativeDatePattern(phrase), days: days})
+	}
+	for _, pattern := range words.Future {
+		vocab.patterns = append(vocab.patterns, &relativeDatePattern{words: splitRelativeDatePattern(pattern), sign: 1})
+	}
+	for _, pattern := range words.Past {
+		vocab.patterns = append(vocab.patterns, &relativeDatePattern{words: splitRelativeDatePattern(pattern), sign: -1})
+	}
+	for name, weekday := range words.Weekdays {
+		vocab.weekdays[normalizeRelativeDateWord(name)] = weekday
+	}
+	for name, unit := range words.Units {
+		vocab.units[normalizeRelativeDateWord(name)] = unit
+	}
+
+	relativeDateVocabs[lang] = vocab
+}
+
+func getRelativeDateVocab(lang i18n.Language) *relativeDateVocab {
+	relativeDateVocabsMutex.RLock()
+	defer relativeDateVocabsMutex.RUnlock()
+
+	return relativeDateVocabs[lang]
+}
+
+// words can include digits and be joined by hyphens or apostrophes, e.g. "après-demain" or "aujourd'hui"
+var relativeDateWordRegex = regexp.MustCompile(`[\pL\pM\pN]+(?:['’-][\pL\pM\pN]+)*`)
+
+type relativeDateToken struct {
+	text  string
+	start int
+	end   int
+}
+
+// normalizes a word so that matching ignores case and accents
+func normalizeRelativeDateWord(w string) string {
+	return transliterate(strings.ReplaceAll(w, "’", "'"))
+}
+
+func splitRelativeDatePattern(pattern string) []string {
+	words := strings.Fields(pattern)
+	for i, w := range words {
+		if w != relativeDateAmount && w != relativeDateUnit && w != relativeDateWeekday {
+			words[i] = normalizeRelativeDateWord(w)
+		}
+	}
+	return words
+}
+
+// FindRelativeDate finds the first date written relative to today in the given text, e.g. "tomorrow" or "in 3 days",
+// using the vocabulary of the environment's default language, and returns it with the remainder of the text
+func FindRelativeDate(env Environment, str string) (dates.Date, string, error) {
+	lang := env.DefaultLanguage()
+
+	if vocab := getRelativeDateVocab(lang); vocab != nil {
+		tokens := make([]relativeDateToken, 0)
+		for _, loc := range relativeDateWordRegex.FindAllStringIndex(str, -1) {
+			tokens = append(tokens, relativeDateToken{normalizeRelativeDateWord(str[loc[0]:loc[1]]), loc[0], loc[1]})
+		}
+
+		for i := range tokens {
+			// look for the longest match starting at this word
+			var best *relativeDateMatch
+			for _, pattern := range vocab.patterns {
+				if m := vocab.match(lang, str, tokens[i:], pattern); m != nil && (best == nil || m.end > best.end) {
+					best = m
+				}
+			}
+
+			if best != nil {
+				return best.resolve(env.Now()), str[best.end:], nil
+			}
+		}
+	}
+
+	return dates.ZeroDate, str, fmt.Errorf("string '%s' couldn't be parsed as a relative date", str)
+}
+
+// RelativeDateTimeFromString returns a datetime constructed from the first date written relative to today in the passed
+// in string, with the time of day taken from the rest of the string, or from now if fillTime is set
+func RelativeDateTimeFromString(env Environment, str string, fillTime bool) (time.Time, error) {
+	date, remainder, err := FindRelativeDate(env, str)
+	if err != nil {
+		return ZeroDateTime, err
+	}
+
+	hasTime, timeOfDay := parseTime(remainder)
+	if !hasTime && fillTime {
+		timeOfDay = dates.ExtractTimeOfDay(env.Now())
+	}
+
+	return date.Combine(timeOfDay, env.Timezone()), nil
+}
+
+type relativeDateMatch struct {
+	pattern    *relativeDatePattern
+	end        int
+	amount     int
+	unit       string
+	weekday    time.Weekday
+	hasWeekday bool
+}
+
+// tries to match the given pattern at the start of the given tokens
+func (v *relativeDateVocab) match(lang i18n.Language, str string, tokens []relativeDateToken, pattern *relativeDatePattern) *relativeDateMatch {
+	m := &relativeDateMatch{pattern: pattern, amount: 1}
+	pos := 0
+
+	for _, word := range pattern.words {
+		if pos >= len(tokens) {
+			return nil
+		}
+
+		switch word {
+		case relativeDateAmount:
+			amount, consumed := parseRelativeDateAmount(lang, str, tokens[pos:])
+			if consumed == 0 {
+				return nil
+			}
+			m.amount = amount
+			pos += consumed
+		case relativeDateUnit:
+			unit, found := v.units[tokens[pos].text]
+			if !found {
+				return nil
+			}
+			m.unit = unit
+			pos++
+		case relativeDateWeekday:
+			weekday, found := v.weekdays[tokens[pos].text]
+			if !found {
+				return nil
+			}
+			m.weekday, m.hasWeekday = weekday, true
+			pos++
+		default:
+			if tokens[pos].text != word {
+				return nil
+			}
+			pos++
+		}
+	}
+
+	m.end = tokens[pos-1].end
+	return m
+}
+
+// parses an amount written as digits or as words, returning the number of tokens consumed
+func parseRelativeDateAmount(lang i18n.Language, str string, tokens []relativeDateToken) (int, int) {
+	if n, err := strconv.Atoi(tokens[0].text); err == nil {
+		return n, 1
+	}
+
+	// look for the longest run of words which is a number
+	for end := min(len(tokens), 6); end > 0; end-- {
+		if n, ok := ParseNumberWords(lang, str[tokens[0].start:tokens[end-1].end]); ok {
+			return int(n), end
+		}
+	}
+	return 0, 0
+}
+
+func (m *relativeDateMatch) resolve(now time.Time) dates.Date {
+	if m.pattern.sign == 0 {
+		return dates.ExtractDate(now.AddDate(0, 0, m.pattern.days))
+	}
+
+	if m.hasWeekday {
+		// the next or previous occurrence of that day, never today
+		var days int
+		if m.pattern.sign > 0 {
+			days = (int(m.weekday) - int(now.Weekday()) + 7) % 7
+		} else {
+			days = (int(now.Weekday()) - int(m.weekday) + 7) % 7
+		}
+		if days == 0 {
+			days = 7
+		}
+		return dates.ExtractDate(now.AddDate(0, 0, days*m.pattern.sign))
+	}
+
+	amount := m.amount * m.pattern.sign
+
+	switch m.unit {
+	case "W":
+		return dates.ExtractDate(now.AddDate(0, 0, amount*7))
+	case "M":
+		return addMonths(now, amount)
+	case "Y":
+		return addMonths(now, amount*12)
+	}
+	return dates.ExtractDate(now.AddDate(0, 0, amount))
+}
+
+// adds months to the given time, clamping to the last day of the target month rather than overflowing into the
+// following month, e.g. a month after January 31st is the end of February
+func addMonths(t time.Time, months int) dates.Date {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+
+	return dates.NewDate(first.Year(), int(first.Month()), min(t.Day(), lastDay))
+}
+
+func init() {
+	RegisterRelativeDateWords("eng", &RelativeDateWords{
+		Days: map[string]int{
+			"today": 0, "tonight": 0, "tomorrow": 1, "tmrw": 1, "yesterday": -1, "day after tomorrow": 2,
+			"day before yesterday": -2,
+		},
+		Weekdays: map[string]time.Weekday{
+			"monday": time.Monday, "mon": time.Monday, "tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+			"wednesday": time.Wednesday, "wed": time.Wednesday, "thursday": time.Thursday, "thu": time.Thursday,
+			"thurs": time.Thursday, "friday": time.Friday, "fri": time.Friday, "saturday": time.Saturday,
+			"sunday": time.Sunday,
+		},
+		Units: map[string]string{
+			"day": "D", "days": "D", "week": "W", "weeks": "W", "month": "M", "months": "M", "year": "Y", "years": "Y",
+		},
+		Future: []string{"in {n} {unit}", "in a {unit}", "{n} {unit} from now", "next {unit}", "next {weekday}", "this {weekday}", "{weekday}"},
+		Past:   []string{"{n} {unit} ago", "a {unit} ago", "last {unit}", "last {weekday}"},
+	})
+	RegisterRelativeDateWords("spa", &RelativeDateWords{
+		Days: map[string]int{
+			"hoy": 0, "mañana": 1, "pasado mañana": 2, "ayer": -1, "anteayer": -2, "antier": -2, "antes de ayer": -2,
+		},
+		Weekdays: map[string]time.Weekday{
+			"lunes": time.Monday, "martes": time.Tuesday, "miércoles": time.Wednesday, "jueves": time.Thursday,
+			"viernes": time.Friday, "sábado": time.Saturday, "domingo": time.Sunday,
+		},
+		Units: map[string]string{
+			"día": "D", "días": "D", "semana": "W", "semanas": "W", "mes": "M", "meses": "M", "año": "Y", "años": "Y",
+		},
+		Future: []string{"en {n} {unit}", "dentro de {n} {unit}", "próximo {unit}", "próxima {unit}", "{unit} que viene", "próximo {weekday}", "{weekday} que viene", "{weekday}"},
+		Past:   []string{"hace {n} {unit}", "{unit} pasado", "{unit} pasada", "{weekday} pasado"},
+	})
+	RegisterRelativeDateWords("fra", &RelativeDateWords{
+		Days: map[string]int{
+			"aujourd'hui": 0, "demain": 1, "après-demain": 2, "hier": -1, "avant-hier": -2,
+		},
+		Weekdays: map[string]time.Weekday{
+			"lundi": time.Monday, "mardi": time.Tuesday, "mercredi": time.Wednesday, "jeudi": time.Thursday,
+			"vendredi": time.Friday, "samedi": time.Saturday, "dimanche": time.Sunday,
+		},
+		Units: map[string]string{
+			"jour": "D", "jours": "D", "semaine": "W", "semaines": "W", "mois": "M", "an": "Y", "ans": "Y", "année": "Y",
+			"années": "Y",
+		},
+		Future: []string{"dans {n} {unit}", "{unit} prochain", "{unit} prochaine", "{weekday} prochain", "{weekday}"},
+		Past:   []string{"il y a {n} {unit}", "{unit} dernier", "{unit} dernière", "{weekday} dernier"},
+	})
+	RegisterRelativeDateWords("por", &RelativeDateWords{
+		Days: map[string]int{
+			"hoje": 0, "amanhã": 1, "depois de amanhã": 2, "ontem": -1, "anteontem": -2,
+		},
+		Weekdays: map[string]time.Weekday{
+			"segunda": time.Monday, "segunda-feira": time.Monday, "terça": time.Tuesday, "terça-feira": time.Tuesday,
+			"quarta": time.Wednesday, "quarta-feira": time.Wednesday, "quinta": time.Thursday,
+			"quinta-feira": time.Thursday, "sexta": time.Friday, "sexta-feira": time.Friday, "sábado": time.Saturday,
+			"domingo": time.Sunday,
+		},
+		Units: map[string]string{
+			"dia": "D", "dias": "D", "semana": "W", "semanas": "W", "mês": "M", "meses": "M", "ano": "Y", "anos": "Y",
+		},
+		Future: []string{"em {n} {unit}", "daqui a {n} {unit}", "próximo {unit}", "próxima {unit}", "{unit} que vem", "próximo {weekday}", "próxima {weekday}", "{weekday}"},
+		Past:   []string{"há {n} {unit}", "{n} {unit} atrás", "{unit} passado", "{unit} passada", "{weekday} passado", "{weekday} passada"},
+	})
+	RegisterRelativeDateWords("swa", &RelativeDateWords{
+		Days: map[string]int{
+			"leo": 0, "kesho": 1, "kesho kutwa": 2, "keshokutwa": 2, "jana": -1, "juzi": -2,
+		},
+		Weekdays: map[string]time.Weekday{
+			"jumatatu": time.Monday, "jumanne": time.Tuesday, "jumatano": time.Wednesday, "alhamisi": time.Thursday,
+			"ijumaa": time.Friday, "jumamosi": time.Saturday, "jumapili": time.Sunday,
+		},
+		Units: map[string]string{
+			"siku": "D", "wiki": "W", "mwezi": "M", "miezi": "M", "mwaka": "Y", "miaka": "Y",
+		},
+		Future: []string{"baada ya {unit} {n}", "{unit} ijayo", "{unit} ujao", "{weekday} ijayo", "{weekday}"},
+		Past:   []string{"{unit} {n} zilizopita", "{unit} {n} iliyopita", "{unit} iliyopita", "{unit} uliopita", "{weekday} iliyopita"},
+	})
+}
@@ -0,0 +1,156 @@
+package envs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/nyaruka/gocommon/i18n"
+	"github.com/nyaruka/goflow/envs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRelativeDate(t *testing.T) {
+	// a Thursday
+	dates.SetNowFunc(dates.NewFixedNow(time.Date(2018, 9, 13, 13, 36, 30, 123456789, time.UTC)))
+	defer dates.SetNowFunc(time.Now)
+
+	tcs := []struct {
+		lang      i18n.Language
+		text      string
+		expected  dates.Date
+		remainder string
+	}{
+		{"eng", "today", dates.NewDate(2018, 9, 13), ""},
+		{"eng", "Tomorrow please", dates.NewDate(2018, 9, 14), " please"},
+		{"eng", "yesterday", dates.NewDate(2018, 9, 12), ""},
+		{"eng", "the day after tomorrow", dates.NewDate(2018, 9, 15), ""},
+		{"eng", "in 3 days", dates.NewDate(2018, 9, 16), ""},
+		{"eng", "in three days", dates.NewDate(2018, 9, 16), ""},
+		{"eng", "in twenty one days", dates.NewDate(2018, 10, 4), ""},
+		{"eng", "in a week", dates.NewDate(2018, 9, 20), ""},
+		{"eng", "2 weeks from now", dates.NewDate(2018, 9, 27), ""},
+		{"eng", "next month", dates.NewDate(2018, 10, 13), ""},
+		{"eng", "next Monday at 10:30", dates.NewDate(2018, 9, 17), " at 10:30"},
+		{"eng", "on friday", dates.NewDate(2018, 9, 14), ""},
+		{"eng", "thursday", dates.NewDate(2018, 9, 20), ""}, // never today
+		{"eng", "last thursday", dates.NewDate(2018, 9, 6), ""},
+		{"eng", "last tue", dates.NewDate(2018, 9, 11), ""},
+		{"eng", "5 days ago", dates.NewDate(2018, 9, 8), ""},
+		{"eng", "a year ago", dates.NewDate(2017, 9, 13), ""},
+		{"spa", "mañana", dates.NewDate(2018, 9, 14), ""},
+		{"spa", "manana", dates.NewDate(2018, 9, 14), ""},
+		{"spa", "pasado mañana", dates.NewDate(2018, 9, 15), ""},
+		{"spa", "el lunes que viene", dates.NewDate(2018, 9, 17), ""},
+		{"spa", "el lunes pasado", dates.NewDate(2018, 9, 10), ""},
+		{"spa", "dentro de dos semanas", dates.NewDate(2018, 9, 27), ""},
+		{"spa", "hace 3 días", dates.NewDate(2018, 9, 10), ""},
+		{"fra", "hier", dates.NewDate(2018, 9, 12), ""},
+		{"fra", "aujourd’hui", dates.NewDate(2018, 9, 13), ""},
+		{"fra", "après-demain", dates.NewDate(2018, 9, 15), ""},
+		{"fra", "il y a deux jours", dates.NewDate(2018, 9, 11), ""},
+		{"fra", "mardi prochain", dates.NewDate(2018, 9, 18), ""},
+		{"fra", "la semaine dernière", dates.NewDate(2018, 9, 6), ""},
+		{"por", "amanhã", dates.NewDate(2018, 9, 14), ""},
+		{"por", "na próxima segunda-feira", dates.NewDate(2018, 9, 17), ""},
+		{"por", "há 2 dias", dates.NewDate(2018, 9, 11), ""},
+		{"swa", "kesho", dates.NewDate(2018, 9, 14), ""},
+		{"swa", "kesho kutwa", dates.NewDate(2018, 9, 15), ""},
+		{"swa", "baada ya siku tatu", dates.NewDate(2018, 9, 16), ""},
+		{"swa", "wiki ijayo", dates.NewDate(2018, 9, 20), ""},
+		{"swa", "siku 2 zilizopita", dates.NewDate(2018, 9, 11), ""},
+		{"swa", "jumatatu", dates.NewDate(2018, 9, 17), ""},
+	}
+
+	for _, tc := range tcs {
+		env := envs.NewBuilder().WithAllowedLanguages(tc.lang).Build()
+		date, remainder, err := envs.FindRelativeDate(env, tc.text)
+
+		if assert.NoError(t, err, "unexpected error for '%s' in %s", tc.text, tc.lang) {
+			assert.Equal(t, tc.expected, date, "date mismatch for '%s' in %s", tc.text, tc.lang)
+			assert.Equal(t, tc.remainder, remainder, "remainder mismatch for '%s' in %s", tc.text, tc.lang)
+		}
+	}
+
+	// no date or no vocabulary for the language
+	_, _, err := envs.FindRelativeDate(envs.NewBuilder().WithAllowedLanguages("eng").Build(), "in a while")
+	assert.EqualError(t, err, "string 'in a while' couldn't be parsed as a relative date")
+	_, _, err = envs.FindRelativeDate(envs.NewBuilder().WithAllowedLanguages("kin").Build(), "ejo")
+	assert.Error(t, err)
+	_, _, err = envs.FindRelativeDate(envs.NewBuilder().Build(), "tomorrow")
+	assert.Error(t, err)
+
+	// dates are relative to today in the environment's timezone
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	env := envs.NewBuilder().WithAllowedLanguages("eng").WithTimezone(tokyo).Build()
+	dates.SetNowFunc(dates.NewFixedNow(time.Date(2018, 9, 13, 23, 0, 0, 0, time.UTC)))
+
+	date, _, err := envs.FindRelativeDate(env, "tomorrow")
+	assert.NoError(t, err)
+	assert.Equal(t, dates.NewDate(2018, 9, 15), date)
+
+	// months and years are clamped to the end of shorter months
+	env = envs.NewBuilder().WithAllowedLanguages("eng").Build()
+	dates.SetNowFunc(dates.NewFixedNow(time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)))
+
+	for text, expected := range map[string]dates.Date{
+		"next month":   dates.NewDate(2024, 2, 29),
+		"in 3 months":  dates.NewDate(2024, 4, 30),
+		"last month":   dates.NewDate(2023, 12, 31),
+		"2 months ago": dates.NewDate(2023, 11, 30),
+		"in 13 months": dates.NewDate(2025, 2, 28),
+		"next week":    dates.NewDate(2024, 2, 7),
+	} {
+		date, _, err = envs.FindRelativeDate(env, text)
+		assert.NoError(t, err, "unexpected error for '%s'", text)
+		assert.Equal(t, expected, date, "date mismatch for '%s'", text)
+	}
+
+	dates.SetNowFunc(dates.NewFixedNow(time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)))
+
+	date, _, err = envs.FindRelativeDate(env, "next year")
+	assert.NoError(t, err)
+	assert.Equal(t, dates.NewDate(2025, 2, 28), date)
+}
+
+func TestRegisterRelativeDateWords(t *testing.T) {
+	dates.SetNowFunc(dates.NewFixedNow(time.Date(2018, 9, 13, 13, 36, 30, 123456789, time.UTC)))
+	defer dates.SetNowFunc(time.Now)
+
+	envs.RegisterRelativeDateWords("kin", &envs.RelativeDateWords{Days: map[string]int{"ejo": 1}})
+	defer envs.RegisterRelativeDateWords("kin", nil)
+
+	date, _, err := envs.FindRelativeDate(envs.NewBuilder().WithAllowedLanguages("kin").Build(), "ejo")
+	assert.NoError(t, err)
+	assert.Equal(t, dates.NewDate(2018, 9, 14), date)
+}
+
+func TestRelativeDatesInput(t *testing.T) {
+	dates.SetNowFunc(dates.NewFixedNow(time.Date(2018, 9, 13, 13, 36, 30, 123456789, time.UTC)))
+	defer dates.SetNowFunc(time.Now)
+
+	env := envs.NewBuilder().WithDateFormat(envs.DateFormatDayMonthYear).WithAllowedLanguages("eng").WithInputRelativeDates(true).Build()
+
+	value, err := envs.RelativeDateTimeFromString(env, "tomorrow at 10:30", false)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2018, 9, 14, 10, 30, 0, 0, time.UTC), value)
+
+	value, err = envs.RelativeDateTimeFromString(env, "in 2 days", true)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2018, 9, 15, 13, 36, 30, 123456789, time.UTC), value)
+
+	value, err = envs.RelativeDateTimeFromString(env, "in 2 days", false)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2018, 9, 15, 0, 0, 0, 0, time.UTC), value)
+
+	_, err = envs.RelativeDateTimeFromString(env, "no date here", false)
+	assert.Error(t, err)
+
+	// general date parsing never considers relative dates, even if enabled
+	_, err = envs.DateTimeFromString(env, "tomorrow", false)
+	assert.Error(t, err)
+
+	_, err = envs.DateFromString(env, "tomorrow")
+	assert.Error(t, err)
+}
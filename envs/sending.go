@@ -0,0 +1,142 @@
+package envs
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/goflow/utils"
+)
+
+// DayOfWeek is a day of the week used in send windows
+type DayOfWeek string
+
+// days of the week
+const (
+	DayOfWeekMonday    DayOfWeek = "mon"
+	DayOfWeekTuesday   DayOfWeek = "tue"
+	DayOfWeekWednesday DayOfWeek = "wed"
+	DayOfWeekThursday  DayOfWeek = "thu"
+	DayOfWeekFriday    DayOfWeek = "fri"
+	DayOfWeekSaturday  DayOfWeek = "sat"
+	DayOfWeekSunday    DayOfWeek = "sun"
+)
+
+var weekdays = map[time.Weekday]DayOfWeek{
+	time.Monday:    DayOfWeekMonday,
+	time.Tuesday:   DayOfWeekTuesday,
+	time.Wednesday: DayOfWeekWednesday,
+	time.Thursday:  DayOfWeekThursday,
+	time.Friday:    DayOfWeekFriday,
+	time.Saturday:  DayOfWeekSaturday,
+	time.Sunday:    DayOfWeekSunday,
+}
+
+// SendWindow is a period of the day, on some or all days of the week, during which messages can be sent. If the end
+// isn't after the start then the window runs past midnight into the next day.
+type SendWindow struct {
+	days  []DayOfWeek
+	start dates.TimeOfDay
+	end   dates.TimeOfDay
+}
+
+// NewSendWindow creates a new send window - if no days are given then it applies to every day
+func NewSendWindow(days []DayOfWeek, start, end dates.TimeOfDay) *SendWindow {
+	return &SendWindow{days: days, start: start, end: end}
+}
+
+func (w *SendWindow) Days() []DayOfWeek      { return w.days }
+func (w *SendWindow) Start() dates.TimeOfDay { return w.start }
+func (w *SendWindow) End() dates.TimeOfDay   { return w.end }
+
+// returns whether this window opens on the given date
+func (w *SendWindow) opensOn(d dates.Date) bool {
+	return len(w.days) == 0 || slices.Contains(w.days, weekdays[d.Weekday()])
+}
+
+// returns the period of this window which opens on the given date
+func (w *SendWindow) period(d dates.Date, tz *time.Location) (time.Time, time.Time) {
+	start := w.start.Combine(d, tz)
+	end := w.end.Combine(d, tz)
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end
+}
+
+// SendWindows is a set of send windows. An empty set means that sending is always allowed.
+type SendWindows []*SendWindow
+
+// IsOpen returns whether sending is allowed at the given time in the given timezone
+func (ws SendWindows) IsOpen(t time.Time, tz *time.Location) bool {
+	return ws.NextOpen(t, tz).Equal(t)
+}
+
+// NextOpen returns the given time if sending is allowed then, otherwise the time when the next window opens
+func (ws SendWindows) NextOpen(t time.Time, tz *time.Location) time.Time {
+	if len(ws) == 0 {
+		return t
+	}
+
+	local := t.In(tz)
+	var next time.Time
+
+	// windows that opened yesterday may still be open, and every window will open again within a week
+	for offset := -1; offset <= 7; offset++ {
+		day := dates.ExtractDate(local.AddDate(0, 0, offset))
+
+		for _, w := range ws {
+			if !w.opensOn(day) {
+				continue
+			}
+
+			start, end := w.period(day, tz)
+			if !t.Before(start) && t.Before(end) {
+				return t
+			}
+			if start.After(t) && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+	}
+
+	return next.In(t.Location())
+}
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+type sendWindowEnvelope struct {
+	Days  []DayOfWeek `json:"days,omitempty" validate:"omitempty,dive,eq=mon|eq=tue|eq=wed|eq=thu|eq=fri|eq=sat|eq=sun"`
+	Start string      `json:"start"          validate:"required"`
+	End   string      `json:"end"            validate:"required"`
+}
+
+// UnmarshalJSON unmarshals a send window from JSON
+func (w *SendWindow) UnmarshalJSON(data []byte) error {
+	e := &sendWindowEnvelope{}
+	if err := utils.UnmarshalAndValidate(data, e); err != nil {
+		return err
+	}
+
+	var err error
+	w.days = e.Days
+	if w.start, err = dates.ParseTimeOfDay("tt:mm", e.Start); err != nil {
+		return fmt.Errorf("invalid send window start '%s': %w", e.Start, err)
+	}
+	if w.end, err = dates.ParseTimeOfDay("tt:mm", e.End); err != nil {
+		return fmt.Errorf("invalid send window end '%s': %w", e.End, err)
+	}
+	return nil
+}
+
+// MarshalJSON marshals this send window into JSON
+func (w *SendWindow) MarshalJSON() ([]byte, error) {
+	start, _ := w.start.Format("tt:mm", "")
+	end, _ := w.end.Format("tt:mm", "")
+
+	return jsonx.Marshal(&sendWindowEnvelope{Days: w.days, Start: start, End: end})
+}
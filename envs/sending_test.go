@@ -0,0 +1,87 @@
+package envs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/goflow/envs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendWindows(t *testing.T) {
+	kgl, _ := time.LoadLocation("Africa/Kigali") // UTC+2
+
+	weekdays := envs.NewSendWindow(
+		[]envs.DayOfWeek{envs.DayOfWeekMonday, envs.DayOfWeekTuesday, envs.DayOfWeekWednesday, envs.DayOfWeekThursday, envs.DayOfWeekFriday},
+		dates.NewTimeOfDay(9, 0, 0, 0),
+		dates.NewTimeOfDay(17, 0, 0, 0),
+	)
+	saturdayNights := envs.NewSendWindow(
+		[]envs.DayOfWeek{envs.DayOfWeekSaturday},
+		dates.NewTimeOfDay(20, 0, 0, 0),
+		dates.NewTimeOfDay(2, 0, 0, 0),
+	)
+
+	tcs := []struct {
+		windows  envs.SendWindows
+		tz       *time.Location
+		time     time.Time
+		nextOpen time.Time
+	}{
+		// no windows means always open
+		{nil, time.UTC, time.Date(2025, 5, 4, 3, 0, 0, 0, time.UTC), time.Date(2025, 5, 4, 3, 0, 0, 0, time.UTC)},
+
+		// within window (Monday)
+		{envs.SendWindows{weekdays}, time.UTC, time.Date(2025, 5, 5, 9, 0, 0, 0, time.UTC), time.Date(2025, 5, 5, 9, 0, 0, 0, time.UTC)},
+		{envs.SendWindows{weekdays}, time.UTC, time.Date(2025, 5, 5, 16, 59, 0, 0, time.UTC), time.Date(2025, 5, 5, 16, 59, 0, 0, time.UTC)},
+
+		// before window opens on same day
+		{envs.SendWindows{weekdays}, time.UTC, time.Date(2025, 5, 5, 7, 0, 0, 0, time.UTC), time.Date(2025, 5, 5, 9, 0, 0, 0, time.UTC)},
+
+		// end of window is exclusive so next open is the following day
+		{envs.SendWindows{weekdays}, time.UTC, time.Date(2025, 5, 5, 17, 0, 0, 0, time.UTC), time.Date(2025, 5, 6, 9, 0, 0, 0, time.UTC)},
+
+		// Friday evening skips to Monday
+		{envs.SendWindows{weekdays}, time.UTC, time.Date(2025, 5, 9, 18, 0, 0, 0, time.UTC), time.Date(2025, 5, 12, 9, 0, 0, 0, time.UTC)},
+
+		// windows are evaluated in the given timezone, i.e. 08:00 UTC is 10:00 in Kigali
+		{envs.SendWindows{weekdays}, kgl, time.Date(2025, 5, 5, 8, 0, 0, 0, time.UTC), time.Date(2025, 5, 5, 8, 0, 0, 0, time.UTC)},
+		{envs.SendWindows{weekdays}, kgl, time.Date(2025, 5, 5, 6, 0, 0, 0, time.UTC), time.Date(2025, 5, 5, 7, 0, 0, 0, time.UTC)},
+
+		// window that runs past midnight is still open early Sunday morning
+		{envs.SendWindows{saturdayNights}, time.UTC, time.Date(2025, 5, 4, 1, 0, 0, 0, time.UTC), time.Date(2025, 5, 4, 1, 0, 0, 0, time.UTC)},
+		{envs.SendWindows{saturdayNights}, time.UTC, time.Date(2025, 5, 4, 3, 0, 0, 0, time.UTC), time.Date(2025, 5, 10, 20, 0, 0, 0, time.UTC)},
+
+		// multiple windows picks the earliest to open
+		{envs.SendWindows{weekdays, saturdayNights}, time.UTC, time.Date(2025, 5, 9, 18, 0, 0, 0, time.UTC), time.Date(2025, 5, 10, 20, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range tcs {
+		assert.Equal(t, tc.nextOpen, tc.windows.NextOpen(tc.time, tc.tz), "next open mismatch for %s in %s", tc.time, tc.tz)
+		assert.Equal(t, tc.nextOpen.Equal(tc.time), tc.windows.IsOpen(tc.time, tc.tz), "is open mismatch for %s in %s", tc.time, tc.tz)
+	}
+
+	// test marshaling
+	var windows envs.SendWindows
+	err := jsonx.Unmarshal([]byte(`[{"days": ["mon", "tue", "wed", "thu", "fri"], "start": "09:00", "end": "17:00"}, {"start": "20:00", "end": "02:00"}]`), &windows)
+	require.NoError(t, err)
+	assert.Len(t, windows, 2)
+	assert.Equal(t, weekdays, windows[0])
+	assert.Nil(t, windows[1].Days())
+	assert.Equal(t, dates.NewTimeOfDay(20, 0, 0, 0), windows[1].Start())
+	assert.Equal(t, dates.NewTimeOfDay(2, 0, 0, 0), windows[1].End())
+
+	assert.Equal(t, `[{"days":["mon","tue","wed","thu","fri"],"start":"09:00","end":"17:00"},{"start":"20:00","end":"02:00"}]`, string(jsonx.MustMarshal(windows)))
+
+	err = jsonx.Unmarshal([]byte(`[{"days": ["xyz"], "start": "09:00", "end": "17:00"}]`), &windows)
+	assert.Error(t, err)
+
+	err = jsonx.Unmarshal([]byte(`[{"start": "9am", "end": "17:00"}]`), &windows)
+	assert.EqualError(t, err, "invalid send window start '9am': cannot parse 'am' as ':'")
+
+	err = jsonx.Unmarshal([]byte(`[{"start": "09:00"}]`), &windows)
+	assert.EqualError(t, err, "field 'end' is required")
+}
@@ -0,0 +1,119 @@
+package envs
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Based on common (simplified) romanizations of Russian and Ukrainian
+var cyrillicLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'ё': "e", 'є': "ye", 'ж': "zh", 'з': "z",
+	'и': "i", 'і': "i", 'ї': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ў': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// Devanagari consonants have an inherent a which is replaced by a following vowel sign or removed by a virama.
+// Long vowels are romanized the same as short ones as that's how they're usually typed.
+var devanagariConsonants = map[rune]string{
+	'क': "k", 'ख': "kh", 'ग': "g", 'घ': "gh", 'ङ': "n", 'च': "ch", 'छ': "chh", 'ज': "j", 'झ': "jh", 'ञ': "n",
+	'ट': "t", 'ठ': "th", 'ड': "d", 'ढ': "dh", 'ण': "n", 'त': "t", 'थ': "th", 'द': "d", 'ध': "dh", 'न': "n",
+	'प': "p", 'फ': "ph", 'ब': "b", 'भ': "bh", 'म': "m", 'य': "y", 'र': "r", 'ल': "l", 'ळ': "l", 'व': "v",
+	'श': "sh", 'ष': "sh", 'स': "s", 'ह': "h",
+}
+var devanagariVowels = map[rune]string{
+	'अ': "a", 'आ': "a", 'इ': "i", 'ई': "i", 'उ': "u", 'ऊ': "u", 'ऋ': "ri", 'ए': "e", 'ऐ': "ai", 'ओ': "o", 'औ': "au",
+}
+var devanagariVowelSigns = map[rune]string{
+	'ा': "a", 'ि': "i", 'ी': "i", 'ु': "u", 'ू': "u", 'ृ': "ri", 'े': "e", 'ै': "ai", 'ो': "o", 'ौ': "au",
+}
+var devanagariSigns = map[rune]string{
+	'ं': "n", 'ँ': "n", 'ः': "h",
+}
+
+const (
+	devanagariVirama = '्'
+	devanagariNukta  = '़'
+	devanagariZero   = '०'
+	devanagariNine   = '९'
+)
+
+// Ethiopic syllables come in blocks of 8, one per vowel order, starting from these consonants
+var ethiopicConsonants = map[rune]string{
+	'ሀ': "h", 'ለ': "l", 'ሐ': "h", 'መ': "m", 'ሠ': "s", 'ረ': "r", 'ሰ': "s", 'ሸ': "sh", 'ቀ': "q", 'በ': "b", 'ቨ': "v",
+	'ተ': "t", 'ቸ': "ch", 'ኀ': "h", 'ነ': "n", 'ኘ': "ny", 'አ': "", 'ከ': "k", 'ወ': "w", 'ዐ': "", 'ዘ': "z", 'ዠ': "zh",
+	'የ': "y", 'ደ': "d", 'ጀ': "j", 'ገ': "g", 'ጠ': "t", 'ጨ': "ch", 'ጰ': "p", 'ጸ': "ts", 'ፀ': "ts", 'ፈ': "f", 'ፐ': "p",
+}
+
+// the 6th order is usually a short vowel or no vowel at all so it's omitted
+var ethiopicVowelOrders = [8]string{"e", "u", "i", "a", "e", "", "o", "wa"}
+
+// transliterates Cyrillic, Devanagari and Ethiopic text to Latin characters, and removes accents from Latin characters
+func transliterate(s string) string {
+	var sb strings.Builder
+
+	// whether we've written a Devanagari consonant whose inherent vowel is pending
+	inherentA := false
+
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		if inherentA {
+			_, isConsonant := devanagariConsonants[r]
+			_, isVowel := devanagariVowels[r]
+			_, isSign := devanagariSigns[r]
+
+			if isConsonant || isVowel || isSign {
+				sb.WriteString("a")
+				inherentA = false
+			} else if r != devanagariNukta {
+				// vowel signs and viramas replace the inherent vowel, and it's not pronounced at the end of words
+				inherentA = false
+			}
+		}
+
+		if latin, found := cyrillicLatin[r]; found {
+			sb.WriteString(latin)
+		} else if latin, found := devanagariConsonants[r]; found {
+			sb.WriteString(latin)
+			inherentA = true
+		} else if latin, found := devanagariVowels[r]; found {
+			sb.WriteString(latin)
+		} else if latin, found := devanagariVowelSigns[r]; found {
+			sb.WriteString(latin)
+		} else if latin, found := devanagariSigns[r]; found {
+			sb.WriteString(latin)
+		} else if r >= devanagariZero && r <= devanagariNine {
+			sb.WriteRune('0' + (r - devanagariZero))
+		} else if latin, found := ethiopicSyllable(r); found {
+			sb.WriteString(latin)
+		} else if r == devanagariVirama || r == devanagariNukta || unicode.Is(unicode.Mn, r) {
+			continue
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+func ethiopicSyllable(r rune) (string, bool) {
+	if r < 'ሀ' || r > 'ፗ' {
+		return "", false
+	}
+
+	base := r - (r-'ሀ')%8
+	consonant, found := ethiopicConsonants[base]
+	if !found {
+		return "", false
+	}
+
+	vowel := ethiopicVowelOrders[r-base]
+
+	// the glottal consonants are just their vowels, and the 1st order is usually written as a
+	if consonant == "" && vowel == "e" && r == base {
+		vowel = "a"
+	}
+
+	return consonant + vowel, true
+}
@@ -0,0 +1,118 @@
+package excellent
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/nyaruka/goflow/envs"
+	"github.com/nyaruka/goflow/excellent/budget"
+	"github.com/nyaruka/goflow/excellent/functions"
+	"github.com/nyaruka/goflow/excellent/types"
+)
+
+var customNameRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,63}$`)
+
+// CustomFunction is a function defined by an expression, which is called with its params as the only values in
+// its context. It can call builtin functions and other custom functions, but not itself, directly or indirectly.
+type CustomFunction struct {
+	name   string
+	params []string
+	body   Expression
+}
+
+// NewCustomFunction creates a new custom function, returning an error if it isn't valid. Because names in expressions
+// are resolved against the context before functions, a function can't be named the same as one of the given top-levels
+// of the context where it will be called.
+func NewCustomFunction(name string, params []string, body string, topLevels []string) (*CustomFunction, error) {
+	name = strings.ToLower(name)
+
+	if !customNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("'%s' is not a valid function name", name)
+	}
+	if functions.Lookup(name) != nil {
+		return nil, fmt.Errorf("'%s' is the name of a builtin function", name)
+	}
+	if slices.Contains(topLevels, name) {
+		return nil, fmt.Errorf("'%s' is the name of a top-level context value", name)
+	}
+
+	for i, p := range params {
+		if !customNameRegex.MatchString(p) {
+			return nil, fmt.Errorf("'%s' is not a valid parameter name", p)
+		}
+		if slices.Contains(params[:i], p) {
+			return nil, fmt.Errorf("parameter name '%s' isn't unique", p)
+		}
+	}
+
+	parsed, err := Parse(body, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body: %w", err)
+	}
+
+	return &CustomFunction{name: name, params: params, body: parsed}, nil
+}
+
+// Name returns the name of this function
+func (f *CustomFunction) Name() string { return f.name }
+
+func (f *CustomFunction) call(ctx context.Context, env envs.Environment, args ...types.XValue) types.XValue {
+	stack, _ := ctx.Value(callStackKey{}).([]string)
+	if slices.Contains(stack, f.name) {
+		return types.NewXErrorf("function %s can't be called recursively", f.name)
+	}
+
+	ctx = context.WithValue(ctx, callStackKey{}, append(slices.Clone(stack), f.name))
+
+	vals := make(map[string]types.XValue, len(f.params))
+	for i, p := range f.params {
+		vals[p] = args[i]
+	}
+
+	result := f.body.Evaluate(ctx, env, NewScope(types.NewXObject(vals), nil), &Warnings{})
+
+	// charge the call and the value it produced against the evaluation budget
+	if b := budget.From(ctx); b != nil && !b.Charge(1+types.CostOf(result)) {
+		return types.ErrTooComplex
+	}
+
+	return result
+}
+
+// CustomFunctions is a set of custom functions which can be called in expressions like builtin functions
+type CustomFunctions struct {
+	byName map[string]*types.XFunction
+}
+
+// NewCustomFunctions creates a new set of custom functions
+func NewCustomFunctions(fns []*CustomFunction) *CustomFunctions {
+	s := &CustomFunctions{byName: make(map[string]*types.XFunction, len(fns))}
+	for _, f := range fns {
+		s.byName[f.name] = types.NewXFunction(f.name, functions.NumArgsCheck(len(f.params), f.call))
+	}
+	return s
+}
+
+// Lookup returns the function with the given name or nil if there isn't one
+func (s *CustomFunctions) Lookup(name string) *types.XFunction {
+	return s.byName[strings.ToLower(name)]
+}
+
+type customFunctionsKey struct{}
+type callStackKey struct{}
+
+// WithCustomFunctions returns a copy of ctx carrying the given custom functions
+func WithCustomFunctions(ctx context.Context, fns *CustomFunctions) context.Context {
+	return context.WithValue(ctx, customFunctionsKey{}, fns)
+}
+
+// looks up a custom function carried by ctx
+func lookupCustomFunction(ctx context.Context, name string) *types.XFunction {
+	if fns, _ := ctx.Value(customFunctionsKey{}).(*CustomFunctions); fns != nil {
+		return fns.Lookup(name)
+	}
+	return nil
+}
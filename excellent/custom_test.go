@@ -0,0 +1,99 @@
+package excellent_test
+
+import (
+	"testing"
+
+	"github.com/nyaruka/goflow/envs"
+	"github.com/nyaruka/goflow/excellent"
+	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomFunctions(t *testing.T) {
+	env := envs.NewBuilder().Build()
+	root := types.NewXObject(map[string]types.XValue{"name": types.NewXText("bob jones"), "id": types.NewXText(" 12-345-a ")})
+	eval := excellent.NewEvaluator(excellent.DefaultEvaluationBudget)
+	topLevels := []string{"name", "id"}
+
+	_, err := excellent.NewCustomFunction("1st", nil, `1`, topLevels)
+	assert.EqualError(t, err, "'1st' is not a valid function name")
+
+	_, err = excellent.NewCustomFunction("upper", []string{"text"}, `text`, topLevels)
+	assert.EqualError(t, err, "'upper' is the name of a builtin function")
+
+	_, err = excellent.NewCustomFunction("Name", nil, `1`, topLevels)
+	assert.EqualError(t, err, "'name' is the name of a top-level context value")
+
+	_, err = excellent.NewCustomFunction("foo", []string{"a", "Bad Param"}, `a`, topLevels)
+	assert.EqualError(t, err, "'Bad Param' is not a valid parameter name")
+
+	_, err = excellent.NewCustomFunction("foo", []string{"a", "a"}, `a`, topLevels)
+	assert.EqualError(t, err, "parameter name 'a' isn't unique")
+
+	_, err = excellent.NewCustomFunction("foo", []string{"a"}, `a +`, topLevels)
+	assert.EqualError(t, err, "invalid body: syntax error at ")
+
+	nationalID, err := excellent.NewCustomFunction("National_ID", []string{"value"}, `upper(replace(trim(value), "-", ""))`, topLevels)
+	require.NoError(t, err)
+	assert.Equal(t, "national_id", nationalID.Name())
+
+	greet, err := excellent.NewCustomFunction("greet", []string{"name", "id"}, `"Hi " & title(name) & " (" & national_id(id) & ")"`, topLevels)
+	require.NoError(t, err)
+
+	leaky, err := excellent.NewCustomFunction("leaky", nil, `id`, topLevels)
+	require.NoError(t, err)
+
+	ping, err := excellent.NewCustomFunction("ping", []string{"n"}, `pong(n)`, topLevels)
+	require.NoError(t, err)
+	pong, err := excellent.NewCustomFunction("pong", []string{"n"}, `ping(n)`, topLevels)
+	require.NoError(t, err)
+
+	fns := excellent.NewCustomFunctions([]*excellent.CustomFunction{nationalID, greet, leaky, ping, pong})
+	assert.NotNil(t, fns.Lookup("NATIONAL_ID"))
+	assert.Nil(t, fns.Lookup("xxx"))
+
+	ctx := excellent.WithCustomFunctions(t.Context(), fns)
+
+	tcs := []struct {
+		template string
+		output   string
+		err      string
+	}{
+		{`@(national_id(id))`, "12345A", ""},
+		{`@(NATIONAL_ID("9-8"))`, "98", ""},
+		{`@(greet(name, id))`, "Hi Bob Jones (12345A)", ""},
+		{`@(national_id())`, "", "error calling national_id(...): need 1 argument(s), got 0"},
+		{`@(leaky())`, "", "error calling leaky(...): context has no property 'id'"},
+		{`@(ping(1))`, "", "error calling ping(...): error calling pong(...): error calling ping(...): function ping can't be called recursively"},
+		{`@(xxx(1))`, "", "context has no property 'xxx'"},
+	}
+
+	for _, tc := range tcs {
+		output, _, err := eval.Template(ctx, env, root, tc.template, nil)
+		if tc.err != "" {
+			if assert.Error(t, err, "expected error for %s", tc.template) {
+				assert.Contains(t, err.Error(), tc.err, "error mismatch for %s", tc.template)
+			}
+		} else {
+			assert.NoError(t, err, "unexpected error for %s", tc.template)
+			assert.Equal(t, tc.output, output, "output mismatch for %s", tc.template)
+		}
+	}
+
+	// without the custom functions in the context, they can't be called
+	_, _, err = eval.Template(t.Context(), env, root, `@(national_id(id))`, nil)
+	assert.EqualError(t, err, "error evaluating @(national_id(id)): context has no property 'national_id'")
+
+	// calls are charged against the evaluation budget
+	repeat, err := excellent.NewCustomFunction("big", []string{"n"}, `repeat("x", n)`, topLevels)
+	require.NoError(t, err)
+	ctx = excellent.WithCustomFunctions(t.Context(), excellent.NewCustomFunctions([]*excellent.CustomFunction{repeat}))
+
+	_, _, err = excellent.NewEvaluator(250).Template(ctx, env, root, `@(big(30) & big(30))`, nil)
+	assert.NoError(t, err)
+	_, _, err = excellent.NewEvaluator(250).Template(ctx, env, root, `@(big(30) & big(30) & big(30))`, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "expression is too complex to evaluate")
+	}
+}
@@ -120,6 +120,7 @@ func init() {
 		"unique":   OneArrayFunction(Unique),
 		"concat":   TwoArrayFunction(Concat),
 		"filter":   MinAndMaxArgsCheck(2, 2, Filter),
+		"nearest":  InitialArrayFunction(1, 1, Nearest),
 
 		// encoded text functions
 		"urn_parts":        OneTextFunction(URNParts),
@@ -200,16 +201,24 @@ func Boolean(env envs.Environment, value types.XValue) types.XValue {
 
 // Number tries to convert `value` to a number.
 //
-// An error is returned if the value can't be converted.
+// If the environment has `input_number_words` enabled, text can also be a whole number written
+// out in words in the default language, e.g. "twenty five". An error is returned if the value
+// can't be converted.
 //
 //	@(number(10)) -> 10
 //	@(number("123.45000")) -> 123.45
+//	@(number("twenty five")) -> ERROR
 //	@(number("what?")) -> ERROR
 //
 // @function number(value)
 func Number(env envs.Environment, value types.XValue) types.XValue {
 	num, xerr := types.ToXNumber(env, value)
 	if xerr != nil {
+		if text, isText := value.(*types.XText); isText && env.InputNumberWords() {
+			if n, found := envs.ParseNumberWords(env.DefaultLanguage(), text.Native()); found {
+				return types.NewXNumberFromInt64(n)
+			}
+		}
 		return xerr
 	}
 	return num
@@ -1190,11 +1199,15 @@ func RandBetween(env envs.Environment, min *types.XNumber, max *types.XNumber) t
 // You should only specify fractional seconds when you want to assert the number of places
 // in the input format.
 //
+// If the environment has `input_relative_dates` enabled then text which doesn't match the format can
+// also be a date written relative to today in the default language, e.g. "tomorrow at 10:30".
+//
 // parse_datetime will return an error if it is unable to convert the text to a datetime.
 //
 //	@(parse_datetime("1979-07-18", "YYYY-MM-DD")) -> 1979-07-18T00:00:00.000000-05:00
 //	@(parse_datetime("2010 5 10", "YYYY M DD")) -> 2010-05-10T00:00:00.000000-05:00
 //	@(parse_datetime("2010 5 10 12:50", "YYYY M DD tt:mm", "America/Los_Angeles")) -> 2010-05-10T12:50:00.000000-07:00
+//	@(parse_datetime("tomorrow at 10:30", "YYYY-MM-DD tt:mm")) -> ERROR
 //	@(parse_datetime("NOT DATE", "YYYY-MM-DD")) -> ERROR
 //
 // @function parse_datetime(text, format [,timezone])
@@ -1227,6 +1240,12 @@ func ParseDateTime(ctx context.Context, env envs.Environment, args ...types.XVal
 	// finally try to parse the date
 	parsed, err := dates.ParseDateTime(layout.Native(), str.Native(), location)
 	if err != nil {
+		if env.InputRelativeDates() {
+			if date, remainder, relErr := envs.FindRelativeDate(env, str.Native()); relErr == nil {
+				timeOfDay, _ := envs.TimeFromString(remainder)
+				return types.NewXDateTime(date.Combine(timeOfDay, location))
+			}
+		}
 		return types.NewXError(err)
 	}
 
@@ -1771,6 +1790,80 @@ func Concat(env envs.Environment, array1 *types.XArray, array2 *types.XArray) ty
 	return types.NewXArray(both...)
 }
 
+// Nearest returns the item in `array` which is nearest to `location`, e.g. a shared `geo:<lat>,<long>` attachment.
+//
+// Items can be locations as text, or objects with `latitude` and `longitude` properties (or `lat` and `lng`), such as
+// the items of an array returned by a webhook. Items without a valid location are ignored.
+//
+//	@(nearest(array("-1.95,30.06", "-1.29,36.82"), "geo:-1.9441,30.0619")) -> -1.95,30.06
+//	@(nearest(array(object("name", "Central", "lat", -1.95, "lng", 30.06), object("name", "Nairobi", "lat", -1.29, "lng", 36.82)), "geo:-1.3,36.8").name) -> Nairobi
+//	@(nearest(array("nowhere"), "geo:-1.9441,30.0619")) -> ERROR
+//	@(nearest(array("-1.95,30.06"), "nowhere")) -> ERROR
+//
+// @function nearest(array, location)
+func Nearest(env envs.Environment, array *types.XArray, args ...types.XValue) types.XValue {
+	text, xerr := types.ToXText(env, args[0])
+	if xerr != nil {
+		return xerr
+	}
+
+	origin, found := utils.ParseGeoPoint(text.Native())
+	if !found {
+		return types.NewXErrorf("%s is not a valid location", text.Describe())
+	}
+
+	var nearest types.XValue
+	nearestDistance := math.MaxFloat64
+
+	for i := range array.Count() {
+		item := array.Get(i)
+
+		if point, found := geoPointFromValue(env, item); found {
+			if distance := origin.DistanceTo(point); distance < nearestDistance {
+				nearest, nearestDistance = item, distance
+			}
+		}
+	}
+
+	if nearest == nil {
+		return types.NewXErrorf("array has no items with valid locations")
+	}
+	return nearest
+}
+
+// extracts a point from a value which is either an object with coordinate properties, or text containing a location
+func geoPointFromValue(env envs.Environment, value types.XValue) (utils.GeoPoint, bool) {
+	if obj, isObject := value.(*types.XObject); isObject {
+		lat := firstNumberProperty(env, obj, "latitude", "lat")
+		lng := firstNumberProperty(env, obj, "longitude", "lng", "lon", "long")
+		if lat == nil || lng == nil {
+			return utils.GeoPoint{}, false
+		}
+
+		latF, _ := lat.Native().Float64()
+		lngF, _ := lng.Native().Float64()
+		return utils.NewGeoPoint(latF, lngF)
+	}
+
+	text, xerr := types.ToXText(env, value)
+	if xerr != nil {
+		return utils.GeoPoint{}, false
+	}
+	return utils.ParseGeoPoint(text.Native())
+}
+
+// returns the value of the first of the given properties which is a number
+func firstNumberProperty(env envs.Environment, obj *types.XObject, names ...string) *types.XNumber {
+	for _, name := range names {
+		if value, exists := obj.Get(name); exists {
+			if num, xerr := types.ToXNumber(env, value); xerr == nil {
+				return num
+			}
+		}
+	}
+	return nil
+}
+
 // Filter returns a new array with the items from `array` that when passed to `func` return true.
 //
 //	@(filter(array(1, 0, 2), boolean)) -> [1, 2]
@@ -45,6 +45,14 @@ func TestFunctions(t *testing.T) {
 		WithTimeFormat(envs.TimeFormatHourMinuteAmPm).
 		WithTimezone(la).
 		Build()
+	wrd := envs.NewBuilder().
+		WithAllowedLanguages("eng").
+		WithInputNumberWords(true).
+		Build()
+	rel := envs.NewBuilder().
+		WithAllowedLanguages("eng").
+		WithInputRelativeDates(true).
+		Build()
 
 	// inputs for testing array size limits
 	xitems := func(n int) []types.XValue {
@@ -171,6 +179,7 @@ func TestFunctions(t *testing.T) {
 		{"datetime", dmy, []types.XValue{xs("01-12-2017 10:15pm")}, xdt(time.Date(2017, 12, 1, 22, 15, 0, 0, time.UTC))},
 		{"datetime", dmy, []types.XValue{xs("01.15.2017")}, ERROR}, // month out of range
 		{"datetime", dmy, []types.XValue{xs("no date")}, ERROR},    // invalid date
+		{"datetime", rel, []types.XValue{xs("tomorrow")}, ERROR},   // relative dates only parsed by parse_datetime
 		{"datetime", dmy, []types.XValue{}, ERROR},
 
 		{"datetime_add", dmy, []types.XValue{xs("03-12-2017 10:15pm"), xs("2"), xs("Y")}, xdt(time.Date(2019, 12, 03, 22, 15, 0, 0, time.UTC))},
@@ -478,12 +487,36 @@ func TestFunctions(t *testing.T) {
 		{"mod", dmy, []types.XValue{xs("9"), xs("not_num")}, ERROR},
 		{"mod", dmy, []types.XValue{}, ERROR},
 
+		{"nearest", dmy, []types.XValue{xa(xs("-1.95,30.06"), xs("geo:-1.29,36.82")), xs("geo:-1.3,36.8")}, xs("geo:-1.29,36.82")},
+		{"nearest", dmy, []types.XValue{xa(xs("nowhere"), xs("-1.95,30.06")), xs("-1.9441,30.0619")}, xs("-1.95,30.06")},
+		{
+			"nearest",
+			dmy,
+			[]types.XValue{
+				xa(
+					xo(map[string]types.XValue{"name": xs("Kigali"), "Latitude": xn("-1.95"), "Longitude": xn("30.06")}),
+					xo(map[string]types.XValue{"name": xs("Nairobi"), "lat": xs("-1.29"), "lng": xs("36.82")}),
+					xo(map[string]types.XValue{"name": xs("Nowhere")}),
+				),
+				xs("geo:-1.9441,30.0619"),
+			},
+			xo(map[string]types.XValue{"name": xs("Kigali"), "Latitude": xn("-1.95"), "Longitude": xn("30.06")}),
+		},
+		{"nearest", dmy, []types.XValue{xa(), xs("geo:-1.9441,30.0619")}, ERROR},
+		{"nearest", dmy, []types.XValue{xa(xs("nowhere")), xs("geo:-1.9441,30.0619")}, ERROR},
+		{"nearest", dmy, []types.XValue{xa(xs("-1.95,30.06")), xs("nowhere")}, ERROR},
+		{"nearest", dmy, []types.XValue{xa(xs("-1.95,30.06")), ERROR}, ERROR},
+		{"nearest", dmy, []types.XValue{ERROR, xs("geo:-1.9441,30.0619")}, ERROR},
+
 		{"now", dmy, []types.XValue{}, xdt(time.Date(2018, 4, 11, 13, 24, 30, 123456000, time.UTC))},
 		{"now", dmy, []types.XValue{ERROR}, ERROR},
 
 		{"number", dmy, []types.XValue{xn("10")}, xn("10")},
 		{"number", dmy, []types.XValue{xs("123.45000")}, xn("123.45")},
 		{"number", dmy, []types.XValue{xs("what?")}, ERROR},
+		{"number", dmy, []types.XValue{xs("twenty five")}, ERROR},
+		{"number", wrd, []types.XValue{xs("twenty five")}, xn("25")},
+		{"number", wrd, []types.XValue{xs("twenty five cows")}, ERROR},
 
 		{"object", dmy, []types.XValue{xs("foo"), xs("hello"), xs("bar"), xi(123)}, types.NewXObject(map[string]types.XValue{"foo": xs("hello"), "bar": xi(123)})},
 		{"object", dmy, []types.XValue{xi(0), xs("hello")}, types.NewXObject(map[string]types.XValue{"0": xs("hello")})},
@@ -517,6 +550,10 @@ func TestFunctions(t *testing.T) {
 		{"parse_datetime", dmy, []types.XValue{xs("1977-06-23 15:34"), ERROR}, ERROR},                          // error as format
 		{"parse_datetime", dmy, []types.XValue{xs("1977-06-23 15:34"), xs("YYYY-MM-DD"), ERROR}, ERROR},        // error as timezone
 		{"parse_datetime", dmy, []types.XValue{}, ERROR},
+		{"parse_datetime", dmy, []types.XValue{xs("tomorrow"), xs("YYYY-MM-DD")}, ERROR}, // relative dates not enabled
+		{"parse_datetime", rel, []types.XValue{xs("tomorrow"), xs("YYYY-MM-DD")}, xdt(time.Date(2018, 4, 12, 0, 0, 0, 0, time.UTC))},
+		{"parse_datetime", rel, []types.XValue{xs("in 3 days at 15:30"), xs("YYYY-MM-DD"), xs("America/Los_Angeles")}, xdt(time.Date(2018, 4, 14, 15, 30, 0, 0, la))},
+		{"parse_datetime", rel, []types.XValue{xs("abcd"), xs("YYYY-MM-DD")}, ERROR},
 
 		{"parse_json", dmy, []types.XValue{xs(`"hello"`)}, xs(`hello`)},
 		{"parse_json", dmy, []types.XValue{xs(`{a: b}`)}, ERROR},
@@ -1,9 +1,11 @@
 package refactor
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/nyaruka/goflow/excellent"
+	"github.com/nyaruka/goflow/excellent/types"
 )
 
 // ContextRefRename returns a transformation function that renames context references
@@ -19,3 +21,47 @@ func ContextRefRename(from, to string) func(excellent.Expression) bool {
 		return changed
 	}
 }
+
+// ContextLookupRename returns a transformation function that renames lookups of a key on any of the given
+// context paths, e.g. renaming age to years on the path fields changes @fields.age to @fields.years
+func ContextLookupRename(paths [][]string, from, to string) func(excellent.Expression) bool {
+	matchesPath := func(container excellent.Expression) bool {
+		path := contextPath(container)
+		if path == nil {
+			return false
+		}
+		return slices.ContainsFunc(paths, func(p []string) bool { return slices.Equal(p, path) })
+	}
+
+	return func(exp excellent.Expression) bool {
+		changed := false
+		exp.Visit(func(e excellent.Expression) {
+			switch typed := e.(type) {
+			case *excellent.DotLookup:
+				if strings.EqualFold(typed.Lookup, from) && matchesPath(typed.Container) {
+					typed.Lookup = to
+					changed = true
+				}
+			case *excellent.ArrayLookup:
+				if asText, isText := typed.Lookup.(*excellent.TextLiteral); isText && strings.EqualFold(asText.Value.Native(), from) && matchesPath(typed.Container) {
+					typed.Lookup = &excellent.TextLiteral{Value: types.NewXText(to)}
+					changed = true
+				}
+			}
+		})
+		return changed
+	}
+}
+
+// gets the lowercased context path of the given expression if it's a chain of dot lookups on a context reference
+func contextPath(exp excellent.Expression) []string {
+	switch typed := exp.(type) {
+	case *excellent.ContextReference:
+		return []string{strings.ToLower(typed.Name)}
+	case *excellent.DotLookup:
+		if path := contextPath(typed.Container); path != nil {
+			return append(path, strings.ToLower(typed.Lookup))
+		}
+	}
+	return nil
+}
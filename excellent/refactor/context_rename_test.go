@@ -31,3 +31,31 @@ func TestContextRefRename(t *testing.T) {
 		assert.Equal(t, tc.expected, actual, "refactor mismatch for template: %s", tc.template)
 	}
 }
+
+func TestContextLookupRename(t *testing.T) {
+	fieldPaths := [][]string{{"fields"}, {"contact", "fields"}}
+
+	tcs := []struct {
+		template string
+		expected string
+	}{
+		{"@fields.age", "@fields.years"},
+		{"@FIELDS.Age", "@fields.years"},
+		{"@contact.fields.age", "@contact.fields.years"},
+		{"@(fields.age + 1) @(contact.fields.age)", "@(fields.years + 1) @(contact.fields.years)"},
+		{`@(fields["age"] & upper(fields.age))`, `@(fields["years"] & upper(fields.years))`},
+		{"@fields.gender @fields.age_group", "@fields.gender @fields.age_group"},
+		{"@(results.age)", "@(results.age)"}, // not on a matching path
+		{"@(contact.age)", "@(contact.age)"},
+		{"@(parent.fields.age)", "@(parent.fields.age)"},
+		{"@(fields[age])", "@(fields[age])"}, // lookup isn't a literal
+	}
+
+	topLevels := []string{"contact", "fields", "parent", "results"}
+
+	for _, tc := range tcs {
+		actual, err := refactor.Template(tc.template, topLevels, refactor.ContextLookupRename(fieldPaths, "age", "years"))
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, actual, "refactor mismatch for template: %s", tc.template)
+	}
+}
@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/nyaruka/goflow/excellent"
+	"github.com/nyaruka/goflow/excellent/functions"
+)
+
+// FindCustomFunctionCallsInTemplate finds calls in the given template to functions which aren't builtin functions
+// and so must be custom functions. Names are returned lowercased as function names are case-insensitive.
+func FindCustomFunctionCallsInTemplate(template string, allowedTopLevels []string, callback func(string)) error {
+	return excellent.VisitTemplate(template, allowedTopLevels, false, func(tokenType excellent.XTokenType, token string) error {
+		if tokenType != excellent.EXPRESSION {
+			return nil
+		}
+
+		parsed, err := excellent.Parse(token, nil)
+		if err != nil {
+			return nil
+		}
+
+		// names which are arguments of anonymous functions can be called but aren't custom functions
+		anonArgs := make([]string, 0)
+		calls := make([]string, 0)
+
+		parsed.Visit(func(x excellent.Expression) {
+			switch typed := x.(type) {
+			case *excellent.AnonFunction:
+				for _, a := range typed.Args {
+					anonArgs = append(anonArgs, strings.ToLower(a))
+				}
+			case *excellent.FunctionCall:
+				if ref, isRef := typed.Func.(*excellent.ContextReference); isRef {
+					calls = append(calls, strings.ToLower(ref.Name))
+				}
+			}
+		})
+
+		for _, name := range calls {
+			if functions.Lookup(name) == nil && !slices.Contains(anonArgs, name) {
+				callback(name)
+			}
+		}
+		return nil
+	})
+}
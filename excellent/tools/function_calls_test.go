@@ -0,0 +1,34 @@
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/nyaruka/goflow/excellent/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindCustomFunctionCallsInTemplate(t *testing.T) {
+	testCases := []struct {
+		template string
+		names    []string
+	}{
+		{``, []string{}},
+		{`Hi @foo @(upper(foo.bar))`, []string{}},
+		{`@(national_id(foo.id))`, []string{"national_id"}},
+		{`@(UPPER(Greet(foo, Greet(foo))))`, []string{"greet", "greet"}},
+		{`@(foreach(foo.list, (f) => f(1)))`, []string{}},
+		{`@(foo.bar(1))`, []string{}},
+		{`@(national_id(foo.id`, []string{}},
+	}
+
+	for _, tc := range testCases {
+		actual := make([]string, 0)
+
+		err := tools.FindCustomFunctionCallsInTemplate(tc.template, []string{"foo"}, func(name string) {
+			actual = append(actual, name)
+		})
+
+		assert.NoError(t, err, "unexpected error for template: %s", tc.template)
+		assert.Equal(t, tc.names, actual, "function calls mismatch for input: %s", tc.template)
+	}
+}
@@ -42,6 +42,10 @@ type ContextReference struct {
 func (x *ContextReference) Evaluate(ctx context.Context, env envs.Environment, scope *Scope, warnings *Warnings) types.XValue {
 	value, exists := scope.Get(x.Name)
 	if !exists {
+		// could be a call to a custom function
+		if fn := lookupCustomFunction(ctx, x.Name); fn != nil {
+			return fn
+		}
 		return types.NewXErrorf("context has no property '%s'", x.Name)
 	}
 
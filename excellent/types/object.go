@@ -32,6 +32,7 @@ type XObject struct {
 
 	marshalDefault    bool
 	marshalDeprecated bool
+	marshalAsDefault  bool
 }
 
 // NewXObject returns a new object with the given properties
@@ -97,6 +98,14 @@ func (x *XObject) Format(env envs.Environment) string {
 
 // MarshalJSON converts this type to internal JSON
 func (x *XObject) MarshalJSON() ([]byte, error) {
+	if x.hasDefault() && x.marshalAsDefault {
+		asJSON, err := ToXJSON(x.def)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(asJSON.Native()), nil
+	}
+
 	marshaled := make(map[string]json.RawMessage, x.Count())
 	for p, v := range x.properties() {
 		if v == nil || x.marshalDeprecated || v.Deprecated() == "" {
@@ -217,6 +226,12 @@ func (x *XObject) SetMarshalOptions(includeDefault, includeDeprecated bool) {
 	x.marshalDeprecated = includeDeprecated
 }
 
+// SetMarshalAsDefault sets whether this object is marshaled as just its default value, e.g. to keep the JSON of an
+// object which replaced a simpler value unchanged
+func (x *XObject) SetMarshalAsDefault(asDefault bool) {
+	x.marshalAsDefault = asDefault
+}
+
 // Default returns the default value for this
 func (x *XObject) hasDefault() bool {
 	return x.Default() != x
@@ -111,6 +111,12 @@ func TestXObjectWithDefault(t *testing.T) {
 	asJSON, _ = types.ToXJSON(object)
 	assert.Equal(t, types.NewXText(`{"__default__":"abc-123","bar":123,"foo":"abc","zed":false}`), asJSON)
 
+	// or be marshaled as just its default
+	object.SetMarshalAsDefault(true)
+	asJSON, _ = types.ToXJSON(object)
+	assert.Equal(t, types.NewXText(`"abc-123"`), asJSON)
+	object.SetMarshalAsDefault(false)
+
 	// test equality
 	test.AssertXEqual(t, object, types.NewXObject(map[string]types.XValue{
 		"__default__": types.NewXText("abc-123"),
@@ -2,6 +2,7 @@ package actions
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -81,7 +82,7 @@ func (a *baseAction) Inspect(dependency func(assets.Reference), local func(strin
 func (a *baseAction) LocalizationUUID() uuids.UUID { return uuids.UUID(a.UUID_) }
 
 // helper function for actions that send a message (text + attachments) that must be localized and evalulated
-func (a *baseAction) evaluateMessage(ctx context.Context, run flows.Run, languages []i18n.Language, actionText string, actionAttachments []string, actionQuickReplies []string, log events.EventLogger) (*core.MsgContent, i18n.Language) {
+func (a *baseAction) evaluateMessage(ctx context.Context, run flows.Run, languages []i18n.Language, actionText string, actionAttachments []string, actionQuickReplies []string, actionButtons []*flows.Button, actionList *flows.List, log events.EventLogger) (*core.MsgContent, i18n.Language) {
 	// localize and evaluate the message text
 	localizedText, txtLang := run.GetTextArray(uuids.UUID(a.UUID()), "text", []string{actionText}, languages)
 	evaluatedText, _ := run.EvaluateTemplate(ctx, localizedText[0], log)
@@ -121,18 +122,137 @@ func (a *baseAction) evaluateMessage(ctx context.Context, run flows.Run, languag
 		evaluatedQuickReplies = append(evaluatedQuickReplies, quickReply)
 	}
 
+	// localize and evaluate the buttons and list
+	evaluatedButtons, btnsLang := evaluateButtons(ctx, run, languages, actionButtons, log)
+	evaluatedList, listLang := evaluateList(ctx, run, languages, actionList, log)
+
 	// although it's possible for the different parts of the message to have different languages, we want to resolve
-	// a single language based on what the user actually provided for this message
-	var lang i18n.Language
+	// a single language based on what the user actually provided for this message, preferring parts which were
+	// translated, e.g. a message where only the buttons were translated is in the language of the buttons
+	provided := make([]i18n.Language, 0, 5)
 	if localizedText[0] != "" {
-		lang = txtLang
-	} else if len(translatedAttachments) > 0 {
-		lang = attLang
-	} else if len(translatedQuickReplies) > 0 {
-		lang = qrsLang
+		provided = append(provided, txtLang)
+	}
+	if len(translatedAttachments) > 0 {
+		provided = append(provided, attLang)
+	}
+	if len(translatedQuickReplies) > 0 {
+		provided = append(provided, qrsLang)
+	}
+	if len(actionButtons) > 0 {
+		provided = append(provided, btnsLang)
+	}
+	if actionList != nil {
+		provided = append(provided, listLang)
+	}
+
+	var lang i18n.Language
+	for _, l := range provided {
+		if l != run.Flow().Language() {
+			lang = l
+			break
+		}
+	}
+	if lang == i18n.NilLanguage && len(provided) > 0 {
+		lang = provided[0]
+	}
+
+	return &core.MsgContent{Text: evaluatedText, Attachments: evaluatedAttachments, QuickReplies: evaluatedQuickReplies, Buttons: evaluatedButtons, List: evaluatedList}, lang
+}
+
+// helper function to localize and evaluate the interactive buttons of a message, returning the language of the first
+func evaluateButtons(ctx context.Context, run flows.Run, languages []i18n.Language, actionButtons []*flows.Button, log events.EventLogger) ([]core.MsgButton, i18n.Language) {
+	evaluated := make([]core.MsgButton, 0, len(actionButtons))
+	var lang i18n.Language
+
+	for i, b := range actionButtons {
+		text, textLang := evaluateLocalized(ctx, run, languages, b, "text", b.Text, log)
+		if i == 0 {
+			lang = textLang
+		}
+		if text == "" {
+			log(events.NewError("Button text evaluated to empty string and will be ignored", ""))
+			continue
+		}
+
+		button := core.MsgButton{Type: b.Type, Text: stringsx.TruncateEllipsis(text, core.MaxButtonTextLength)}
+
+		if b.Type == flows.ButtonTypeURL {
+			button.URL, _ = evaluateLocalized(ctx, run, languages, b, "url", b.URL, log)
+
+			if !isValidButtonURL(button.URL) {
+				log(events.NewError("Button URL evaluated to invalid value and will be ignored", ""))
+				continue
+			}
+		} else {
+			payload, _ := run.EvaluateTemplate(ctx, b.Payload, log)
+			button.Payload = stringsx.Truncate(strings.TrimSpace(payload), core.MaxButtonPayloadLength)
+		}
+
+		evaluated = append(evaluated, button)
+	}
+
+	return evaluated, lang
+}
+
+// helper function to localize and evaluate the interactive list of a message, returning the language of its button
+func evaluateList(ctx context.Context, run flows.Run, languages []i18n.Language, actionList *flows.List, log events.EventLogger) (*core.MsgList, i18n.Language) {
+	if actionList == nil {
+		return nil, i18n.NilLanguage
+	}
+
+	sections := make([]core.MsgListSection, 0, len(actionList.Sections))
+
+	for _, s := range actionList.Sections {
+		rows := make([]core.MsgListRow, 0, len(s.Rows))
+
+		for _, r := range s.Rows {
+			title, _ := evaluateLocalized(ctx, run, languages, r, "title", r.Title, log)
+			if title == "" {
+				log(events.NewError("List row title evaluated to empty string and will be ignored", ""))
+				continue
+			}
+
+			description, _ := evaluateLocalized(ctx, run, languages, r, "description", r.Description, log)
+			payload, _ := run.EvaluateTemplate(ctx, r.Payload, log)
+
+			rows = append(rows, core.MsgListRow{
+				Title:       stringsx.TruncateEllipsis(title, core.MaxButtonTextLength),
+				Description: stringsx.TruncateEllipsis(description, core.MaxListRowDescriptionLength),
+				Payload:     stringsx.Truncate(strings.TrimSpace(payload), core.MaxButtonPayloadLength),
+			})
+		}
+
+		if len(rows) > 0 {
+			title, _ := evaluateLocalized(ctx, run, languages, s, "title", s.Title, log)
+			sections = append(sections, core.MsgListSection{Title: stringsx.TruncateEllipsis(title, core.MaxButtonTextLength), Rows: rows})
+		}
+	}
+
+	if len(sections) == 0 {
+		log(events.NewError("List has no rows and will be ignored", ""))
+		return nil, i18n.NilLanguage
+	}
+
+	button, lang := evaluateLocalized(ctx, run, languages, actionList, "button", actionList.Button, log)
+
+	return &core.MsgList{Button: stringsx.TruncateEllipsis(button, core.MaxButtonTextLength), Sections: sections}, lang
+}
+
+// helper function to localize and evaluate a single text property of a localizable item, returning the language used
+func evaluateLocalized(ctx context.Context, run flows.Run, languages []i18n.Language, item flows.Localizable, property, native string, log events.EventLogger) (string, i18n.Language) {
+	if native == "" {
+		return "", i18n.NilLanguage
 	}
 
-	return &core.MsgContent{Text: evaluatedText, Attachments: evaluatedAttachments, QuickReplies: evaluatedQuickReplies}, lang
+	localized, lang := run.GetTextArray(item.LocalizationUUID(), property, []string{native}, languages)
+	evaluated, _ := run.EvaluateTemplate(ctx, localized[0], log)
+	return strings.TrimSpace(evaluated), lang
+}
+
+// checks that a button URL is an absolute http or https URL
+func isValidButtonURL(u string) bool {
+	return isValidURL(u) && (strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "http://"))
 }
 
 // helper to save a run result and log it as an event
@@ -164,13 +284,6 @@ func (a *baseAction) applyModifier(ctx context.Context, run flows.Run, mod flows
 	return modifiers.Apply(ctx, s.Engine(), s.MergedEnvironment(), s.Assets(), run.Contact(), mod, log)
 }
 
-// helper to log a failure
-func (a *baseAction) fail(run flows.Run, err string, log events.EventLogger) {
-	run.Exit(core.RunStatusFailed)
-	log(events.NewFailure(err))
-	log(events.NewRunEnded(run.UUID(), run.FlowReference(), core.RunStatusFailed))
-}
-
 // utility struct which sets the allowed flow types to any
 type universalAction struct{}
 
@@ -282,16 +395,71 @@ type createMsgAction struct {
 	Text              string                    `json:"text"                       validate:"required,max=10000"     engine:"localized,evaluated"`
 	Attachments       []string                  `json:"attachments,omitempty"      validate:"max=10,dive,attachment,max=8192" engine:"localized,evaluated"`
 	QuickReplies      []string                  `json:"quick_replies,omitempty"    validate:"max=10,dive,max=1000"   engine:"localized,evaluated"`
+	Buttons           []*flows.Button           `json:"buttons,omitempty"          validate:"max=10,dive"`
+	List              *flows.List               `json:"list,omitempty"`
 	Template          *assets.TemplateReference `json:"template,omitempty"`
 	TemplateVariables []string                  `json:"template_variables,omitempty" validate:"max=100,dive,max=10000" engine:"evaluated"`
 }
 
+// validates the interactive content of the message
+func (a *createMsgAction) validate() error {
+	numInteractive := 0
+	for _, has := range []bool{len(a.QuickReplies) > 0, len(a.Buttons) > 0, a.List != nil} {
+		if has {
+			numInteractive++
+		}
+	}
+	if numInteractive > 1 {
+		return errors.New("messages can only have one of quick replies, buttons or a list")
+	}
+
+	for _, b := range a.Buttons {
+		if err := b.Validate(); err != nil {
+			return fmt.Errorf("invalid button[uuid=%s]: %s", b.UUID, err)
+		}
+	}
+	if a.List != nil {
+		return a.List.Validate()
+	}
+	return nil
+}
+
 func (a *createMsgAction) Inspect(dependency func(assets.Reference), local func(string), result func(*flows.ResultInfo)) {
 	if a.Template != nil {
 		dependency(a.Template)
 	}
 }
 
+// helper function for actions that create messages to determine if the message can be sent to the current contact
+func checkSendable(ctx context.Context, run flows.Run, content *core.MsgContent) (core.UnsendableReason, error) {
+	switch run.Contact().Status() {
+	case core.ContactStatusBlocked:
+		return core.UnsendableReasonContactBlocked, nil
+	case core.ContactStatusStopped:
+		return core.UnsendableReasonContactStopped, nil
+	case core.ContactStatusArchived:
+		return core.UnsendableReasonContactArchived, nil
+	}
+
+	reason, err := run.Session().Engine().Options().CheckSendable(ctx, run.Session().Assets(), run.Contact(), content)
+	if err != nil {
+		return "", fmt.Errorf("error checking if message is sendable: %w", err)
+	}
+	return reason, nil
+}
+
+// helper function for actions that create messages to defer them if we're currently outside of the send windows
+func deferToSendWindow(run flows.Run, msg *core.MsgOut) {
+	if msg.UnsendableReason() != "" || len(flows.SendWindows(run)) == 0 {
+		return
+	}
+
+	now := dates.Now()
+	if next := flows.NextSendTime(run, now); next.After(now) {
+		msg.SetSendAfter(next)
+	}
+}
+
 // helper function for actions that have a set of group references that must be resolved to actual groups
 func resolveGroups(ctx context.Context, run flows.Run, references []*assets.GroupReference, log events.EventLogger) []*core.Group {
 	groupAssets := run.Session().Assets().Groups()
@@ -131,18 +131,18 @@ func (a *CallResthook) Execute(ctx context.Context, run flows.Run, step flows.St
 		call, err := svc.Call(req)
 
 		if err != nil {
-			logCallError(err, run, log)
+			logCallError(err, run, nil, log)
 		}
 		if call != nil {
 			calls = append(calls, call)
-			log(events.NewWebhookCalled(call, callStatus(call, nil, true), a.Resthook))
+			log(events.NewWebhookCalled(call, callStatus(call, nil, true), a.Resthook, nil))
 		}
 	}
 
 	asResult := a.pickResultCall(calls)
 	var call *flows.WebhookCall
 	if asResult != nil {
-		call = flows.NewWebhookCall(asResult)
+		call = flows.NewWebhookCall(asResult, nil)
 	}
 
 	run.SetWebhook(call)
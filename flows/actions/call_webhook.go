@@ -15,6 +15,7 @@ import (
 	"github.com/nyaruka/goflow/assets"
 	"github.com/nyaruka/goflow/core"
 	"github.com/nyaruka/goflow/core/events"
+	"github.com/nyaruka/goflow/excellent/tools"
 	"github.com/nyaruka/goflow/flows"
 	"golang.org/x/net/http/httpguts"
 )
@@ -47,6 +48,11 @@ const TypeCallWebhook string = "call_webhook"
 // templates and will be evaluated at runtime. A [event:webhook_called] event will be created based on
 // the results of the HTTP call.
 //
+// Secret globals are only evaluated to their actual values in the query of the url and in header values, and are
+// masked in the event and in `@webhook`. They can only be used as they are, e.g. `@globals.token`, because values
+// derived from them by functions can't be masked, so a call with a url or header like `@(upper(globals.token))` isn't
+// made.
+//
 // This action always updates the `@webhook` context value: if a call was made it will be the result
 // of that call, and if not (e.g. the URL evaluated to something invalid) it will be cleared.
 //
@@ -107,26 +113,45 @@ func (a *CallWebhook) Execute(ctx context.Context, run flows.Run, step flows.Ste
 	// left holding the result of a previous call
 	run.SetWebhook(nil)
 
-	url, _ := run.EvaluateTemplate(ctx, a.URL, log)
-	url = strings.TrimSpace(url)
+	// secret globals can only be revealed in the query of the URL and in header values
+	redact := run.Session().Assets().Globals().Redactor()
+
+	masked, _ := run.EvaluateTemplate(ctx, a.URL, log)
+	masked = strings.TrimSpace(masked)
 
-	if url == "" {
+	if masked == "" {
 		log(events.NewError("Webhook URL evaluated to empty string", ""))
 		return nil
 	}
 
+	url, ok := revealSecrets(ctx, run, a.URL, masked, true, redact)
+	if !ok {
+		log(events.NewError("Secret globals can only be used as they are, without being passed to functions", ""))
+		return nil
+	}
+
 	if !isValidURL(url) {
-		truncated := stringsx.TruncateEllipsis(url, 255)
+		truncated := stringsx.TruncateEllipsis(redactWith(redact, url), 255)
 		log(events.NewError(fmt.Sprintf("Webhook URL evaluated to an invalid URL: '%s'", truncated), events.ErrorCodeURLInvalid, "url", truncated))
 		return nil
 	}
 
+	if urlBase(url) != urlBase(masked) {
+		log(events.NewError("Secret globals can only be used in the query of webhook URLs", ""))
+		return nil
+	}
+
 	method := strings.ToUpper(a.Method)
 
 	// substitute any header variables
 	headers := make(map[string]string, len(a.Headers))
 	for key, value := range a.Headers {
-		headers[key], _ = run.EvaluateTemplate(ctx, value, log)
+		masked, _ := run.EvaluateTemplate(ctx, value, log)
+
+		if headers[key], ok = revealSecrets(ctx, run, value, masked, false, redact); !ok {
+			log(events.NewError("Secret globals can only be used as they are, without being passed to functions", ""))
+			return nil
+		}
 	}
 
 	body := a.Body
@@ -145,19 +170,19 @@ func (a *CallWebhook) Execute(ctx context.Context, run flows.Run, step flows.Ste
 		return nil
 	}
 
-	call := a.call(ctx, run, step, url, method, headers, body, log)
+	call := a.call(ctx, run, step, url, method, headers, body, redact, log)
 	run.SetWebhook(call)
 
 	return nil
 }
 
 // Execute runs this action
-func (a *CallWebhook) call(ctx context.Context, run flows.Run, step flows.Step, url, method string, headers map[string]string, body string, log events.EventLogger) *flows.WebhookCall {
+func (a *CallWebhook) call(ctx context.Context, run flows.Run, step flows.Step, url, method string, headers map[string]string, body string, redact stringsx.Redactor, log events.EventLogger) *flows.WebhookCall {
 	// build our request
 	req, err := httpx.NewRequest(ctx, method, url, strings.NewReader(body), headers)
 	if err != nil {
 		// in theory this can't happen because we're already validating the method and the URL.. but just in case
-		log(events.NewRawError(err))
+		log(events.NewError(redactWith(redact, err.Error()), ""))
 		return nil
 	}
 
@@ -169,20 +194,21 @@ func (a *CallWebhook) call(ctx context.Context, run flows.Run, step flows.Step,
 
 	// the service decides which domains are blocked for this session's workspace
 	if svc.IsBlocked(req.URL) {
-		log(events.NewError(fmt.Sprintf("Webhook calls to %s are not allowed", req.URL.Hostname()), events.ErrorCodeURLBlocked, "hostname", req.URL.Hostname()))
+		hostname := redactWith(redact, req.URL.Hostname())
+		log(events.NewError(fmt.Sprintf("Webhook calls to %s are not allowed", hostname), events.ErrorCodeURLBlocked, "hostname", hostname))
 		return nil
 	}
 
 	trace, err := svc.Call(req)
 	if err != nil {
-		logCallError(err, run, log)
+		logCallError(err, run, redact, log)
 	}
 
 	if trace != nil {
-		call := flows.NewWebhookCall(trace)
+		call := flows.NewWebhookCall(trace, redact)
 		status := callStatus(trace, err, false)
 
-		log(events.NewWebhookCalled(trace, status, ""))
+		log(events.NewWebhookCalled(trace, status, "", redact))
 
 		if a.ResultName != "" {
 			a.saveLegacyWebhookResult(run, step, a.ResultName, call, status, log)
@@ -202,13 +228,55 @@ func (a *CallWebhook) Inspect(dependency func(assets.Reference), local func(stri
 
 // logs an error from the webhook service - a response exceeding the size limit is only a warning because the call
 // is still recorded (as a connection error) and the flow routes on that as usual
-func logCallError(err error, run flows.Run, log events.EventLogger) {
+func logCallError(err error, run flows.Run, redact stringsx.Redactor, log events.EventLogger) {
 	if errors.Is(err, httpx.ErrResponseSize) {
 		maxResponseBytes := run.Session().Engine().Options().MaxResponseBytes
 		log(events.NewWarning(fmt.Sprintf("Webhook response exceeded the limit of %d bytes", maxResponseBytes), events.WarningCodeWebhookResponseSize, "limit", strconv.Itoa(maxResponseBytes)))
 	} else {
-		log(events.NewRawError(err))
+		log(events.NewError(redactWith(redact, err.Error()), ""))
+	}
+}
+
+// re-evaluates a template with the actual values of secret globals. Because the redactor can only mask the exact values
+// of secrets, this fails if the revealed value can't be masked back to the given masked value, e.g. the template is
+// @(upper(globals.token)), so that derived values can't be leaked.
+func revealSecrets(ctx context.Context, run flows.Run, template, masked string, trim bool, redact stringsx.Redactor) (string, bool) {
+	if redact == nil || !referencesGlobals(template) {
+		return masked, true
+	}
+
+	// evaluation errors have already been logged by evaluating the masked value
+	revealed, _ := run.EvaluateTemplate(core.WithSecretsRevealed(ctx), template, func(events.Event) {})
+	if trim {
+		revealed = strings.TrimSpace(revealed)
+	}
+
+	return revealed, redact(revealed) == masked
+}
+
+// checks whether the given template references any globals
+func referencesGlobals(template string) bool {
+	found := false
+	tools.FindContextRefsInTemplate(template, flows.RunContextTopLevels, func(path []string) {
+		if strings.EqualFold(path[0], "globals") {
+			found = true
+		}
+	})
+	return found
+}
+
+// gets the part of a URL before its query
+func urlBase(u string) string {
+	base, _, _ := strings.Cut(u, "?")
+	return base
+}
+
+// applies the given redactor to s if there is one
+func redactWith(redact stringsx.Redactor, s string) string {
+	if redact == nil {
+		return s
 	}
+	return redact(s)
 }
 
 // determines the webhook status from the HTTP status code
@@ -3,6 +3,8 @@ package actions
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/nyaruka/goflow/assets"
 	"github.com/nyaruka/goflow/core/events"
@@ -18,13 +20,19 @@ const TypeEnterFlow string = "enter_flow"
 
 // EnterFlow can be used to start a contact down another flow. The current flow will pause until the subflow exits or expires.
 //
+// Values can be passed to the parameters declared by the subflow with `params`, which maps parameter names to
+// templates. These are converted to the declared types and are accessible in the subflow as `@params`, and the
+// values the subflow returns are accessible afterwards as `@child.returns`.
+//
 // A [event:run_started] event will be created to record that a new run was started. If the flow
-// doesn't exist or its type can't be entered from the current session, the run will end in failure.
+// doesn't exist, its type can't be entered from the current session, or its parameters can't be bound, the run will
+// end in failure.
 //
 //	{
 //	  "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
 //	  "type": "enter_flow",
-//	  "flow": {"uuid": "b7cf0d83-f1c9-411c-96fd-c511a4cfa86d", "name": "Collect Language"},
+//	  "flow": {"uuid": "b7cf0d83-f1c9-411c-96fd-c511a4cfa86d", "name": "Collect Age"},
+//	  "params": {"min_age": "@(fields.age + 1)"},
 //	  "terminal": false
 //	}
 //
@@ -33,15 +41,17 @@ type EnterFlow struct {
 	baseAction
 	universalAction
 
-	Flow     *assets.FlowReference `json:"flow" validate:"required"`
+	Flow     *assets.FlowReference `json:"flow"               validate:"required"`
+	Params   map[string]string     `json:"params,omitempty"   validate:"max=20,dive,keys,local_ref,endkeys,max=1000" engine:"evaluated"`
 	Terminal bool                  `json:"terminal,omitempty"`
 }
 
 // NewEnterFlow creates a new start flow action
-func NewEnterFlow(uuid flows.ActionUUID, flow *assets.FlowReference, terminal bool) *EnterFlow {
+func NewEnterFlow(uuid flows.ActionUUID, flow *assets.FlowReference, params map[string]string, terminal bool) *EnterFlow {
 	return &EnterFlow{
 		baseAction: newBaseAction(TypeEnterFlow, uuid),
 		Flow:       flow,
+		Params:     params,
 		Terminal:   terminal,
 	}
 }
@@ -52,19 +62,58 @@ func (a *EnterFlow) Execute(ctx context.Context, run flows.Run, step flows.Step,
 
 	// we ignore other missing asset types but a missing flow means we don't know how to route so we can't continue
 	if err != nil {
-		a.fail(run, fmt.Sprintf("Unable to find flow '%s'", a.Flow.Name), log)
+		run.Fail(fmt.Sprintf("Unable to find flow '%s'", a.Flow.Name), log)
 		return nil
 	}
 
 	if run.Session().Type() != flow.Type() && flow.Type() != flows.FlowTypeMessagingBackground {
-		a.fail(run, fmt.Sprintf("Can't enter %s of type %s from type %s", flow.Reference(false), flow.Type(), run.Session().Type()), log)
+		run.Fail(fmt.Sprintf("Can't enter %s of type %s from type %s", flow.Reference(false), flow.Type(), run.Session().Type()), log)
+		return nil
+	}
+
+	params, ok := a.bindParams(ctx, run, flow, log)
+	if !ok {
 		return nil
 	}
 
-	run.Session().PushFlow(flow, run, a.Terminal)
+	run.Session().PushFlow(flow, run, a.Terminal, params)
 	return nil
 }
 
+// evaluates our parameter templates and converts them to the types declared by the given flow
+func (a *EnterFlow) bindParams(ctx context.Context, run flows.Run, flow flows.Flow, log events.EventLogger) (flows.ParamValues, bool) {
+	env := run.Session().MergedEnvironment()
+	values := make(flows.ParamValues, len(a.Params))
+
+	for _, name := range slices.Sorted(maps.Keys(a.Params)) {
+		param := flows.FindParam(flow.Params(), name)
+		if param == nil {
+			log(events.NewError(fmt.Sprintf("Ignoring parameter '%s' which isn't declared by %s", name, flow.Reference(false)), ""))
+			continue
+		}
+
+		evaluated, _ := run.EvaluateTemplate(ctx, a.Params[name], log)
+
+		value, err := param.Normalize(env, evaluated)
+		if err != nil {
+			run.Fail(fmt.Sprintf("Invalid value for parameter '%s' of %s: %s", name, flow.Reference(false), err), log)
+			return nil, false
+		}
+		if value != "" {
+			values[name] = value
+		}
+	}
+
+	for _, param := range flow.Params() {
+		if param.Required && values[param.Name] == "" {
+			run.Fail(fmt.Sprintf("Missing value for required parameter '%s' of %s", param.Name, flow.Reference(false)), log)
+			return nil, false
+		}
+	}
+
+	return values, true
+}
+
 func (a *EnterFlow) Inspect(dependency func(assets.Reference), local func(string), result func(*flows.ResultInfo)) {
 	dependency(a.Flow)
 }
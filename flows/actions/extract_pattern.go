@@ -0,0 +1,108 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nyaruka/goflow/assets"
+	"github.com/nyaruka/goflow/core/events"
+	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/routers/cases"
+)
+
+func init() {
+	registerType(TypeExtractPattern, func() flows.Action { return &ExtractPattern{} })
+}
+
+// TypeExtractPattern is the type for the extract pattern action
+const TypeExtractPattern string = "extract_pattern"
+
+// ExtractPattern can be used to match text against a regex pattern and save its named groups as results
+// or locals. Matching works the same as the `has_pattern` test. Each capture maps a named group to either a
+// result or a local. If the text doesn't match, nothing is saved.
+//
+// Both the text and pattern fields may be templates. A [event:run_result_changed] event will be created for
+// each result saved.
+//
+//	{
+//	  "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+//	  "type": "extract_pattern",
+//	  "text": "REG John 25 F",
+//	  "pattern": "reg (?P<name>\\w+) (?P<age>\\d+) (?P<gender>[mf])",
+//	  "captures": [
+//	    {"group": "name", "result": "Name"},
+//	    {"group": "age", "result": "Age"},
+//	    {"group": "gender", "local": "gender"}
+//	  ]
+//	}
+//
+// @action extract_pattern
+type ExtractPattern struct {
+	baseAction
+	universalAction
+
+	Text     string           `json:"text"     validate:"max=10000" engine:"evaluated"`
+	Pattern  string           `json:"pattern"  validate:"required,max=1000" engine:"evaluated"`
+	Captures []*flows.Capture `json:"captures" validate:"required,min=1,max=20,dive"`
+}
+
+// NewExtractPattern creates a new extract pattern action
+func NewExtractPattern(uuid flows.ActionUUID, text, pattern string, captures []*flows.Capture) *ExtractPattern {
+	return &ExtractPattern{
+		baseAction: newBaseAction(TypeExtractPattern, uuid),
+		Text:       text,
+		Pattern:    pattern,
+		Captures:   captures,
+	}
+}
+
+// Validate validates our action is valid
+func (a *ExtractPattern) Validate() error {
+	for _, capture := range a.Captures {
+		if err := capture.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute runs this action
+func (a *ExtractPattern) Execute(ctx context.Context, run flows.Run, step flows.Step, log events.EventLogger) error {
+	text, ok := run.EvaluateTemplate(ctx, a.Text, log)
+	if !ok {
+		return nil
+	}
+	pattern, ok := run.EvaluateTemplate(ctx, a.Pattern, log)
+	if !ok {
+		return nil
+	}
+
+	env := run.Session().MergedEnvironment()
+
+	result := cases.HasPattern(env, types.NewXText(text), types.NewXText(pattern))
+
+	switch typed := result.(type) {
+	case *types.XError:
+		log(events.NewError(fmt.Sprintf("Unable to match pattern: %s", typed.Error()), ""))
+	case *types.XObject:
+		if !typed.Truthy() {
+			return nil
+		}
+
+		extra, _ := typed.Get("extra")
+		groups, _ := extra.(*types.XObject)
+
+		for _, capture := range a.Captures {
+			capture.Apply(env, run, step, groups, "", "", text, log)
+		}
+	}
+
+	return nil
+}
+
+func (a *ExtractPattern) Inspect(dependency func(assets.Reference), local func(string), result func(*flows.ResultInfo)) {
+	for _, capture := range a.Captures {
+		capture.Inspect([]string{}, local, result)
+	}
+}
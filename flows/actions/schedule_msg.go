@@ -0,0 +1,94 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/goflow/assets"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/core/events"
+	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/flows"
+)
+
+func init() {
+	registerType(TypeScheduleMsg, func() flows.Action { return &ScheduleMsg{} })
+}
+
+// TypeScheduleMsg is the type for the schedule message action
+const TypeScheduleMsg string = "schedule_msg"
+
+// ScheduleMsg can be used to send a message to the current contact at a later time without the flow
+// waiting for it. The `fire_on` field is a template which should evaluate to a datetime, and is parsed in the
+// contact's timezone. Values without a time use the current time of day. If the fire time is outside of the send
+// windows of the flow or environment, it's moved to when the next window opens.
+//
+// A [event:msg_scheduled] event will be created with the evaluated message and fire time, and it's up to the
+// caller to deliver the message when that time is reached. If `fire_on` doesn't evaluate to a valid datetime,
+// an [event:error] event will be created and the message won't be scheduled.
+//
+//	{
+//	  "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+//	  "type": "schedule_msg",
+//	  "text": "Hi @contact.name, don't forget your appointment tomorrow!",
+//	  "fire_on": "@(datetime_add(now(), 1, \"D\"))"
+//	}
+//
+// @action schedule_msg
+type ScheduleMsg struct {
+	baseAction
+	universalAction
+
+	Text         string   `json:"text"                    validate:"required,max=10000"              engine:"localized,evaluated"`
+	Attachments  []string `json:"attachments,omitempty"   validate:"max=10,dive,attachment,max=8192" engine:"localized,evaluated"`
+	QuickReplies []string `json:"quick_replies,omitempty" validate:"max=10,dive,max=1000"            engine:"localized,evaluated"`
+	FireOn       string   `json:"fire_on"                 validate:"required,max=1000"               engine:"evaluated"`
+}
+
+// NewScheduleMsg creates a new schedule msg action
+func NewScheduleMsg(uuid flows.ActionUUID, text string, attachments []string, quickReplies []string, fireOn string) *ScheduleMsg {
+	return &ScheduleMsg{
+		baseAction:   newBaseAction(TypeScheduleMsg, uuid),
+		Text:         text,
+		Attachments:  attachments,
+		QuickReplies: quickReplies,
+		FireOn:       fireOn,
+	}
+}
+
+// Execute runs this action
+func (a *ScheduleMsg) Execute(ctx context.Context, run flows.Run, step flows.Step, log events.EventLogger) error {
+	evaluatedFireOn, ok := run.EvaluateTemplate(ctx, a.FireOn, log)
+	if !ok {
+		return nil
+	}
+
+	// parse the fire time in the contact's timezone
+	fireOn, xerr := types.ToXDateTimeWithTimeFill(run.Session().MergedEnvironment(), types.NewXText(evaluatedFireOn))
+	if xerr != nil {
+		log(events.NewError(fmt.Sprintf("Unable to schedule message, '%s' is not a valid datetime", evaluatedFireOn), events.ErrorCodeDatetimeInvalid, "value", evaluatedFireOn))
+		return nil
+	}
+
+	content, lang := a.evaluateMessage(ctx, run, nil, a.Text, a.Attachments, a.QuickReplies, nil, nil, log)
+
+	unsendableReason, err := checkSendable(ctx, run, content)
+	if err != nil {
+		return err
+	}
+
+	route := run.Contact().ResolveRoute()
+	locale := currentLocale(run, lang)
+
+	var msg *core.MsgOut
+	if route != nil {
+		channelRef := assets.NewChannelReference(route.Channel.UUID(), route.Channel.Name())
+		msg = core.NewMsgOut(route.URN, channelRef, content, nil, locale, unsendableReason)
+	} else {
+		msg = core.NewMsgOut(urns.NilURN, nil, content, nil, locale, core.UnsendableReasonNoRoute)
+	}
+
+	log(events.NewMsgScheduled(msg, flows.NextSendTime(run, fireOn.Native())))
+	return nil
+}
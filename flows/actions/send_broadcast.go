@@ -3,6 +3,7 @@ package actions
 import (
 	"context"
 
+	"github.com/nyaruka/gocommon/dates"
 	"github.com/nyaruka/gocommon/i18n"
 	"github.com/nyaruka/gocommon/urns"
 	"github.com/nyaruka/goflow/assets"
@@ -65,6 +66,11 @@ func NewSendBroadcast(uuid flows.ActionUUID, text string, attachments []string,
 	}
 }
 
+// Validate validates our action is valid
+func (a *SendBroadcast) Validate() error {
+	return a.createMsgAction.validate()
+}
+
 // Execute runs this action
 func (a *SendBroadcast) Execute(ctx context.Context, run flows.Run, step flows.Step, log events.EventLogger) error {
 	groupRefs, contactRefs, contactQuery, urnList, err := a.resolveRecipients(ctx, run, log)
@@ -85,7 +91,7 @@ func (a *SendBroadcast) Execute(ctx context.Context, run flows.Run, step flows.S
 	for _, language := range languages {
 		languages := []i18n.Language{language, run.Flow().Language()}
 
-		content, _ := a.evaluateMessage(ctx, run, languages, a.Text, a.Attachments, a.QuickReplies, log)
+		content, _ := a.evaluateMessage(ctx, run, languages, a.Text, a.Attachments, a.QuickReplies, a.Buttons, a.List, log)
 		translations[language] = content
 	}
 
@@ -111,7 +117,17 @@ func (a *SendBroadcast) Execute(ctx context.Context, run flows.Run, step flows.S
 		}
 	}
 
-	log(events.NewBroadcastCreated(translations, run.Flow().Language(), groupRefs, contactRefs, contactQuery, urnList, templateRef, templateVariables))
+	event := events.NewBroadcastCreated(translations, run.Flow().Language(), groupRefs, contactRefs, contactQuery, urnList, templateRef, templateVariables)
+
+	// defer the broadcast if we're outside of the send windows
+	if windows := flows.SendWindows(run); len(windows) > 0 {
+		now := dates.Now()
+		if next := windows.NextOpen(now, run.Session().Environment().Timezone()); next.After(now) {
+			event.SendAfter = &next
+		}
+	}
+
+	log(event)
 	return nil
 }
 
@@ -3,6 +3,7 @@ package actions
 import (
 	"context"
 	"fmt"
+	"html"
 	"regexp"
 	"strings"
 
@@ -19,7 +20,8 @@ func init() {
 const TypeSendEmail string = "send_email"
 
 // SendEmail can be used to send an email to one or more recipients. The subject, body and addresses
-// can all contain expressions.
+// can all contain expressions. It can optionally include an HTML body, in which case the values of expressions are
+// HTML escaped, as well as CC and BCC addresses, a reply-to address and the URLs of files to attach.
 //
 // An [event:email_sent] event will be created if the email could be sent.
 //
@@ -36,9 +38,14 @@ type SendEmail struct {
 	baseAction
 	onlineAction
 
-	Addresses []string `json:"addresses" validate:"required,min=1,dive,max=1000" engine:"evaluated"`
-	Subject   string   `json:"subject"   validate:"required,max=1000"            engine:"localized,evaluated"`
-	Body      string   `json:"body"      validate:"required,max=10000"           engine:"localized,evaluated"`
+	Addresses   []string `json:"addresses"             validate:"required,min=1,dive,max=1000"   engine:"evaluated"`
+	CC          []string `json:"cc,omitempty"          validate:"omitempty,dive,max=1000"        engine:"evaluated"`
+	BCC         []string `json:"bcc,omitempty"         validate:"omitempty,dive,max=1000"        engine:"evaluated"`
+	ReplyTo     string   `json:"reply_to,omitempty"    validate:"max=1000"                       engine:"evaluated"`
+	Subject     string   `json:"subject"               validate:"required,max=1000"              engine:"localized,evaluated"`
+	Body        string   `json:"body"                  validate:"required,max=10000"             engine:"localized,evaluated"`
+	HTMLBody    string   `json:"html_body,omitempty"   validate:"max=100000"                     engine:"localized,evaluated"`
+	Attachments []string `json:"attachments,omitempty" validate:"omitempty,max=10,dive,max=2048" engine:"evaluated"`
 }
 
 // NewSendEmail creates a new send email action
@@ -72,38 +79,89 @@ func (a *SendEmail) Execute(ctx context.Context, run flows.Run, step flows.Step,
 		return nil
 	}
 
-	evaluatedAddresses := make([]string, 0)
-
-	for _, address := range a.Addresses {
-		evaluatedAddress, _ := run.EvaluateTemplate(ctx, address, log)
-		if evaluatedAddress == "" {
-			log(events.NewError("Email address evaluated to empty string, skipping", ""))
-			continue
-		}
-
-		// strip mailto prefix if this is an email URN
-		evaluatedAddress = strings.TrimPrefix(evaluatedAddress, "mailto:")
-
-		evaluatedAddresses = append(evaluatedAddresses, evaluatedAddress)
+	// HTML body is optional but if it's provided, values inserted into it need to be escaped
+	var evaluatedHTMLBody string
+	if a.HTMLBody != "" {
+		localizedHTMLBody, _ := run.GetText(uuids.UUID(a.UUID()), "html_body", a.HTMLBody)
+		evaluatedHTMLBody, _ = run.EvaluateTemplateText(ctx, localizedHTMLBody, html.EscapeString, false, log)
 	}
 
+	evaluatedAddresses := a.evaluateAddresses(ctx, run, a.Addresses, log)
+
 	// nothing to do if there are no addresses
 	if len(evaluatedAddresses) == 0 {
 		return nil
 	}
 
+	evaluatedCC := a.evaluateAddresses(ctx, run, a.CC, log)
+	evaluatedBCC := a.evaluateAddresses(ctx, run, a.BCC, log)
+
+	evaluatedReplyTo := ""
+	if a.ReplyTo != "" {
+		evaluatedReplyTo, _ = run.EvaluateTemplate(ctx, a.ReplyTo, log)
+		evaluatedReplyTo = strings.TrimPrefix(strings.TrimSpace(evaluatedReplyTo), "mailto:")
+	}
+
+	evaluatedAttachments := make([]string, 0, len(a.Attachments))
+	for _, attachment := range a.Attachments {
+		evaluatedAttachment, _ := run.EvaluateTemplate(ctx, attachment, log)
+		evaluatedAttachment = strings.TrimSpace(evaluatedAttachment)
+		if evaluatedAttachment == "" {
+			log(events.NewError("Email attachment evaluated to empty string, skipping", ""))
+			continue
+		}
+		evaluatedAttachments = append(evaluatedAttachments, evaluatedAttachment)
+	}
+
+	email := &flows.Email{
+		To:          evaluatedAddresses,
+		CC:          evaluatedCC,
+		BCC:         evaluatedBCC,
+		ReplyTo:     evaluatedReplyTo,
+		Subject:     evaluatedSubject,
+		Body:        evaluatedBody,
+		HTMLBody:    evaluatedHTMLBody,
+		Attachments: evaluatedAttachments,
+	}
+
 	svc, err := run.Session().Engine().Services().Email(run.Session().Assets())
 	if err != nil {
 		log(events.NewRawError(err))
 		return nil
 	}
 
-	err = svc.Send(ctx, evaluatedAddresses, evaluatedSubject, evaluatedBody)
+	err = svc.Send(ctx, email)
 	if err != nil {
 		log(events.NewError(fmt.Sprintf("Unable to send email: %s", err.Error()), ""))
 	} else {
-		log(events.NewEmailSent(evaluatedAddresses, evaluatedSubject, evaluatedBody))
+		event := events.NewEmailSent(email.To, email.Subject, email.Body)
+		event.CC = email.CC
+		event.BCC = email.BCC
+		event.ReplyTo = email.ReplyTo
+		event.HTMLBody = email.HTMLBody
+		event.Attachments = email.Attachments
+		log(event)
 	}
 
 	return nil
 }
+
+// evaluates a list of address templates, skipping any that evaluate to empty
+func (a *SendEmail) evaluateAddresses(ctx context.Context, run flows.Run, addresses []string, log events.EventLogger) []string {
+	evaluated := make([]string, 0, len(addresses))
+
+	for _, address := range addresses {
+		evaluatedAddress, _ := run.EvaluateTemplate(ctx, address, log)
+		if evaluatedAddress == "" {
+			log(events.NewError("Email address evaluated to empty string, skipping", ""))
+			continue
+		}
+
+		// strip mailto prefix if this is an email URN
+		evaluatedAddress = strings.TrimPrefix(evaluatedAddress, "mailto:")
+
+		evaluated = append(evaluated, evaluatedAddress)
+	}
+
+	return evaluated
+}
@@ -2,7 +2,6 @@ package actions
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/nyaruka/gocommon/i18n"
 	"github.com/nyaruka/gocommon/urns"
@@ -23,9 +22,16 @@ const TypeSendMsg string = "send_msg"
 // will attempt to find pairs of URNs and channels which can be used for sending. If it can't find such a pair, it will
 // create a message without a channel or URN.
 //
+// If the current time is outside of the send windows of the flow or environment, the message will have a `send_after`
+// time set to when the next window opens in the contact's timezone.
+//
 // A [event:msg_created] event will be created with the evaluated text. If the action has a `template`
 // set and a matching translation exists for the channel, the created message will use that template.
 //
+// Instead of quick replies, a message can have `buttons` or a `list`. Reply buttons and list rows have a `payload` which
+// is sent back as the payload of the contact's reply, so can be routed on with `@input.payload` regardless of the
+// language the message was sent in. URL buttons open their `url`. Button text, URLs and list text can be localized.
+//
 //	{
 //	  "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
 //	  "type": "send_msg",
@@ -57,25 +63,19 @@ func NewSendMsg(uuid flows.ActionUUID, text string, attachments []string, quickR
 	}
 }
 
+// Validate validates our action is valid
+func (a *SendMsg) Validate() error {
+	return a.createMsgAction.validate()
+}
+
 // Execute runs this action
 func (a *SendMsg) Execute(ctx context.Context, run flows.Run, step flows.Step, log events.EventLogger) error {
-	content, lang := a.evaluateMessage(ctx, run, nil, a.Text, a.Attachments, a.QuickReplies, log)
+	content, lang := a.evaluateMessage(ctx, run, nil, a.Text, a.Attachments, a.QuickReplies, a.Buttons, a.List, log)
 
 	// determine if this message can be sent - unsendable messages are still created for history's sake
-	var unsendableReason core.UnsendableReason
-	contactStatus := run.Contact().Status()
-	if contactStatus == core.ContactStatusBlocked {
-		unsendableReason = core.UnsendableReasonContactBlocked
-	} else if contactStatus == core.ContactStatusStopped {
-		unsendableReason = core.UnsendableReasonContactStopped
-	} else if contactStatus == core.ContactStatusArchived {
-		unsendableReason = core.UnsendableReasonContactArchived
-	} else {
-		var err error
-		unsendableReason, err = run.Session().Engine().Options().CheckSendable(ctx, run.Session().Assets(), run.Contact(), content)
-		if err != nil {
-			return fmt.Errorf("error checking if message is sendable: %w", err)
-		}
+	unsendableReason, err := checkSendable(ctx, run, content)
+	if err != nil {
+		return err
 	}
 
 	sa := run.Session().Assets()
@@ -119,6 +119,8 @@ func (a *SendMsg) Execute(ctx context.Context, run flows.Run, step flows.Step, l
 			msg = core.NewMsgOut(route.URN, channelRef, content, nil, locale, unsendableReason)
 		}
 
+		deferToSendWindow(run, msg)
+
 		log(events.NewMsgCreated(msg, "", ""))
 	} else {
 		// if we couldn't find a route, create a msg without a URN or channel and it's up to the caller
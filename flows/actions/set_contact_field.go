@@ -17,15 +17,25 @@ func init() {
 // TypeSetContactField is the type for the set contact field action
 const TypeSetContactField string = "set_contact_field"
 
+var fieldCategories = []string{CategorySuccess, CategoryFailure}
+
 // SetContactField can be used to update a field value on the contact. The value is a template
 // and white space is trimmed from the final value. An empty string clears the value.
-// A [event:contact_field_changed] event will be created with the corresponding value.
+// A [event:contact_field_changed] event will be created with the corresponding value. The value is
+// normalized according to the rules of the field, and if it isn't valid for the type or rules of the
+// field, e.g. it isn't one of the choices of a choice field, the field isn't changed and an [event:error]
+// event will be created.
+//
+// If this action has `result_name` set, a result will be created with that name. The value of the result
+// will be the normalized value and the category will be `Success` or `Failure` depending on whether the
+// value was valid, so that the flow can route on it.
 //
 //	{
 //	  "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
 //	  "type": "set_contact_field",
 //	  "field": {"key": "gender", "name": "Gender"},
-//	  "value": "Female"
+//	  "value": "Female",
+//	  "result_name": "Gender Saved"
 //	}
 //
 // @action set_contact_field
@@ -33,8 +43,9 @@ type SetContactField struct {
 	baseAction
 	universalAction
 
-	Field *assets.FieldReference `json:"field" validate:"required"`
-	Value string                 `json:"value" validate:"max=10000" engine:"evaluated"` // matches the engine's template length limit
+	Field      *assets.FieldReference `json:"field" validate:"required"`
+	Value      string                 `json:"value" validate:"max=10000" engine:"evaluated"` // matches the engine's template length limit
+	ResultName string                 `json:"result_name,omitempty" validate:"omitempty,result_name"`
 }
 
 // NewSetContactField creates a new set channel action
@@ -58,17 +69,42 @@ func (a *SetContactField) Execute(ctx context.Context, run flows.Run, step flows
 	fields := run.Session().Assets().Fields()
 	field := fields.Get(a.Field.Key)
 
-	if field != nil {
-		_, err := a.applyModifier(ctx, run, modifiers.NewField(field, value), log)
-		if err != nil {
-			return err
-		}
-	} else {
+	if field == nil {
 		log(events.NewDependencyError(a.Field))
+		return nil
+	}
+
+	// the modifier normalizes the value and logs an error event if it isn't valid for the field
+	valid := true
+	logEvent := func(e events.Event) {
+		if err, isErr := e.(*events.Error); isErr && err.Code == events.ErrorCodeFieldValueInvalid {
+			valid = false
+		}
+		log(e)
+	}
+
+	if _, err := a.applyModifier(ctx, run, modifiers.NewField(field, value), logEvent); err != nil {
+		return err
+	}
+
+	if a.ResultName != "" {
+		if valid {
+			normalized := ""
+			if v := run.Contact().Fields().Get(field); v != nil {
+				normalized = v.Text.Native()
+			}
+			a.saveResult(run, step, a.ResultName, normalized, CategorySuccess, "", value, nil, log)
+		} else {
+			a.saveResult(run, step, a.ResultName, value, CategoryFailure, "", value, nil, log)
+		}
 	}
 	return nil
 }
 
 func (a *SetContactField) Inspect(dependency func(assets.Reference), local func(string), result func(*flows.ResultInfo)) {
 	dependency(a.Field)
+
+	if a.ResultName != "" {
+		result(flows.NewResultInfo(a.ResultName, fieldCategories))
+	}
 }
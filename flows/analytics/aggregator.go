@@ -0,0 +1,258 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/nyaruka/goflow/assets"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/core/events"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/utils"
+)
+
+// Position is where a session is currently waiting in a flow
+type Position struct {
+	FlowUUID assets.FlowUUID `json:"flow_uuid"`
+	NodeUUID core.NodeUUID   `json:"node_uuid"`
+	Since    time.Time       `json:"since"`
+}
+
+// Aggregator aggregates the paths taken through flows by many sessions. It is built from the segments and events
+// of sprints and can be serialized to JSON, and merged with aggregators built by other workers. It remembers when
+// sessions stopped waiting so that merging doesn't bring back positions that another worker knows have ended.
+type Aggregator struct {
+	Flows     map[assets.FlowUUID]*FlowStats `json:"flows"`
+	Positions map[core.SessionUUID]*Position `json:"positions"`
+	Ended     map[core.SessionUUID]time.Time `json:"ended,omitempty"`
+}
+
+// NewAggregator creates a new empty aggregator
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		Flows:     make(map[assets.FlowUUID]*FlowStats),
+		Positions: make(map[core.SessionUUID]*Position),
+		Ended:     make(map[core.SessionUUID]time.Time),
+	}
+}
+
+// Flow gets the stats for the given flow which will be empty if no sprints have passed through it
+func (a *Aggregator) Flow(uuid assets.FlowUUID) *FlowStats {
+	if s := a.Flows[uuid]; s != nil {
+		return s
+	}
+	return newFlowStats()
+}
+
+// ActiveCounts gets the number of sessions currently waiting at each node of the given flow
+func (a *Aggregator) ActiveCounts(uuid assets.FlowUUID) map[core.NodeUUID]int {
+	counts := make(map[core.NodeUUID]int)
+	for _, p := range a.Positions {
+		if p.FlowUUID == uuid {
+			counts[p.NodeUUID]++
+		}
+	}
+	return counts
+}
+
+// Add adds a sprint of the given session. Sprints of the same session should be added in the order they happened,
+// so that the time spent waiting at nodes between sprints can be measured.
+func (a *Aggregator) Add(sessionUUID core.SessionUUID, sprint flows.Sprint) {
+	// when the contact arrived at the nodes they visited in this sprint, starting with where they were waiting
+	arrivedOn := make(map[core.NodeUUID]time.Time)
+	if p := a.Positions[sessionUUID]; p != nil && !sprint.IsInitial() {
+		arrivedOn[p.NodeUUID] = p.Since
+	}
+
+	for _, e := range sprint.Events() {
+		switch typed := e.(type) {
+		case *events.RunStarted:
+			// a new run arrives at the first node of its flow
+			for _, f := range sprint.Flows() {
+				if f.UUID() == typed.Flow.UUID && len(f.Nodes()) > 0 {
+					entry := f.Nodes()[0].UUID()
+					a.flowStats(f.UUID()).Arrivals[entry]++
+					arrivedOn[entry] = typed.CreatedOn()
+				}
+			}
+		case *events.RunResultChanged:
+			if typed.Step() != nil {
+				a.flowStats(typed.Step().Flow.UUID).recordResult(typed)
+			}
+		}
+	}
+
+	for _, seg := range sprint.Segments() {
+		stats := a.flowStats(seg.Flow().UUID())
+		exitUUID := seg.Exit().UUID()
+
+		stats.ExitCounts[exitUUID]++
+		stats.Departures[seg.Node().UUID()]++
+		stats.Arrivals[seg.Destination().UUID()]++
+
+		if since, known := arrivedOn[seg.Node().UUID()]; known {
+			if stats.ExitDurations[exitUUID] == nil {
+				stats.ExitDurations[exitUUID] = &Durations{}
+			}
+			stats.ExitDurations[exitUUID].Add(seg.Time().Sub(since))
+		}
+
+		arrivedOn[seg.Destination().UUID()] = seg.Time()
+	}
+
+	// if the sprint ended with a wait, the session is now waiting at that node...
+	var position *Position
+	var lastOn time.Time
+
+	for _, e := range sprint.Events() {
+		if (e.Type() == events.TypeMsgWait || e.Type() == events.TypeDialWait) && e.Step() != nil {
+			since, known := arrivedOn[e.Step().Node]
+			if !known {
+				since = e.CreatedOn()
+			}
+			position = &Position{FlowUUID: e.Step().Flow.UUID, NodeUUID: e.Step().Node, Since: since}
+		}
+		if e.CreatedOn().After(lastOn) {
+			lastOn = e.CreatedOn()
+		}
+	}
+
+	// otherwise record when it ended, as another aggregator may have it waiting from an earlier sprint
+	if position != nil {
+		a.setPosition(sessionUUID, position)
+	} else {
+		a.endPosition(sessionUUID, lastOn)
+	}
+}
+
+// Merge merges the given aggregator into this one. If both have a position for the same session, the most recent
+// is kept, and positions are removed if the other aggregator has seen them end.
+func (a *Aggregator) Merge(other *Aggregator) {
+	for uuid, s := range other.Flows {
+		a.flowStats(uuid).merge(s)
+	}
+	for uuid, p := range other.Positions {
+		existing := a.Positions[uuid]
+		ended, hasEnded := a.Ended[uuid]
+
+		if (existing == nil || p.Since.After(existing.Since)) && (!hasEnded || p.Since.After(ended)) {
+			a.setPosition(uuid, p)
+		}
+	}
+	for uuid, ended := range other.Ended {
+		if existing := a.Positions[uuid]; existing != nil && !existing.Since.After(ended) {
+			a.endPosition(uuid, ended)
+		} else if existing == nil && ended.After(a.Ended[uuid]) {
+			a.Ended[uuid] = ended
+		}
+	}
+}
+
+func (a *Aggregator) setPosition(sessionUUID core.SessionUUID, p *Position) {
+	a.Positions[sessionUUID] = p
+	delete(a.Ended, sessionUUID)
+}
+
+func (a *Aggregator) endPosition(sessionUUID core.SessionUUID, on time.Time) {
+	delete(a.Positions, sessionUUID)
+
+	if a.Ended == nil {
+		a.Ended = make(map[core.SessionUUID]time.Time)
+	}
+	a.Ended[sessionUUID] = on
+}
+
+func (a *Aggregator) flowStats(uuid assets.FlowUUID) *FlowStats {
+	s := a.Flows[uuid]
+	if s == nil {
+		s = newFlowStats()
+		a.Flows[uuid] = s
+	}
+	return s
+}
+
+// FlowStats are the aggregated paths taken through a single flow
+type FlowStats struct {
+	ExitCounts    map[flows.ExitUUID]int        `json:"exit_counts"`
+	ExitDurations map[flows.ExitUUID]*Durations `json:"exit_durations"`
+	Arrivals      map[core.NodeUUID]int         `json:"arrivals"`
+	Departures    map[core.NodeUUID]int         `json:"departures"`
+	Results       map[string]map[string]int     `json:"results"`
+}
+
+func newFlowStats() *FlowStats {
+	return &FlowStats{
+		ExitCounts:    make(map[flows.ExitUUID]int),
+		ExitDurations: make(map[flows.ExitUUID]*Durations),
+		Arrivals:      make(map[core.NodeUUID]int),
+		Departures:    make(map[core.NodeUUID]int),
+		Results:       make(map[string]map[string]int),
+	}
+}
+
+// DropOffRate gets the fraction of arrivals at the given node which didn't continue to another node, i.e. which
+// ended, are still waiting or expired there
+func (s *FlowStats) DropOffRate(node core.NodeUUID) float64 {
+	arrivals := s.Arrivals[node]
+	if arrivals == 0 {
+		return 0
+	}
+	return float64(max(arrivals-s.Departures[node], 0)) / float64(arrivals)
+}
+
+// MedianDuration gets the approximate median time between arriving at the node of the given exit and leaving via
+// that exit, which is zero if no durations have been recorded
+func (s *FlowStats) MedianDuration(exit flows.ExitUUID) time.Duration {
+	if d := s.ExitDurations[exit]; d != nil {
+		return d.Median()
+	}
+	return 0
+}
+
+// Categories gets the number of runs with each category for the result with the given name
+func (s *FlowStats) Categories(result string) map[string]int {
+	if c := s.Results[utils.Snakify(result)]; c != nil {
+		return c
+	}
+	return map[string]int{}
+}
+
+func (s *FlowStats) recordResult(e *events.RunResultChanged) {
+	key := utils.Snakify(e.Name)
+	if s.Results[key] == nil {
+		s.Results[key] = make(map[string]int)
+	}
+
+	// a result being changed moves the run from its previous category
+	if e.Previous != nil && s.Results[key][e.Previous.Category] > 0 {
+		s.Results[key][e.Previous.Category]--
+		if s.Results[key][e.Previous.Category] == 0 {
+			delete(s.Results[key], e.Previous.Category)
+		}
+	}
+	s.Results[key][e.Category]++
+}
+
+func (s *FlowStats) merge(other *FlowStats) {
+	mergeCounts(s.ExitCounts, other.ExitCounts)
+	mergeCounts(s.Arrivals, other.Arrivals)
+	mergeCounts(s.Departures, other.Departures)
+
+	for exit, d := range other.ExitDurations {
+		if s.ExitDurations[exit] == nil {
+			s.ExitDurations[exit] = &Durations{}
+		}
+		s.ExitDurations[exit].Merge(d)
+	}
+	for key, categories := range other.Results {
+		if s.Results[key] == nil {
+			s.Results[key] = make(map[string]int)
+		}
+		mergeCounts(s.Results[key], categories)
+	}
+}
+
+func mergeCounts[K comparable](dst, src map[K]int) {
+	for k, v := range src {
+		dst[k] += v
+	}
+}
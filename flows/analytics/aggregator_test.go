@@ -0,0 +1,139 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/analytics"
+	"github.com/nyaruka/goflow/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	flowUUID  = "615b8a0f-588c-4d20-a05f-363b0b4ce6f4"
+	colorNode = core.NodeUUID("46d51f50-58de-49da-8d13-dadbf322685d")
+	sodaNode  = core.NodeUUID("11a772f3-3ca2-4429-8b33-20fdcfc2b69e")
+	redExit   = flows.ExitUUID("2f42b942-bf32-4e81-8ff3-f946b5e68dd8")
+	blueExit  = flows.ExitUUID("dcdc29b6-4671-4c10-a614-5b1507f3df97")
+	otherExit = flows.ExitUUID("17ec8700-cada-4cff-b3b1-351cac4d85c6")
+)
+
+func TestAggregator(t *testing.T) {
+	now := time.Date(2025, 5, 4, 12, 0, 0, 0, time.UTC)
+	dates.SetNowFunc(func() time.Time { return now })
+	defer dates.SetNowFunc(time.Now)
+
+	// runs a session with the given responses, each of which is sent after the given delay
+	runSession := func(agg *analytics.Aggregator, delay time.Duration, responses ...string) (flows.Session, flows.SessionAssets) {
+		sa, session, sprint := test.NewSessionBuilder().WithAssetsPath("../../test/testdata/runner/two_questions.json").WithFlow(flowUUID).MustBuild()
+		agg.Add(session.UUID(), sprint)
+
+		for _, r := range responses {
+			now = now.Add(delay)
+
+			var err error
+			session, sprint, err = test.ResumeSession(session, sa, r)
+			require.NoError(t, err)
+			agg.Add(session.UUID(), sprint)
+		}
+		return session, sa
+	}
+
+	agg1 := analytics.NewAggregator()
+	runSession(agg1, 10*time.Second, "red")
+	runSession(agg1, 5*time.Minute, "purple", "blue")
+
+	stats := agg1.Flow(flowUUID)
+	assert.Equal(t, map[flows.ExitUUID]int{redExit: 1, blueExit: 1, otherExit: 1}, stats.ExitCounts)
+	assert.Equal(t, map[core.NodeUUID]int{colorNode: 3, sodaNode: 2}, stats.Arrivals)
+	assert.Equal(t, map[core.NodeUUID]int{colorNode: 3}, stats.Departures)
+	assert.Equal(t, 0.0, stats.DropOffRate(colorNode))
+	assert.Equal(t, 1.0, stats.DropOffRate(sodaNode))
+	assert.Equal(t, 10500*time.Millisecond, stats.MedianDuration(redExit)) // middle of the [10s, 11s) bucket
+	assert.Equal(t, 304*time.Second, stats.MedianDuration(blueExit))       // middle of the [288s, 320s) bucket
+	assert.Equal(t, time.Duration(0), stats.MedianDuration("8a8c5b48-a9a6-4b3f-bd24-3d47b8e8d0a3"))
+	assert.Equal(t, map[string]int{"Red": 1, "Blue": 1}, stats.Categories("Favorite Color"))
+	assert.Equal(t, map[string]int{}, stats.Categories("Soda"))
+	assert.Equal(t, map[core.NodeUUID]int{sodaNode: 2}, agg1.ActiveCounts(flowUUID))
+
+	// aggregators can be serialized and merged
+	agg2 := analytics.NewAggregator()
+	runSession(agg2, time.Hour, "purple")
+
+	agg3 := &analytics.Aggregator{}
+	jsonx.MustUnmarshal(jsonx.MustMarshal(agg2), agg3)
+	agg1.Merge(agg3)
+
+	stats = agg1.Flow(flowUUID)
+	assert.Equal(t, map[flows.ExitUUID]int{redExit: 1, blueExit: 1, otherExit: 2}, stats.ExitCounts)
+	assert.Equal(t, map[core.NodeUUID]int{colorNode: 5, sodaNode: 2}, stats.Arrivals)
+	assert.Equal(t, 0.2, stats.DropOffRate(colorNode))
+	assert.Equal(t, map[string]int{"Red": 1, "Blue": 1, "Other": 1}, stats.Categories("favorite_color"))
+	assert.Equal(t, map[core.NodeUUID]int{colorNode: 1, sodaNode: 2}, agg1.ActiveCounts(flowUUID))
+
+	// a session waiting in one aggregator which another aggregator sees end
+	agg4 := analytics.NewAggregator()
+	session, sa := runSession(agg4, time.Minute, "red")
+	assert.Equal(t, map[core.NodeUUID]int{sodaNode: 1}, agg4.ActiveCounts(flowUUID))
+
+	stale := &analytics.Aggregator{}
+	jsonx.MustUnmarshal(jsonx.MustMarshal(agg4), stale)
+
+	now = now.Add(time.Minute)
+	agg5 := analytics.NewAggregator()
+	_, sprint, err := test.ResumeSession(session, sa, "coke")
+	require.NoError(t, err)
+	agg5.Add(session.UUID(), sprint)
+
+	ended := &analytics.Aggregator{}
+	jsonx.MustUnmarshal(jsonx.MustMarshal(agg5), ended)
+	agg4.Merge(ended)
+	assert.Equal(t, map[core.NodeUUID]int{}, agg4.ActiveCounts(flowUUID))
+
+	// and merging the other way round doesn't bring the position back
+	agg5.Merge(stale)
+	assert.Equal(t, map[core.NodeUUID]int{}, agg5.ActiveCounts(flowUUID))
+
+	// unknown flow
+	assert.Equal(t, map[core.NodeUUID]int{}, agg1.Flow("2d7c0ba4-8eb8-4e1b-9e52-e4d1c0c5ba73").Arrivals)
+}
+
+func TestDurations(t *testing.T) {
+	d := &analytics.Durations{}
+	assert.Equal(t, time.Duration(0), d.Median())
+
+	d.Add(-time.Second)
+	d.Add(500 * time.Millisecond)
+	d.Add(3 * time.Second)
+	d.Add(time.Minute)
+	d.Add(time.Hour)
+	assert.Equal(t, 5, d.Count)
+	assert.Equal(t, 3500*time.Millisecond, d.Median())
+
+	other := &analytics.Durations{}
+	other.Add(time.Hour)
+	other.Add(2 * time.Hour)
+	other.Add(3 * time.Hour)
+	d.Merge(other)
+	assert.Equal(t, 8, d.Count)
+	assert.Equal(t, 64*time.Second, d.Median())
+
+	// medians of longer durations are within the width of a bucket, i.e. an eighth of a power of two
+	for _, v := range []time.Duration{1000 * time.Second, 90 * time.Minute, 3 * 24 * time.Hour} {
+		d = &analytics.Durations{}
+		for range 5 {
+			d.Add(v)
+		}
+		assert.InEpsilon(t, float64(v), float64(d.Median()), 0.07, "median mismatch for %s", v)
+	}
+
+	// histograms can be serialized
+	d = &analytics.Durations{}
+	d.Add(10 * time.Second)
+	assert.Equal(t, `{"buckets":[0,0,0,0,0,0,0,0,0,0,1],"count":1}`, string(jsonx.MustMarshal(d)))
+}
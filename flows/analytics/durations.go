@@ -0,0 +1,78 @@
+package analytics
+
+import (
+	"math/bits"
+	"time"
+)
+
+// durations under 8 seconds get a bucket per second, and longer durations get 8 buckets for each power of two
+// seconds, which keeps the error of medians within a few percent, up to durations of over a hundred years
+const (
+	subBucketsPerOctave = 8
+	numDurationBuckets  = subBucketsPerOctave * 30
+)
+
+// Durations is a histogram of durations in buckets which get wider as durations get longer. This allows medians to be
+// approximated and histograms to be merged without keeping every duration.
+type Durations struct {
+	Buckets []int `json:"buckets"`
+	Count   int   `json:"count"`
+}
+
+// Add adds a duration to this histogram. Negative durations are counted as zero.
+func (d *Durations) Add(v time.Duration) {
+	i := bucketOf(v)
+	d.ensureBuckets(i + 1)
+	d.Buckets[i]++
+	d.Count++
+}
+
+// Merge merges the given histogram into this one
+func (d *Durations) Merge(other *Durations) {
+	d.ensureBuckets(min(len(other.Buckets), numDurationBuckets))
+	for i, c := range other.Buckets {
+		d.Buckets[min(i, numDurationBuckets-1)] += c
+	}
+	d.Count += other.Count
+}
+
+// Median gets the approximate median duration by interpolating within the bucket which contains it
+func (d *Durations) Median() time.Duration {
+	target := float64(d.Count) / 2
+	seen := 0
+	for i, c := range d.Buckets {
+		if c > 0 && float64(seen+c) >= target {
+			start, end := bucketStart(i), bucketStart(i+1)
+			fraction := (target - float64(seen)) / float64(c)
+			return start + time.Duration(float64(end-start)*fraction)
+		}
+		seen += c
+	}
+	return 0
+}
+
+func (d *Durations) ensureBuckets(n int) {
+	if len(d.Buckets) < n {
+		d.Buckets = append(d.Buckets, make([]int, n-len(d.Buckets))...)
+	}
+}
+
+// bucket 0 is less than a second, bucket 1 is [1s, 2s) etc up to bucket 7, and then bucket 8 is [8s, 9s), bucket
+// 16 is [16s, 18s), bucket 24 is [32s, 36s) etc
+func bucketOf(v time.Duration) int {
+	secs := int64(v / time.Second)
+	if secs < subBucketsPerOctave {
+		return int(max(secs, 0))
+	}
+	octave := bits.Len64(uint64(secs)) - 1
+	sub := int((secs - 1<<octave) >> (octave - 3))
+	return min((octave-2)*subBucketsPerOctave+sub, numDurationBuckets-1)
+}
+
+func bucketStart(i int) time.Duration {
+	if i < subBucketsPerOctave {
+		return time.Duration(i) * time.Second
+	}
+	octave, sub := i/subBucketsPerOctave+2, i%subBucketsPerOctave
+	return time.Duration(int64(1)<<octave+int64(sub)<<(octave-3)) * time.Second
+}
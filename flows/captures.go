@@ -0,0 +1,64 @@
+package flows
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/core/events"
+	"github.com/nyaruka/goflow/envs"
+	"github.com/nyaruka/goflow/excellent/types"
+)
+
+// Capture maps a named group of a regex pattern to a run result or a local
+type Capture struct {
+	Group  string `json:"group"            validate:"required,max=64"`
+	Result string `json:"result,omitempty" validate:"omitempty,result_name"`
+	Local  string `json:"local,omitempty"  validate:"omitempty,local_ref"`
+}
+
+// NewCapture creates a new capture which saves to the given result or local
+func NewCapture(group, result, local string) *Capture {
+	return &Capture{Group: group, Result: result, Local: local}
+}
+
+// Validate validates that this capture saves to either a result or a local
+func (c *Capture) Validate() error {
+	if (c.Result == "") == (c.Local == "") {
+		return fmt.Errorf("capture of group '%s' must have either a result or a local", c.Group)
+	}
+	return nil
+}
+
+// Inspect reports the result or local this capture saves to
+func (c *Capture) Inspect(categories []string, local func(string), result func(*ResultInfo)) {
+	if c.Result != "" {
+		result(NewResultInfo(c.Result, categories))
+	} else {
+		local(c.Local)
+	}
+}
+
+// Apply saves the value of this capture's group in the given groups to the run
+func (c *Capture) Apply(env envs.Environment, run Run, step Step, groups *types.XObject, category, categoryLocalized, input string, log events.EventLogger) {
+	var group types.XValue
+	if groups != nil {
+		group, _ = groups.Get(c.Group)
+	}
+	if group == nil {
+		log(events.NewError(fmt.Sprintf("Pattern has no group named '%s'", c.Group), ""))
+		return
+	}
+
+	value, _ := types.ToXText(env, group)
+
+	if c.Result != "" {
+		result := core.NewResult(c.Result, value.Native(), category, categoryLocalized, step.NodeUUID(), input, nil, dates.Now())
+		prev, changed := run.SetResult(result)
+		if changed {
+			log(events.NewRunResultChanged(result, prev))
+		}
+	} else {
+		run.Locals().Set(c.Local, value.Native())
+	}
+}
@@ -0,0 +1,31 @@
+package flows_test
+
+import (
+	"testing"
+
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptures(t *testing.T) {
+	assert.NoError(t, flows.NewCapture("name", "Name", "").Validate())
+	assert.NoError(t, flows.NewCapture("name", "", "name").Validate())
+	assert.EqualError(t, flows.NewCapture("name", "", "").Validate(), "capture of group 'name' must have either a result or a local")
+	assert.EqualError(t, flows.NewCapture("name", "Name", "name").Validate(), "capture of group 'name' must have either a result or a local")
+
+	assert.NoError(t, utils.Validate(flows.NewCapture("name", "Name", "")))
+	assert.EqualError(t, utils.Validate(flows.NewCapture("", "Name", "")), "field 'group' is required")
+	assert.EqualError(t, utils.Validate(flows.NewCapture("name", "", "Name")), "field 'local' is not a valid local variable reference")
+
+	var results []string
+	var locals []string
+	inspect := func(c *flows.Capture) {
+		c.Inspect([]string{"Registered"}, func(l string) { locals = append(locals, l) }, func(r *flows.ResultInfo) { results = append(results, r.Name) })
+	}
+	inspect(flows.NewCapture("name", "Name", ""))
+	inspect(flows.NewCapture("age", "", "age"))
+
+	assert.Equal(t, []string{"Name"}, results)
+	assert.Equal(t, []string{"age"}, locals)
+}
@@ -0,0 +1,230 @@
+package definition
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/nyaruka/gocommon/i18n"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/uuids"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/definition/migrations"
+)
+
+// ChangeType is the type of a change between two revisions of a flow
+type ChangeType string
+
+const (
+	ChangeTypeAdded   ChangeType = "added"
+	ChangeTypeRemoved ChangeType = "removed"
+	ChangeTypeChanged ChangeType = "changed"
+)
+
+// ItemType is the type of item in a flow definition that a change or conflict applies to
+type ItemType string
+
+const (
+	ItemTypeFlow        ItemType = "flow"
+	ItemTypeNode        ItemType = "node"
+	ItemTypeAction      ItemType = "action"
+	ItemTypeCase        ItemType = "case"
+	ItemTypeCategory    ItemType = "category"
+	ItemTypeExit        ItemType = "exit"
+	ItemTypeTranslation ItemType = "translation"
+)
+
+// the properties of a flow which are compared, i.e. not revision, nodes, localization or _ui
+var diffedFlowProperties = []string{"name", "language", "type", "expire_after_minutes", "send_windows", "params", "returns", "error_node_uuid"}
+
+// the properties of nodes and routers which hold lists of items identified by their UUIDs
+var itemListProperties = map[string]ItemType{
+	"nodes":      ItemTypeNode,
+	"actions":    ItemTypeAction,
+	"cases":      ItemTypeCase,
+	"categories": ItemTypeCategory,
+	"exits":      ItemTypeExit,
+}
+
+// Change is a change to a single item between two revisions of a flow. Changes to nodes only include the
+// node's own properties with its actions, cases, categories and exits replaced by their UUIDs, as changes to those
+// are reported separately. Changes to translations are per item and language.
+type Change struct {
+	Type     ChangeType      `json:"type"`
+	Item     ItemType        `json:"item"`
+	UUID     uuids.UUID      `json:"uuid"`
+	NodeUUID core.NodeUUID   `json:"node_uuid,omitempty"`
+	Language i18n.Language   `json:"language,omitempty"`
+	Before   json.RawMessage `json:"before,omitempty"`
+	After    json.RawMessage `json:"after,omitempty"`
+}
+
+// Diff computes the semantic differences between two revisions of a flow, ignoring the ordering of nodes and the
+// _ui section. Changes are ordered by node as they appear in the new revision, followed by removed nodes and then
+// translations.
+func Diff(from, to flows.Flow) []*Change {
+	f1, f2 := readGeneric(from), readGeneric(to)
+	changes := make([]*Change, 0)
+
+	add := func(typ ChangeType, item ItemType, uuid uuids.UUID, nodeUUID core.NodeUUID, lang i18n.Language, before, after any) {
+		changes = append(changes, &Change{
+			Type:     typ,
+			Item:     item,
+			UUID:     uuid,
+			NodeUUID: nodeUUID,
+			Language: lang,
+			Before:   marshalIfPresent(before),
+			After:    marshalIfPresent(after),
+		})
+	}
+
+	// diff flow level properties
+	flowProps1, flowProps2 := pickProperties(f1, diffedFlowProperties), pickProperties(f2, diffedFlowProperties)
+	if !reflect.DeepEqual(flowProps1, flowProps2) {
+		add(ChangeTypeChanged, ItemTypeFlow, uuids.UUID(to.UUID()), "", "", flowProps1, flowProps2)
+	}
+
+	// diff a list of items identified by UUID
+	diffItems := func(item ItemType, nodeUUID core.NodeUUID, list1, list2 any) {
+		order1, items1 := indexItems(list1)
+		order2, items2 := indexItems(list2)
+
+		for _, u := range order2 {
+			if items1[u] == nil {
+				add(ChangeTypeAdded, item, u, nodeUUID, "", nil, items2[u])
+			} else if !reflect.DeepEqual(items1[u], items2[u]) {
+				add(ChangeTypeChanged, item, u, nodeUUID, "", items1[u], items2[u])
+			}
+		}
+		for _, u := range order1 {
+			if items2[u] == nil {
+				add(ChangeTypeRemoved, item, u, nodeUUID, "", items1[u], nil)
+			}
+		}
+	}
+
+	nodeOrder1, nodes1 := indexItems(f1["nodes"])
+	nodeOrder2, nodes2 := indexItems(f2["nodes"])
+
+	for _, u := range nodeOrder2 {
+		n1, n2 := nodes1[u], nodes2[u]
+		if n1 == nil {
+			add(ChangeTypeAdded, ItemTypeNode, u, "", "", nil, n2)
+			continue
+		}
+
+		shell1, shell2 := nodeShell(n1), nodeShell(n2)
+		if !reflect.DeepEqual(shell1, shell2) {
+			add(ChangeTypeChanged, ItemTypeNode, u, "", "", shell1, shell2)
+		}
+
+		nodeUUID := core.NodeUUID(u)
+		r1, _ := n1["router"].(map[string]any)
+		r2, _ := n2["router"].(map[string]any)
+
+		diffItems(ItemTypeAction, nodeUUID, n1["actions"], n2["actions"])
+		diffItems(ItemTypeCase, nodeUUID, r1["cases"], r2["cases"])
+		diffItems(ItemTypeCategory, nodeUUID, r1["categories"], r2["categories"])
+		diffItems(ItemTypeExit, nodeUUID, n1["exits"], n2["exits"])
+	}
+	for _, u := range nodeOrder1 {
+		if nodes2[u] == nil {
+			add(ChangeTypeRemoved, ItemTypeNode, u, "", "", nodes1[u], nil)
+		}
+	}
+
+	// diff translations by language and then by item
+	l1, l2 := f1.Localization(), f2.Localization()
+	for _, lang := range sortedKeys(l1, l2) {
+		lt1, lt2 := l1.GetLanguageTranslation(i18n.Language(lang)), l2.GetLanguageTranslation(i18n.Language(lang))
+
+		for _, u := range sortedKeys(lt1, lt2) {
+			t1, t2 := lt1[u], lt2[u]
+			if t1 == nil {
+				add(ChangeTypeAdded, ItemTypeTranslation, uuids.UUID(u), "", i18n.Language(lang), nil, t2)
+			} else if t2 == nil {
+				add(ChangeTypeRemoved, ItemTypeTranslation, uuids.UUID(u), "", i18n.Language(lang), t1, nil)
+			} else if !reflect.DeepEqual(t1, t2) {
+				add(ChangeTypeChanged, ItemTypeTranslation, uuids.UUID(u), "", i18n.Language(lang), t1, t2)
+			}
+		}
+	}
+
+	return changes
+}
+
+// reads a flow as generic JSON so it can be compared and merged
+func readGeneric(f flows.Flow) migrations.Flow {
+	g, err := migrations.ReadFlow(jsonx.MustMarshal(f))
+	if err != nil {
+		panic(err) // a flow object should always marshal to a readable definition
+	}
+	return g
+}
+
+// indexes a generic list of objects by their UUIDs, returning the UUIDs in their original order
+func indexItems(list any) ([]uuids.UUID, map[uuids.UUID]map[string]any) {
+	l, _ := list.([]any)
+	order := make([]uuids.UUID, 0, len(l))
+	items := make(map[uuids.UUID]map[string]any, len(l))
+
+	for _, v := range l {
+		if u := migrations.GetObjectUUID(v); u != "" {
+			order = append(order, u)
+			items[u] = v.(map[string]any)
+		}
+	}
+	return order, items
+}
+
+// gets a copy of the given node with any lists of items replaced by their UUIDs
+func nodeShell(n map[string]any) map[string]any {
+	shell := replaceItemLists(n)
+	if r, ok := n["router"].(map[string]any); ok {
+		shell["router"] = replaceItemLists(r)
+	}
+	return shell
+}
+
+func replaceItemLists(o map[string]any) map[string]any {
+	c := make(map[string]any, len(o))
+	for k, v := range o {
+		if _, isList := itemListProperties[k]; isList {
+			order, _ := indexItems(v)
+			c[k] = order
+		} else {
+			c[k] = v
+		}
+	}
+	return c
+}
+
+func pickProperties(o map[string]any, props []string) map[string]any {
+	picked := make(map[string]any, len(props))
+	for _, p := range props {
+		if v, exists := o[p]; exists {
+			picked[p] = v
+		}
+	}
+	return picked
+}
+
+// gets the sorted union of the keys of the given maps
+func sortedKeys[M ~map[string]any](ms ...M) []string {
+	keys := make(map[string]bool)
+	for _, m := range ms {
+		for k := range m {
+			keys[k] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(keys))
+}
+
+func marshalIfPresent(v any) json.RawMessage {
+	if v == nil || v == absent {
+		return nil
+	}
+	return jsonx.MustMarshal(v)
+}
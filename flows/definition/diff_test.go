@@ -0,0 +1,117 @@
+package definition_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reads a revision of the flow in testdata/diff_base.json by applying the given old/new replacement pairs
+func readRevision(t *testing.T, replacements ...string) flows.Flow {
+	data, err := os.ReadFile("testdata/diff_base.json")
+	require.NoError(t, err)
+
+	revised := strings.NewReplacer(replacements...).Replace(string(data))
+	require.NotEqual(t, len(replacements) > 0, revised == string(data), "replacements didn't change anything")
+
+	flow, err := definition.ReadFlow([]byte(revised), nil)
+	require.NoError(t, err)
+	return flow
+}
+
+func TestDiff(t *testing.T) {
+	base := readRevision(t)
+
+	// no changes
+	assert.Equal(t, []*definition.Change{}, definition.Diff(base, base))
+
+	// UI only changes are ignored
+	assert.Equal(t, []*definition.Change{}, definition.Diff(base, readRevision(t, `"top": 200`, `"top": 300`)))
+
+	revised := readRevision(t,
+		`"name": "Favorites"`, `"name": "Favorite Colors"`,
+		`"What is your favorite color?"`, `"What's your favorite color?"`,
+		`"result_name": "Color"`, `"result_name": "Favorite Color"`,
+		`"arguments": ["blue"]`, `"arguments": ["blue navy"]`,
+		`"¿Cuál es tu color favorito?"`, `"¿Cuál es su color favorito?"`,
+		`"text": "Thanks, @results.color is a great color"
+                }`, `"text": "Thanks, @results.color is a great color"
+                },
+                {
+                    "uuid": "a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b03",
+                    "type": "send_msg",
+                    "text": "Bye"
+                }`,
+		`{
+                    "uuid": "e0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a03"
+                }`, `{
+                    "uuid": "e0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a03",
+                    "destination_uuid": "0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02"
+                }`,
+	)
+
+	changes := definition.Diff(base, revised)
+
+	summarize := func(cs []*definition.Change) []string {
+		s := make([]string, len(cs))
+		for i, c := range cs {
+			s[i] = strings.Join(strings.Fields(strings.Join([]string{string(c.Type), string(c.Item), string(c.UUID), string(c.NodeUUID), string(c.Language)}, " ")), " ")
+		}
+		return s
+	}
+
+	assert.Equal(t, []string{
+		"changed flow 8f1e1b8c-5d1c-4b0e-9a3e-0b3c6f7b8a11",
+		"changed node 0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c01",
+		"changed action a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b01 0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c01",
+		"changed case d1b2c3a4-5e6f-4a7b-8c9d-0e1f2a3b4c02 0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c01",
+		"changed exit e0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a03 0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c01",
+		"changed node 0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02",
+		"added action a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b03 0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02",
+		"changed translation a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b01 spa",
+	}, summarize(changes))
+
+	assert.JSONEq(t, `{"expire_after_minutes": 60, "language": "eng", "name": "Favorites", "type": "messaging"}`, string(changes[0].Before))
+	assert.JSONEq(t, `{"expire_after_minutes": 60, "language": "eng", "name": "Favorite Colors", "type": "messaging"}`, string(changes[0].After))
+	assert.Contains(t, string(changes[1].After), `"result_name":"Favorite Color"`)
+	assert.Contains(t, string(changes[1].After), `"cases":["d1b2c3a4-5e6f-4a7b-8c9d-0e1f2a3b4c01","d1b2c3a4-5e6f-4a7b-8c9d-0e1f2a3b4c02"]`)
+	assert.Nil(t, changes[6].Before)
+	assert.JSONEq(t, `{"uuid": "a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b03", "type": "send_msg", "text": "Bye"}`, string(changes[6].After))
+
+	// reversing the diff reverses additions and removals
+	reversed := definition.Diff(revised, base)
+	assert.Equal(t, "removed action a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b03 0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02", summarize(reversed)[6])
+
+	// rerouting exits and replacing a translation language
+	revised = readRevision(t,
+		`"destination_uuid": "0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02"`, `"destination_uuid": "0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c01"`,
+		`"type": "send_msg",
+                    "text": "Thanks`, `"type": "send_msg",
+                    "text": "Gracias`,
+		`"spa": {
+            "a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b01": {
+                "text": ["¿Cuál es tu color favorito?"]
+            }
+        }`, `"fra": {
+            "a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b01": {
+                "text": ["Quelle est ta couleur préférée?"]
+            }
+        }`,
+	)
+	assert.Equal(t, []string{
+		"changed exit e0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a01 0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c01",
+		"changed exit e0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a02 0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c01",
+		"changed action a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b02 0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02",
+		"added translation a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b01 fra",
+		"removed translation a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b01 spa",
+	}, summarize(definition.Diff(base, revised)))
+
+	// changes can be marshaled
+	assert.Contains(t, string(jsonx.MustMarshal(changes[0])), `"type":"changed","item":"flow"`)
+}
@@ -42,8 +42,12 @@ type flow struct {
 	flowType     flows.FlowType
 	revision     int
 	expireAfter  time.Duration
+	sendWindows  envs.SendWindows
+	params       []*flows.Param
+	returns      []*flows.Param
 	localization flows.Localization
 	nodes        []flows.Node
+	errorNode    core.NodeUUID
 
 	// optional properties not used by engine itself
 	ui json.RawMessage
@@ -56,7 +60,7 @@ type flow struct {
 }
 
 // NewFlow creates a new flow
-func NewFlow(uuid assets.FlowUUID, name string, language i18n.Language, flowType flows.FlowType, revision int, expireAfter time.Duration, localization flows.Localization, nodes []flows.Node, ui json.RawMessage, a assets.Flow) (flows.Flow, error) {
+func NewFlow(uuid assets.FlowUUID, name string, language i18n.Language, flowType flows.FlowType, revision int, expireAfter time.Duration, sendWindows envs.SendWindows, params []*flows.Param, returns []*flows.Param, localization flows.Localization, nodes []flows.Node, errorNode core.NodeUUID, ui json.RawMessage, a assets.Flow) (flows.Flow, error) {
 	f := &flow{
 		uuid:         uuid,
 		name:         name,
@@ -65,8 +69,12 @@ func NewFlow(uuid assets.FlowUUID, name string, language i18n.Language, flowType
 		flowType:     flowType,
 		revision:     revision,
 		expireAfter:  expireAfter,
+		sendWindows:  sendWindows,
+		params:       params,
+		returns:      returns,
 		localization: localization,
 		nodes:        nodes,
+		errorNode:    errorNode,
 		nodeMap:      make(map[core.NodeUUID]flows.Node, len(nodes)),
 		ui:           ui,
 		asset:        a,
@@ -89,7 +97,11 @@ func (f *flow) SpecVersion() *semver.Version          { return f.specVersion }
 func (f *flow) Revision() int                         { return f.revision }
 func (f *flow) Language() i18n.Language               { return f.language }
 func (f *flow) Type() flows.FlowType                  { return f.flowType }
+func (f *flow) SendWindows() envs.SendWindows         { return f.sendWindows }
+func (f *flow) Params() []*flows.Param                { return f.params }
+func (f *flow) Returns() []*flows.Param               { return f.returns }
 func (f *flow) Nodes() []flows.Node                   { return f.nodes }
+func (f *flow) ErrorNode() core.NodeUUID              { return f.errorNode }
 func (f *flow) Localization() flows.Localization      { return f.localization }
 func (f *flow) UI() json.RawMessage                   { return f.ui }
 func (f *flow) GetNode(uuid core.NodeUUID) flows.Node { return f.nodeMap[uuid] }
@@ -113,6 +125,18 @@ func (f *flow) validate() error {
 		return fmt.Errorf("flow UI can't be larger than %d bytes (is %d)", flows.MaxUIBytes, len(f.ui))
 	}
 
+	if err := validateParams(f.params, "parameter"); err != nil {
+		return err
+	}
+	if err := validateParams(f.returns, "return value"); err != nil {
+		return err
+	}
+	for _, r := range f.returns {
+		if r.Required {
+			return fmt.Errorf("return value '%s' can't be required", r.Name)
+		}
+	}
+
 	// track UUIDs used by nodes and actions to ensure that they are unique
 	seenUUIDs := make(map[uuids.UUID]bool)
 
@@ -128,6 +152,10 @@ func (f *flow) validate() error {
 		}
 	}
 
+	if f.errorNode != "" && f.nodeMap[f.errorNode] == nil {
+		return fmt.Errorf("error node %s isn't a node in the flow", f.errorNode)
+	}
+
 	if err := f.localization.Validate(); err != nil {
 		return fmt.Errorf("invalid localization: %w", err)
 	}
@@ -135,6 +163,34 @@ func (f *flow) validate() error {
 	return nil
 }
 
+// defaults are part of the flow definition rather than any workspace so are checked with the default environment, which
+// accepts numbers and ISO dates
+var defaultParamEnv = envs.NewBuilder().Build()
+
+func validateParams(params []*flows.Param, kind string) error {
+	if len(params) > flows.MaxParamsPerFlow {
+		return fmt.Errorf("flow can't have more than %d %ss (has %d)", flows.MaxParamsPerFlow, kind, len(params))
+	}
+
+	seen := make(map[string]bool, len(params))
+	for _, p := range params {
+		if seen[p.Name] {
+			return fmt.Errorf("%s name '%s' isn't unique", kind, p.Name)
+		}
+		seen[p.Name] = true
+
+		if p.Required && p.Default != "" {
+			return fmt.Errorf("%s '%s' can't be required and have a default", kind, p.Name)
+		}
+		if p.Default != "" {
+			if _, err := p.Normalize(defaultParamEnv, p.Default); err != nil {
+				return fmt.Errorf("%s '%s' has an invalid default: %w", kind, p.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
 // Context returns the properties available in expressions
 //
 //	__default__:text -> the name
@@ -313,13 +369,17 @@ var _ flows.Flow = (*flow)(nil)
 type flowEnvelope struct {
 	migrations.Header13
 
-	Language           i18n.Language   `json:"language"             validate:"required,language"`
-	Type               flows.FlowType  `json:"type"                 validate:"required,flow_type"`
-	Revision           int             `json:"revision"`
-	ExpireAfterMinutes int             `json:"expire_after_minutes"`
-	Localization       localization    `json:"localization"`
-	Nodes              []*node         `json:"nodes"                validate:"dive,required"`
-	UI                 json.RawMessage `json:"_ui,omitempty"`
+	Language           i18n.Language    `json:"language"             validate:"required,language"`
+	Type               flows.FlowType   `json:"type"                 validate:"required,flow_type"`
+	Revision           int              `json:"revision"`
+	ExpireAfterMinutes int              `json:"expire_after_minutes"`
+	SendWindows        envs.SendWindows `json:"send_windows,omitempty"`
+	Params             []*flows.Param   `json:"params,omitempty"     validate:"dive"`
+	Returns            []*flows.Param   `json:"returns,omitempty"    validate:"dive"`
+	Localization       localization     `json:"localization"`
+	Nodes              []*node          `json:"nodes"                validate:"dive,required"`
+	ErrorNodeUUID      core.NodeUUID    `json:"error_node_uuid,omitempty" validate:"omitempty,uuid"`
+	UI                 json.RawMessage  `json:"_ui,omitempty"`
 }
 
 // ReadFlow reads a flow definition from the passed in byte array, migrating it to the spec version of the engine if necessary
@@ -371,7 +431,7 @@ func readFlow(data []byte, mc *migrations.Config, a assets.Flow) (flows.Flow, er
 		e.Localization = make(localization)
 	}
 
-	return NewFlow(e.UUID, e.Name, e.Language, e.Type, e.Revision, time.Duration(e.ExpireAfterMinutes)*time.Minute, e.Localization, nodes, e.UI, a)
+	return NewFlow(e.UUID, e.Name, e.Language, e.Type, e.Revision, time.Duration(e.ExpireAfterMinutes)*time.Minute, e.SendWindows, e.Params, e.Returns, e.Localization, nodes, e.ErrorNodeUUID, e.UI, a)
 }
 
 // checkFlowSize counts the nodes and measures the _ui section of a flow definition (current or legacy
@@ -410,8 +470,12 @@ func (f *flow) MarshalJSON() ([]byte, error) {
 		Type:               f.flowType,
 		Revision:           f.revision,
 		ExpireAfterMinutes: int(f.expireAfter / time.Minute),
+		SendWindows:        f.sendWindows,
+		Params:             f.params,
+		Returns:            f.returns,
 		Localization:       f.localization.(localization),
 		Nodes:              make([]*node, len(f.nodes)),
+		ErrorNodeUUID:      f.errorNode,
 		UI:                 f.ui,
 	}
 
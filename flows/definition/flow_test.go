@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/nyaruka/gocommon/dates"
 	"github.com/nyaruka/gocommon/i18n"
 	"github.com/nyaruka/gocommon/jsonx"
 	"github.com/nyaruka/goflow/assets"
@@ -116,6 +117,22 @@ func TestBrokenFlows(t *testing.T) {
 			"invalid_exit_dest.json",
 			"invalid node[uuid=a58be63b-907d-4a1a-856b-0bb5579d7507]: destination 714f1409-486e-4e8e-bb08-23e2943ef9f6 of exit[uuid=37d8813f-1402-4ad2-9cc2-e9054a96525b] isn't a known node",
 		},
+		{
+			"duplicate_param_name.json",
+			"parameter name 'country' isn't unique",
+		},
+		{
+			"invalid_param_default.json",
+			"parameter 'age' has an invalid default: 'old' is not a valid number",
+		},
+		{
+			"invalid_return_required.json",
+			"return value 'age' can't be required",
+		},
+		{
+			"invalid_error_node.json",
+			"error node 714f1409-486e-4e8e-bb08-23e2943ef9f6 isn't a node in the flow",
+		},
 		{
 			"invalid_localization_language.json",
 			"invalid localization: invalid language code 'vvv'",
@@ -177,7 +194,11 @@ func TestNewFlow(t *testing.T) {
     "type": "messaging",
     "revision": 123,
     "expire_after_minutes": 30,
+    "send_windows": [{"days": ["mon", "tue", "wed", "thu", "fri"], "start": "08:00", "end": "20:00"}],
+    "params": [{"name": "beer", "type": "text", "required": true}],
+    "returns": [{"name": "likes_beer", "type": "text", "default": "unknown"}],
     "localization": {},
+    "error_node_uuid": "baaf9085-1198-4b41-9a1c-cc51c6dbec99",
     "nodes": [
         {
             "uuid": "a58be63b-907d-4a1a-856b-0bb5579d7507",
@@ -269,6 +290,15 @@ func TestNewFlow(t *testing.T) {
 		flows.FlowTypeMessaging,
 		123,            // revision
 		30*time.Minute, // expires after minutes
+		envs.SendWindows{
+			envs.NewSendWindow(
+				[]envs.DayOfWeek{envs.DayOfWeekMonday, envs.DayOfWeekTuesday, envs.DayOfWeekWednesday, envs.DayOfWeekThursday, envs.DayOfWeekFriday},
+				dates.NewTimeOfDay(8, 0, 0, 0),
+				dates.NewTimeOfDay(20, 0, 0, 0),
+			),
+		},
+		[]*flows.Param{flows.NewParam("beer", flows.ParamTypeText, "", true)},
+		[]*flows.Param{flows.NewParam("likes_beer", flows.ParamTypeText, "unknown", false)},
 		definition.NewLocalization(),
 		[]flows.Node{
 			definition.NewNode(
@@ -330,6 +360,7 @@ func TestNewFlow(t *testing.T) {
 				},
 			),
 		},
+		core.NodeUUID("baaf9085-1198-4b41-9a1c-cc51c6dbec99"), // error node
 		nil, // no UI
 		nil, // no asset
 	)
@@ -563,7 +594,7 @@ func TestReadFlowWithOversizedUI(t *testing.T) {
 	assert.EqualError(t, err, fmt.Sprintf("flow UI can't be larger than %d bytes (is %d)", flows.MaxUIBytes, len(ui)))
 
 	// and the same limit is enforced when constructing a flow directly
-	_, err = definition.NewFlow("8ca44c09-791d-453a-9799-a70dd3303306", "Test", "eng", flows.FlowTypeMessaging, 1, 0, definition.NewLocalization(), nil, json.RawMessage(ui), nil)
+	_, err = definition.NewFlow("8ca44c09-791d-453a-9799-a70dd3303306", "Test", "eng", flows.FlowTypeMessaging, 1, 0, nil, nil, nil, definition.NewLocalization(), nil, "", json.RawMessage(ui), nil)
 	assert.EqualError(t, err, fmt.Sprintf("flow UI can't be larger than %d bytes (is %d)", flows.MaxUIBytes, len(ui)))
 }
 
@@ -0,0 +1,234 @@
+package definition
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/nyaruka/gocommon/i18n"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/uuids"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/definition/migrations"
+)
+
+// Conflict is a change made differently in both revisions being merged. Conflicts are reported for the item
+// identified by UUID with the property path inside that item (empty if the item was removed in one revision and
+// changed in the other) and are resolved in favor of our revision.
+type Conflict struct {
+	Item     ItemType        `json:"item"`
+	UUID     uuids.UUID      `json:"uuid"`
+	Language i18n.Language   `json:"language,omitempty"`
+	Property string          `json:"property,omitempty"`
+	Base     json.RawMessage `json:"base,omitempty"`
+	Ours     json.RawMessage `json:"ours,omitempty"`
+	Theirs   json.RawMessage `json:"theirs,omitempty"`
+}
+
+// Merge performs a three-way merge of two revisions of a flow which were both derived from the given base revision.
+// Changes made in only one revision are applied, and changes made differently in both are reported as conflicts and
+// resolved in favor of ours. The _ui section is merged without reporting conflicts, and the revision of the merged
+// flow is the greater of the two. An error is returned if the merged flow isn't valid, e.g. if one revision removed
+// a node that the other revision routes to.
+func Merge(base, ours, theirs flows.Flow) (flows.Flow, []*Conflict, error) {
+	b, o, t := readGeneric(base), readGeneric(ours), readGeneric(theirs)
+
+	m := &merger{conflicts: make([]*Conflict, 0)}
+	merged := make(map[string]any, len(o))
+
+	// merge translations last so that conflicts are reported in the same order as changes in a diff
+	keys := slices.DeleteFunc(sortedKeys(b, o, t), func(k string) bool { return k == "localization" })
+	keys = append(keys, "localization")
+
+	for _, key := range keys {
+		var v any
+		switch key {
+		case "revision":
+			v = max(ours.Revision(), theirs.Revision())
+		case "localization":
+			v = m.mergeValue(&mergeContext{item: ItemTypeTranslation}, valueOf(b, key), valueOf(o, key), valueOf(t, key))
+		case "_ui":
+			v = m.mergeValue(&mergeContext{quiet: true}, valueOf(b, key), valueOf(o, key), valueOf(t, key))
+		default:
+			v = m.mergeProperty(&mergeContext{item: ItemTypeFlow, uuid: uuids.UUID(ours.UUID())}, key, valueOf(b, key), valueOf(o, key), valueOf(t, key))
+		}
+
+		if v != absent {
+			merged[key] = v
+		}
+	}
+
+	flow, err := ReadFlow(jsonx.MustMarshal(merged), nil)
+	if err != nil {
+		return nil, m.conflicts, fmt.Errorf("merged flow is invalid: %w", err)
+	}
+
+	return flow, m.conflicts, nil
+}
+
+// used to represent a value which doesn't exist in a revision
+type absentValue struct{}
+
+var absent = absentValue{}
+
+func valueOf(o map[string]any, key string) any {
+	if v, exists := o[key]; exists {
+		return v
+	}
+	return absent
+}
+
+// tracks where in a flow we are whilst merging
+type mergeContext struct {
+	item     ItemType
+	uuid     uuids.UUID
+	language i18n.Language
+	path     []string
+	quiet    bool
+}
+
+// gets the context for the given property of the current object
+func (c *mergeContext) property(key string) *mergeContext {
+	child := &mergeContext{item: c.item, uuid: c.uuid, language: c.language, path: slices.Clone(c.path), quiet: c.quiet}
+
+	// translations are keyed by language and then by item UUID
+	if c.item == ItemTypeTranslation && c.language == "" {
+		child.language = i18n.Language(key)
+	} else if c.item == ItemTypeTranslation && c.uuid == "" {
+		child.uuid = uuids.UUID(key)
+	} else {
+		child.path = append(child.path, key)
+	}
+	return child
+}
+
+// gets the context for an item in a list of items identified by UUID
+func (c *mergeContext) listItem(item ItemType, uuid uuids.UUID) *mergeContext {
+	return &mergeContext{item: item, uuid: uuid, quiet: c.quiet}
+}
+
+type merger struct {
+	conflicts []*Conflict
+}
+
+func (m *merger) mergeValue(c *mergeContext, base, ours, theirs any) any {
+	if reflect.DeepEqual(ours, theirs) || reflect.DeepEqual(base, theirs) {
+		return ours
+	}
+	if reflect.DeepEqual(base, ours) {
+		return theirs
+	}
+
+	// both revisions have changed this value in different ways, but if it's an object we can try to merge its properties
+	om, oIsMap := ours.(map[string]any)
+	tm, tIsMap := theirs.(map[string]any)
+	if oIsMap && tIsMap {
+		bm, _ := base.(map[string]any)
+		return m.mergeObject(c, bm, om, tm)
+	}
+
+	m.conflict(c, base, ours, theirs)
+	return ours
+}
+
+func (m *merger) mergeObject(c *mergeContext, base, ours, theirs map[string]any) map[string]any {
+	merged := make(map[string]any, len(ours))
+
+	for _, key := range sortedKeys(base, ours, theirs) {
+		if v := m.mergeProperty(c, key, valueOf(base, key), valueOf(ours, key), valueOf(theirs, key)); v != absent {
+			merged[key] = v
+		}
+	}
+	return merged
+}
+
+// merges a property of an object, which if it holds a list of items identified by UUID, merges those items
+func (m *merger) mergeProperty(c *mergeContext, key string, base, ours, theirs any) any {
+	if item, isItemProp := itemListProperties[key]; isItemProp && isItemList(ours) && isItemList(theirs) && (base == absent || isItemList(base)) {
+		return m.mergeItemList(c, item, base, ours, theirs)
+	}
+	return m.mergeValue(c.property(key), base, ours, theirs)
+}
+
+// merges a list of items identified by UUID, using the ordering of ours, with any items added in theirs inserted
+// after the item which precedes them in theirs
+func (m *merger) mergeItemList(c *mergeContext, item ItemType, base, ours, theirs any) any {
+	if reflect.DeepEqual(ours, theirs) || reflect.DeepEqual(base, theirs) {
+		return ours
+	}
+	if reflect.DeepEqual(base, ours) {
+		return theirs
+	}
+
+	_, bItems := indexItems(base)
+	oOrder, oItems := indexItems(ours)
+	tOrder, tItems := indexItems(theirs)
+
+	// work out the order of the merged list
+	order := slices.Clone(oOrder)
+	for i, u := range tOrder {
+		if oItems[u] == nil && bItems[u] == nil {
+			pos := 0
+			for j := i - 1; j >= 0; j-- {
+				if k := slices.Index(order, tOrder[j]); k >= 0 {
+					pos = k + 1
+					break
+				}
+			}
+			order = slices.Insert(order, pos, u)
+		}
+	}
+	for _, u := range tOrder {
+		if !slices.Contains(order, u) && bItems[u] != nil {
+			order = append(order, u) // removed by ours but changed by theirs
+		}
+	}
+
+	merged := make([]any, 0, len(order))
+	for _, u := range order {
+		v := m.mergeValue(c.listItem(item, u), itemOrAbsent(bItems, u), itemOrAbsent(oItems, u), itemOrAbsent(tItems, u))
+		if v != absent {
+			merged = append(merged, v)
+		}
+	}
+	return merged
+}
+
+func (m *merger) conflict(c *mergeContext, base, ours, theirs any) {
+	if c.quiet {
+		return
+	}
+
+	m.conflicts = append(m.conflicts, &Conflict{
+		Item:     c.item,
+		UUID:     c.uuid,
+		Language: c.language,
+		Property: strings.Join(c.path, "."),
+		Base:     marshalIfPresent(base),
+		Ours:     marshalIfPresent(ours),
+		Theirs:   marshalIfPresent(theirs),
+	})
+}
+
+// checks whether the given value is a list of objects with UUIDs
+func isItemList(v any) bool {
+	l, isList := v.([]any)
+	if !isList {
+		return false
+	}
+	for _, i := range l {
+		if migrations.GetObjectUUID(i) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func itemOrAbsent(items map[uuids.UUID]map[string]any, u uuids.UUID) any {
+	if i, exists := items[u]; exists {
+		return i
+	}
+	return absent
+}
@@ -0,0 +1,197 @@
+package definition_test
+
+import (
+	"testing"
+
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/uuids"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/actions"
+	"github.com/nyaruka/goflow/flows/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	base := readRevision(t)
+
+	// changes to different items merge cleanly
+	ours := readRevision(t,
+		`"revision": 12`, `"revision": 13`,
+		`"What is your favorite color?"`, `"What's your favorite color?"`,
+		`"arguments": ["blue"]`, `"arguments": ["blue navy"]`,
+		`"top": 200`, `"top": 300`,
+	)
+	theirs := readRevision(t,
+		`"revision": 12`, `"revision": 14`,
+		`"name": "Favorites"`, `"name": "Favorite Colors"`,
+		`"¿Cuál es tu color favorito?"`, `"¿Cuál es su color favorito?"`,
+		`"text": "Thanks, @results.color is a great color"
+                }`, `"text": "Thanks, @results.color is a great color"
+                },
+                {
+                    "uuid": "a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b03",
+                    "type": "send_msg",
+                    "text": "Bye"
+                }`,
+		`"left": 0, "top": 0`, `"left": 100, "top": 0`,
+	)
+
+	merged, conflicts, err := definition.Merge(base, ours, theirs)
+	require.NoError(t, err)
+	assert.Equal(t, []*definition.Conflict{}, conflicts)
+
+	assert.Equal(t, "Favorite Colors", merged.Name())
+	assert.Equal(t, 14, merged.Revision())
+	assert.Equal(t, "What's your favorite color?", merged.Nodes()[0].Actions()[0].(*actions.SendMsg).Text)
+	assert.Equal(t, []string{"¿Cuál es su color favorito?"}, merged.Localization().GetItemTranslation("spa", "a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b01", "text"))
+	assert.Len(t, merged.Nodes()[1].Actions(), 2)
+	assert.JSONEq(t, `{
+		"nodes": {
+			"0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c01": {"position": {"left": 100, "top": 0}},
+			"0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02": {"position": {"left": 0, "top": 300}}
+		}
+	}`, string(merged.UI()))
+
+	// merging with itself gives the same changes as one side
+	assert.Equal(t, definition.Diff(base, ours), definition.Diff(base, mustMerge(t, base, ours, ours)))
+
+	// items added in both revisions are all kept, with items added by theirs placed after their preceding item
+	ours = readRevision(t,
+		`"arguments": ["blue"],
+                        "category_uuid": "c4e2b1a0-7d3f-4a1e-9c2b-3e5f6a7b8c02"
+                    }`, `"arguments": ["blue"],
+                        "category_uuid": "c4e2b1a0-7d3f-4a1e-9c2b-3e5f6a7b8c02"
+                    },
+                    {
+                        "uuid": "d1b2c3a4-5e6f-4a7b-8c9d-0e1f2a3b4c03",
+                        "type": "has_any_word",
+                        "arguments": ["navy"],
+                        "category_uuid": "c4e2b1a0-7d3f-4a1e-9c2b-3e5f6a7b8c02"
+                    }`,
+	)
+	theirs = readRevision(t,
+		`"arguments": ["blue"],
+                        "category_uuid": "c4e2b1a0-7d3f-4a1e-9c2b-3e5f6a7b8c02"
+                    }`, `"arguments": ["blue"],
+                        "category_uuid": "c4e2b1a0-7d3f-4a1e-9c2b-3e5f6a7b8c02"
+                    },
+                    {
+                        "uuid": "d1b2c3a4-5e6f-4a7b-8c9d-0e1f2a3b4c04",
+                        "type": "has_any_word",
+                        "arguments": ["sky"],
+                        "category_uuid": "c4e2b1a0-7d3f-4a1e-9c2b-3e5f6a7b8c02"
+                    }`,
+		`"arguments": ["red"]`, `"arguments": ["red scarlet"]`,
+	)
+
+	merged, conflicts, err = definition.Merge(base, ours, theirs)
+	require.NoError(t, err)
+	assert.Equal(t, []*definition.Conflict{}, conflicts)
+
+	cases := jsonx.MustMarshal(merged.Nodes()[0].Router())
+	assert.Contains(t, string(cases), `"arguments":["red scarlet"]`)
+	assert.Regexp(t, `"blue".*"sky".*"navy"`, string(cases))
+
+	// changes to the same properties of the same items are conflicts resolved in favor of ours
+	ours = readRevision(t,
+		`"What is your favorite color?"`, `"What's your favorite color?"`,
+		`"¿Cuál es tu color favorito?"`, `"¿Cuál es su color favorito?"`,
+		`"name": "Favorites"`, `"name": "Colors"`,
+		`"type": "send_msg",
+                    "text": "Thanks`, `"type": "send_msg",
+                    "text": "Gracias`,
+	)
+	theirs = readRevision(t,
+		`"What is your favorite color?"`, `"Tell me your favorite color"`,
+		`"¿Cuál es tu color favorito?"`, `"Dime tu color favorito"`,
+		`"name": "Favorites"`, `"name": "Favorite Colors"`,
+		`"expire_after_minutes": 60`, `"expire_after_minutes": 120`,
+		`,
+                    "destination_uuid": "0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02"`, ``,
+		`,
+        {
+            "uuid": "0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02",
+            "actions": [
+                {
+                    "uuid": "a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b02",
+                    "type": "send_msg",
+                    "text": "Thanks, @results.color is a great color"
+                }
+            ],
+            "exits": [
+                {
+                    "uuid": "e0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a04"
+                }
+            ]
+        }`, ``,
+		`"position": {"left": 0, "top": 0}},`, `"position": {"left": 0, "top": 0}}`,
+		`
+            "0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02": {"position": {"left": 0, "top": 200}}`, ``,
+	)
+
+	merged, conflicts, err = definition.Merge(base, ours, theirs)
+	require.NoError(t, err)
+
+	assert.Len(t, conflicts, 4)
+	assert.Equal(t, []*definition.Conflict{
+		{
+			Item:     definition.ItemTypeFlow,
+			UUID:     "8f1e1b8c-5d1c-4b0e-9a3e-0b3c6f7b8a11",
+			Property: "name",
+			Base:     []byte(`"Favorites"`),
+			Ours:     []byte(`"Colors"`),
+			Theirs:   []byte(`"Favorite Colors"`),
+		},
+		{
+			Item:     definition.ItemTypeAction,
+			UUID:     "a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b01",
+			Property: "text",
+			Base:     []byte(`"What is your favorite color?"`),
+			Ours:     []byte(`"What's your favorite color?"`),
+			Theirs:   []byte(`"Tell me your favorite color"`),
+		},
+	}, conflicts[:2])
+
+	// node removed by theirs but changed by ours
+	assert.Equal(t, definition.ItemTypeNode, conflicts[2].Item)
+	assert.Equal(t, uuids.UUID("0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02"), conflicts[2].UUID)
+	assert.Equal(t, "", conflicts[2].Property)
+	assert.JSONEq(t, string(jsonx.MustMarshal(base.Nodes()[1])), string(conflicts[2].Base))
+	assert.JSONEq(t, string(jsonx.MustMarshal(ours.Nodes()[1])), string(conflicts[2].Ours))
+	assert.Nil(t, conflicts[2].Theirs)
+
+	assert.Equal(t, &definition.Conflict{
+		Item:     definition.ItemTypeTranslation,
+		UUID:     "a1f3e0b8-6c2e-4e0f-8a55-1d6c4c1f0b01",
+		Language: "spa",
+		Property: "text",
+		Base:     []byte(`["¿Cuál es tu color favorito?"]`),
+		Ours:     []byte(`["¿Cuál es su color favorito?"]`),
+		Theirs:   []byte(`["Dime tu color favorito"]`),
+	}, conflicts[3])
+
+	assert.Equal(t, "Colors", merged.Name())
+	assert.Equal(t, 120, int(merged.ExpireAfter().Minutes()))
+	assert.Equal(t, "What's your favorite color?", merged.Nodes()[0].Actions()[0].(*actions.SendMsg).Text)
+	assert.Len(t, merged.Nodes(), 2)
+	assert.Equal(t, core.NodeUUID(""), merged.Nodes()[0].Exits()[0].DestinationUUID())
+
+	// an error is returned if the merged flow isn't valid
+	ours = readRevision(t, `{
+                    "uuid": "e0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a03"
+                }`, `{
+                    "uuid": "e0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a03",
+                    "destination_uuid": "0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02"
+                }`)
+
+	_, _, err = definition.Merge(base, ours, theirs)
+	assert.ErrorContains(t, err, "merged flow is invalid: invalid node[uuid=0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c01]: destination 0c4b1a9e-2f57-4d3e-9b4b-6f1e8d1f7c02 of exit[uuid=e0a1b2c3-d4e5-4f60-8a1b-2c3d4e5f6a03] isn't a known node")
+}
+
+func mustMerge(t *testing.T, base, ours, theirs flows.Flow) flows.Flow {
+	merged, _, err := definition.Merge(base, ours, theirs)
+	require.NoError(t, err)
+	return merged
+}
@@ -118,6 +118,12 @@ func (f Flow) Nodes() []Node {
 // Node holds a node definition
 type Node map[string]any
 
+// UUID returns the uuid of this node
+func (n Node) UUID() uuids.UUID {
+	d, _ := n["uuid"].(string)
+	return uuids.UUID(d)
+}
+
 // Actions returns the actions on this node
 func (n Node) Actions() []Action {
 	d, _ := n["actions"].([]any)
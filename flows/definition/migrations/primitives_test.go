@@ -28,7 +28,8 @@ func TestPrimitives(t *testing.T) {
 	}})
 	assert.Equal(t, []migrations.Node{migrations.Node(map[string]any{})}, f.Nodes())
 
-	n := migrations.Node(map[string]any{}) // actions and router are not set
+	n := migrations.Node(map[string]any{}) // uuid, actions and router are not set
+	assert.Equal(t, uuids.UUID(""), n.UUID())
 	assert.Equal(t, []migrations.Action{}, n.Actions())
 	assert.Nil(t, n.Router())
 
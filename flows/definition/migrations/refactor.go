@@ -0,0 +1,227 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/uuids"
+	"github.com/nyaruka/goflow/excellent/refactor"
+	"github.com/nyaruka/goflow/utils"
+)
+
+// RenameType is the type of thing being renamed
+type RenameType string
+
+const (
+	RenameTypeField  RenameType = "field"
+	RenameTypeResult RenameType = "result"
+	RenameTypeGlobal RenameType = "global"
+	RenameTypeLocal  RenameType = "local"
+)
+
+// the context paths on which each type of thing can be looked up
+var renameContextPaths = map[RenameType][][]string{
+	RenameTypeField: {
+		{"fields"},
+		{"contact", "fields"},
+		{"parent", "fields"},
+		{"parent", "contact", "fields"},
+		{"child", "fields"},
+		{"child", "contact", "fields"},
+	},
+	RenameTypeResult: {
+		{"results"},
+		{"run", "results"},
+		{"parent", "results"},
+		{"child", "results"},
+	},
+	RenameTypeGlobal: {
+		{"globals"},
+	},
+	RenameTypeLocal: {
+		{"locals"},
+		{"run", "locals"},
+	},
+}
+
+// Rename is a rename of a contact field key, result name, global key or local name. Results are renamed by name
+// and references to them are updated using the result key, e.g. renaming "Favorite Color" to "Color" changes
+// @results.favorite_color to @results.color.
+type Rename struct {
+	Type RenameType `json:"type" validate:"required,eq=field|eq=result|eq=global|eq=local"`
+	From string     `json:"from" validate:"required"`
+	To   string     `json:"to"   validate:"required"`
+}
+
+// NewRename creates a new rename
+func NewRename(typ RenameType, from, to string) *Rename {
+	return &Rename{Type: typ, From: from, To: to}
+}
+
+// gets the from and to keys used to reference the thing being renamed in expressions
+func (r *Rename) keys() (string, string) {
+	if r.Type == RenameTypeResult {
+		return utils.Snakify(r.From), utils.Snakify(r.To)
+	}
+	return r.From, r.To
+}
+
+// checks whether the given result name matches the result being renamed
+func (r *Rename) isResult(name string) bool {
+	return r.Type == RenameTypeResult && utils.Snakify(name) == utils.Snakify(r.From)
+}
+
+func (r *Rename) rewriteTemplate(s string) string {
+	paths := renameContextPaths[r.Type]
+	topLevels := make([]string, 0, len(paths))
+	for _, p := range paths {
+		topLevels = append(topLevels, p[0])
+	}
+
+	from, to := r.keys()
+
+	// an expression which can't be parsed is left as is
+	refactored, _ := refactor.Template(s, topLevels, refactor.ContextLookupRename(paths, from, to))
+	return refactored
+}
+
+// applies this rename to the given action, returning whether anything changed
+func (r *Rename) refactorAction(f Flow, catalog *TemplateCatalog, a Action) bool {
+	changed := false
+
+	switch a.Type() {
+	case "set_contact_field":
+		field, _ := a["field"].(map[string]any)
+		key, _ := field["key"].(string)
+		if r.Type == RenameTypeField && strings.EqualFold(key, r.From) {
+			field["key"] = r.To
+			changed = true
+		}
+	case "set_run_result":
+		name, _ := a["name"].(string)
+		if r.isResult(name) {
+			a["name"] = r.To
+			changed = true
+		}
+	case "set_run_local":
+		local, _ := a["local"].(string)
+		if r.Type == RenameTypeLocal && strings.EqualFold(local, r.From) {
+			a["local"] = r.To
+			changed = true
+		}
+	}
+
+	rewriteActionTemplates(f, catalog, a, r.trackedRewrite(&changed))
+
+	return changed
+}
+
+// applies this rename to the given router, returning whether anything changed
+func (r *Rename) refactorRouter(f Flow, catalog *TemplateCatalog, router Router) bool {
+	changed := false
+
+	resultName, _ := router["result_name"].(string)
+	if r.isResult(resultName) {
+		router["result_name"] = r.To
+		changed = true
+	}
+
+	rewriteRouterTemplates(f, catalog, router, r.trackedRewrite(&changed))
+
+	return changed
+}
+
+// creates a template rewrite function which records whether any template was changed
+func (r *Rename) trackedRewrite(changed *bool) func(string) string {
+	return func(s string) string {
+		refactored := r.rewriteTemplate(s)
+		if refactored != s {
+			*changed = true
+		}
+		return refactored
+	}
+}
+
+// Refactor applies the given renames to the given flow, which must be at the latest spec version, and returns
+// the UUIDs of the actions and nodes (for routers) that were changed. Renames are applied to templates and their
+// translations, case arguments, and the keys and names set by set_contact_field, set_run_result and set_run_local
+// actions and routers. Contact queries are not refactored.
+func Refactor(f Flow, renames []*Rename) []uuids.UUID {
+	catalog := GetTemplateCatalog(latestVersion())
+	changed := make([]uuids.UUID, 0)
+
+	for _, n := range f.Nodes() {
+		for _, a := range n.Actions() {
+			actionChanged := false
+			for _, r := range renames {
+				if r.refactorAction(f, catalog, a) {
+					actionChanged = true
+				}
+			}
+			if actionChanged {
+				changed = append(changed, a.UUID())
+			}
+		}
+
+		if router := n.Router(); router != nil {
+			routerChanged := false
+			for _, r := range renames {
+				if r.refactorRouter(f, catalog, router) {
+					routerChanged = true
+				}
+			}
+			if routerChanged {
+				changed = append(changed, n.UUID())
+			}
+		}
+	}
+
+	return changed
+}
+
+// Refactored is a flow definition which has been refactored
+type Refactored struct {
+	Definition []byte
+	Changed    []uuids.UUID
+}
+
+// RefactorDefinitions migrates each of the given flow definitions to the latest spec version and applies the given
+// renames to them. The results are in the same order as the given definitions.
+func RefactorDefinitions(defs [][]byte, renames []*Rename, cfg *Config) ([]*Refactored, error) {
+	if err := utils.Validate(renames); err != nil {
+		return nil, fmt.Errorf("invalid renames: %w", err)
+	}
+
+	refactored := make([]*Refactored, len(defs))
+
+	for i, data := range defs {
+		migrated, err := MigrateToLatest(data, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to migrate flow definition #%d: %w", i, err)
+		}
+
+		flow, err := ReadFlow(migrated)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read flow definition #%d: %w", i, err)
+		}
+
+		changed := Refactor(flow, renames)
+
+		refactored[i] = &Refactored{Definition: jsonx.MustMarshal(flow), Changed: changed}
+	}
+
+	return refactored, nil
+}
+
+// gets the latest version for which there is a migration
+func latestVersion() *semver.Version {
+	var latest *semver.Version
+	for v := range registered {
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+		}
+	}
+	return latest
+}
@@ -0,0 +1,157 @@
+package migrations_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/uuids"
+	"github.com/nyaruka/goflow/flows/definition"
+	"github.com/nyaruka/goflow/flows/definition/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefactor(t *testing.T) {
+	tcs := []struct {
+		rename  *migrations.Rename
+		changed []uuids.UUID
+		checks  map[string]any
+	}{
+		{
+			rename: migrations.NewRename(migrations.RenameTypeField, "age", "years"),
+			changed: []uuids.UUID{
+				"8eebd020-1af5-431c-b943-aa670fc74da9", // only changed in translation
+				"c2bd8ce9-3bca-4d8b-8fe7-0e7e1e0ae7c2",
+				"365293c7-633c-45bd-96b7-0b059766588d",
+				"aa3e1d4b-6fb1-4f3c-9d1e-5d6b6dc8a4b2",
+			},
+			checks: map[string]any{
+				"nodes.0.actions.1.text":                                            "You said @results.favorite_color and @(upper(contact.fields.years))",
+				"nodes.0.router.cases.0.arguments.0":                                "@fields.years",
+				"nodes.1.actions.0.field.key":                                       "years",
+				"nodes.1.actions.0.value":                                           "@(fields.years + 1)",
+				"localization.spa.8eebd020-1af5-431c-b943-aa670fc74da9.text.0":      "Hola @fields.years @globals.org_name",
+				"localization.spa.4a6c3b0b-0658-4a93-ae37-bee68f6a6a87.arguments.0": "@fields.years",
+			},
+		},
+		{
+			rename: migrations.NewRename(migrations.RenameTypeResult, "Favorite Color", "Colour"),
+			changed: []uuids.UUID{
+				"c2bd8ce9-3bca-4d8b-8fe7-0e7e1e0ae7c2",
+				"365293c7-633c-45bd-96b7-0b059766588d",
+				"f01d693b-2af2-49fb-9e38-146eb7b0f8f1",
+			},
+			checks: map[string]any{
+				"nodes.0.actions.1.text":     "You said @results.colour and @(upper(contact.fields.AGE))",
+				"nodes.0.router.result_name": "Colour",
+				"nodes.1.actions.1.name":     "Colour",
+				"nodes.1.actions.1.value":    "@results.colour.category",
+			},
+		},
+		{
+			rename: migrations.NewRename(migrations.RenameTypeGlobal, "org_name", "org"),
+			changed: []uuids.UUID{
+				"8eebd020-1af5-431c-b943-aa670fc74da9",
+				"e5a03dde-3b2f-4603-b5d0-d927f6bcc361",
+			},
+			checks: map[string]any{
+				"nodes.0.actions.0.text": "Hello",
+				"localization.spa.8eebd020-1af5-431c-b943-aa670fc74da9.text.0": "Hola @fields.age @globals.org",
+				"nodes.1.actions.3.headers.Org":                                "@globals.org",
+				"nodes.1.actions.3.url":                                        "http://example.com/?count=@locals.counter&key=@globals.api_key",
+			},
+		},
+		{
+			rename: migrations.NewRename(migrations.RenameTypeLocal, "counter", "visits"),
+			changed: []uuids.UUID{
+				"5b6fb7b2-dd7b-47d9-a5c6-1ea0d7b4c0e4",
+				"e5a03dde-3b2f-4603-b5d0-d927f6bcc361",
+			},
+			checks: map[string]any{
+				"nodes.1.actions.2.local": "visits",
+				"nodes.1.actions.3.url":   "http://example.com/?count=@locals.visits&key=@globals.api_key",
+			},
+		},
+		{
+			rename:  migrations.NewRename(migrations.RenameTypeField, "gender", "sex"),
+			changed: []uuids.UUID{},
+			checks:  map[string]any{},
+		},
+	}
+
+	for _, tc := range tcs {
+		flow := readFlow(t, "testdata/refactor.json")
+
+		changed := migrations.Refactor(flow, []*migrations.Rename{tc.rename})
+		assert.Equal(t, tc.changed, changed, "changed mismatch for rename %v", tc.rename)
+
+		for path, expected := range tc.checks {
+			assert.Equal(t, expected, lookupPath(t, flow, path), "value mismatch at %s for rename %v", path, tc.rename)
+		}
+	}
+}
+
+func TestRefactorDefinitions(t *testing.T) {
+	def1, err := os.ReadFile("testdata/refactor.json")
+	require.NoError(t, err)
+	def2, err := os.ReadFile("testdata/templates1.json") // older version which will be migrated
+	require.NoError(t, err)
+
+	refactored, err := migrations.RefactorDefinitions([][]byte{def1, def2}, []*migrations.Rename{
+		migrations.NewRename(migrations.RenameTypeField, "age", "years"),
+		migrations.NewRename(migrations.RenameTypeLocal, "counter", "visits"),
+	}, migrations.DefaultConfig)
+	require.NoError(t, err)
+	assert.Len(t, refactored, 2)
+
+	assert.Equal(t, []uuids.UUID{
+		"8eebd020-1af5-431c-b943-aa670fc74da9",
+		"c2bd8ce9-3bca-4d8b-8fe7-0e7e1e0ae7c2",
+		"365293c7-633c-45bd-96b7-0b059766588d",
+		"aa3e1d4b-6fb1-4f3c-9d1e-5d6b6dc8a4b2",
+		"5b6fb7b2-dd7b-47d9-a5c6-1ea0d7b4c0e4",
+		"e5a03dde-3b2f-4603-b5d0-d927f6bcc361",
+	}, refactored[0].Changed)
+	assert.Contains(t, string(refactored[0].Definition), `"@(fields.years + 1)"`)
+
+	_, err = definition.ReadFlow(refactored[0].Definition, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []uuids.UUID{}, refactored[1].Changed)
+	assert.Contains(t, string(refactored[1].Definition), `"spec_version":"14.4.2"`)
+
+	// invalid renames are rejected
+	_, err = migrations.RefactorDefinitions([][]byte{def1}, []*migrations.Rename{
+		migrations.NewRename("contact", "age", "years"),
+	}, migrations.DefaultConfig)
+	assert.EqualError(t, err, "invalid renames: field 'type' failed tag 'eq=field|eq=result|eq=global|eq=local'")
+
+	// as are invalid definitions
+	_, err = migrations.RefactorDefinitions([][]byte{def1, []byte(`{}`)}, []*migrations.Rename{
+		migrations.NewRename(migrations.RenameTypeField, "age", "years"),
+	}, migrations.DefaultConfig)
+	assert.ErrorContains(t, err, "unable to migrate flow definition #1")
+}
+
+// looks up a dot separated path in generic JSON
+func lookupPath(t *testing.T, v any, path string) any {
+	j := jsonx.MustMarshal(v)
+	var g any
+	jsonx.MustUnmarshal(j, &g)
+
+	for _, p := range strings.Split(path, ".") {
+		switch typed := g.(type) {
+		case map[string]any:
+			g = typed[p]
+		case []any:
+			var i int
+			jsonx.MustUnmarshal([]byte(p), &i)
+			g = typed[i]
+		default:
+			require.Fail(t, "can't lookup %s in %v", p, g)
+		}
+	}
+	return g
+}
@@ -37,7 +37,36 @@ func GetTemplateCatalog(v *semver.Version) *TemplateCatalog {
 }
 
 func RewriteTemplates(f Flow, catalog *TemplateCatalog, tx func(string) string) {
-	txl := func(container, key, val any) any {
+	for _, n := range f.Nodes() {
+		for _, a := range n.Actions() {
+			rewriteActionTemplates(f, catalog, a, tx)
+		}
+
+		if n.Router() != nil {
+			rewriteRouterTemplates(f, catalog, n.Router(), tx)
+		}
+	}
+}
+
+func rewriteActionTemplates(f Flow, catalog *TemplateCatalog, a Action, tx func(string) string) {
+	txl := localizedTransform(f, tx)
+
+	for _, p := range catalog.Actions[a.Type()] {
+		rewriteTemplates(a, p, txl)
+	}
+}
+
+func rewriteRouterTemplates(f Flow, catalog *TemplateCatalog, r Router, tx func(string) string) {
+	txl := localizedTransform(f, tx)
+
+	for _, p := range catalog.Routers[r.Type()] {
+		rewriteTemplates(r, p, txl)
+	}
+}
+
+// creates a transform which applies tx to a template value as well as its translations if it's localizable
+func localizedTransform(f Flow, tx func(string) string) func(container, key, val any) any {
+	return func(container, key, val any) any {
 		localizableUUID := GetObjectUUID(container)
 		if localizableUUID != "" {
 			prop, _ := key.(string)
@@ -63,20 +92,6 @@ func RewriteTemplates(f Flow, catalog *TemplateCatalog, tx func(string) string)
 		}
 		return val
 	}
-
-	for _, n := range f.Nodes() {
-		for _, a := range n.Actions() {
-			for _, p := range catalog.Actions[a.Type()] {
-				rewriteTemplates(a, p, txl)
-			}
-		}
-
-		if n.Router() != nil {
-			for _, p := range catalog.Routers[n.Router().Type()] {
-				rewriteTemplates(n.Router(), p, txl)
-			}
-		}
-	}
 }
 
 func rewriteTemplates[T ~map[string]any](o T, path string, tx func(container, key, val any) any) {
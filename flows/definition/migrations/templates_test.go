@@ -18,8 +18,9 @@ func TestCurrentTemplateCatalog(t *testing.T) {
 	s := &migrations.TemplateCatalog{
 		Actions: make(map[string][]string),
 		Routers: map[string][]string{
-			"random": {".operand", ".cases[*].arguments[*]", ".wait.phone"},
-			"switch": {".operand", ".cases[*].arguments[*]", ".wait.phone"},
+			"command": {".operand", ".wait.phone"},
+			"random":  {".operand", ".cases[*].arguments[*]", ".wait.phone"},
+			"switch":  {".operand", ".cases[*].arguments[*]", ".wait.phone"},
 		},
 	}
 
@@ -105,6 +105,8 @@ func (n *node) Inspect(dependency func(flows.Action, flows.Router, assets.Refere
 			result(nil, n.router, r)
 		}, func(r assets.Reference) {
 			dependency(nil, n.router, r)
+		}, func(l string) {
+			local(nil, n.router, l)
 		})
 	}
 }
@@ -19,6 +19,7 @@ type sessionAssets struct {
 	channels  *core.ChannelAssets
 	fields    *core.FieldAssets
 	flows     flows.FlowAssets
+	functions *core.FunctionAssets
 	globals   *core.GlobalAssets
 	groups    *core.GroupAssets
 	labels    *core.LabelAssets
@@ -46,6 +47,10 @@ func NewSessionAssets(env envs.Environment, source assets.Source, migrationConfi
 	if err != nil {
 		return nil, err
 	}
+	functions, err := source.Functions()
+	if err != nil {
+		return nil, err
+	}
 	globals, err := source.Globals()
 	if err != nil {
 		return nil, err
@@ -105,6 +110,7 @@ func NewSessionAssets(env envs.Environment, source assets.Source, migrationConfi
 		channels:  core.NewChannelAssets(channels),
 		fields:    fieldAssets,
 		flows:     definition.NewFlowAssets(source, migrationConfig),
+		functions: core.NewFunctionAssets(functions, flows.RunContextTopLevels),
 		globals:   core.NewGlobalAssets(globals),
 		groups:    groupAssets,
 		labels:    core.NewLabelAssets(labels),
@@ -122,6 +128,7 @@ func (s *sessionAssets) Campaigns() *core.CampaignAssets { return s.campaigns }
 func (s *sessionAssets) Channels() *core.ChannelAssets   { return s.channels }
 func (s *sessionAssets) Fields() *core.FieldAssets       { return s.fields }
 func (s *sessionAssets) Flows() flows.FlowAssets         { return s.flows }
+func (s *sessionAssets) Functions() *core.FunctionAssets { return s.functions }
 func (s *sessionAssets) Globals() *core.GlobalAssets     { return s.globals }
 func (s *sessionAssets) Groups() *core.GroupAssets       { return s.groups }
 func (s *sessionAssets) Labels() *core.LabelAssets       { return s.labels }
@@ -148,7 +148,7 @@ func TestSessionAssetsWithSourceErrors(t *testing.T) {
 	_, err = sa.Flows().FindByName("Catch All")
 	assert.EqualError(t, err, "unable to load flow assets")
 
-	for _, errType := range []string{"channels", "fields", "globals", "groups", "labels", "llms", "locations", "resthooks", "templates", "users"} {
+	for _, errType := range []string{"channels", "fields", "functions", "globals", "groups", "labels", "llms", "locations", "resthooks", "templates", "users"} {
 		source.currentErrType = errType
 		_, err = engine.NewSessionAssets(env, source, nil)
 		assert.EqualError(t, err, fmt.Sprintf("unable to load %s assets", errType), "error mismatch for type %s", errType)
@@ -187,6 +187,10 @@ func (s *testSource) FlowByName(name string) (assets.Flow, error) {
 	return nil, s.err("flow")
 }
 
+func (s *testSource) Functions() ([]assets.Function, error) {
+	return nil, s.err("functions")
+}
+
 func (s *testSource) Globals() ([]assets.Global, error) {
 	return nil, s.err("globals")
 }
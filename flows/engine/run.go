@@ -25,6 +25,33 @@ const (
 	persistWebhookBytesLimit = 10_000 // max bytes of webhook response we will persist in a run
 )
 
+// a fork in a run's path whose branches haven't all been executed yet
+type fork struct {
+	NodeUUID core.NodeUUID    `json:"node_uuid"         validate:"required,uuid"`
+	Pending  []flows.ExitUUID `json:"pending,omitempty" validate:"dive,uuid"`
+}
+
+// an error which sent a run to the error node of its flow
+type runError struct {
+	Text     string        `json:"text"      validate:"required"`
+	NodeUUID core.NodeUUID `json:"node_uuid" validate:"required,uuid"`
+}
+
+// Context returns the properties available in expressions
+//
+//	__default__:text -> the error text
+//	text:text -> the error text
+//	node:text -> the UUID of the node where the error occurred
+//
+// @context error
+func (e *runError) Context(env envs.Environment) map[string]types.XValue {
+	return map[string]types.XValue{
+		"__default__": types.NewXText(e.Text),
+		"text":        types.NewXText(e.Text),
+		"node":        types.NewXText(string(e.NodeUUID)),
+	}
+}
+
 type run struct {
 	uuid    core.RunUUID
 	session *session
@@ -39,6 +66,13 @@ type run struct {
 	hadInput bool
 	status   core.RunStatus
 	webhook  *flows.WebhookCall
+	forks    []*fork
+	error    *runError
+	params   flows.ParamValues
+	returns  flows.ParamValues
+
+	// whether the run should be sent to the error node of its flow
+	catching bool
 
 	createdOn  time.Time
 	modifiedOn time.Time
@@ -94,6 +128,81 @@ func (r *run) Exit(status core.RunStatus) {
 	r.exitedOn = &now
 	r.modifiedOn = now
 }
+
+// Fail fails this run with the given error text, unless its flow has an error node in which case the error is
+// logged and the run will continue from that node
+func (r *run) Fail(text string, log events.EventLogger) {
+	if r.catch(text) {
+		log(events.NewError(text, events.ErrorCodeRunFailureHandled))
+		return
+	}
+
+	if text != "" {
+		log(events.NewFailure(text))
+	}
+
+	r.Exit(core.RunStatusFailed)
+	log(events.NewRunEnded(r.UUID(), r.FlowReference(), core.RunStatusFailed))
+}
+
+// tries to catch an error by sending this run to the error node of its flow - which isn't possible if the flow
+// doesn't have one, or if the error occurred at that node
+func (r *run) catch(text string) bool {
+	if r.flow == nil || r.flow.ErrorNode() == "" || text == "" {
+		return false
+	}
+
+	_, node, _ := r.PathLocation()
+	if node == nil || node.UUID() == r.flow.ErrorNode() {
+		return false
+	}
+
+	r.error = &runError{Text: text, NodeUUID: node.UUID()}
+	r.forks = nil // pending branches are abandoned
+	r.catching = true
+	return true
+}
+
+// binds the given parameter values to this run, using defaults for any declared parameters without values
+func (r *run) bindParams(values flows.ParamValues) {
+	r.params = make(flows.ParamValues, len(values))
+	for name, value := range values {
+		r.params[name] = value
+	}
+
+	for _, p := range r.flow.Params() {
+		if r.params[p.Name] == "" && p.Default != "" {
+			r.params[p.Name] = p.Default
+		}
+	}
+}
+
+// collects the declared return values of this run from its locals, using defaults for any without values
+func (r *run) collectReturns(log events.EventLogger) {
+	if r.flow == nil || len(r.flow.Returns()) == 0 {
+		return
+	}
+
+	env := r.session.MergedEnvironment()
+	r.returns = make(flows.ParamValues, len(r.flow.Returns()))
+
+	for _, p := range r.flow.Returns() {
+		value := r.locals.Get(p.Name)
+		if value == "" {
+			value = p.Default
+		}
+
+		normalized, err := p.Normalize(env, value)
+		if err != nil {
+			log(events.NewError(fmt.Sprintf("Invalid return value '%s': %s", p.Name, err), ""))
+			continue
+		}
+		if normalized != "" {
+			r.returns[p.Name] = normalized
+		}
+	}
+}
+
 func (r *run) Status() core.RunStatus { return r.status }
 func (r *run) setStatus(status core.RunStatus) {
 	r.status = status
@@ -140,6 +249,44 @@ func (r *run) recordInput() {
 	r.hadInput = true
 }
 
+// starts branches for the given fork node, returning false if the maximum depth of nested forks has been reached
+func (r *run) startFork(node core.NodeUUID, pending []flows.ExitUUID) bool {
+	if len(r.forks) >= flows.MaxForkDepth {
+		return false
+	}
+
+	r.forks = append(r.forks, &fork{NodeUUID: node, Pending: pending})
+	return true
+}
+
+// takes the next pending branch of the current fork, or if there are none left or they aren't needed, ends that fork
+func (r *run) nextBranch(all bool) flows.Exit {
+	if len(r.forks) == 0 {
+		return nil
+	}
+
+	f := r.forks[len(r.forks)-1]
+
+	if all && r.flow != nil {
+		if node := r.flow.GetNode(f.NodeUUID); node != nil {
+			for len(f.Pending) > 0 {
+				exitUUID := f.Pending[0]
+				f.Pending = f.Pending[1:]
+
+				// skip over branches which no longer exist or have nowhere to go
+				for _, exit := range node.Exits() {
+					if exit.UUID() == exitUUID && exit.DestinationUUID() != "" {
+						return exit
+					}
+				}
+			}
+		}
+	}
+
+	r.forks = r.forks[:len(r.forks)-1]
+	return nil
+}
+
 func (r *run) Path() []flows.Step { return r.path }
 func (r *run) CreateStep(node flows.Node) flows.Step {
 	now := dates.Now()
@@ -185,6 +332,8 @@ func (r *run) ExitedOn() *time.Time  { return r.exitedOn }
 //	parent:related_run -> the parent of the run
 //	ticket:ticket -> the open ticket for the contact
 //	webhook:webhook -> the result of the last webhook or resthook call
+//	error:error -> the error which sent the run to the error node of its flow
+//	params:any -> the values of the parameters of the current flow
 //	node:node -> the current node
 //	globals:globals -> the global values
 //	trigger:trigger -> the trigger that started this session
@@ -203,8 +352,9 @@ func (r *run) RootContext(env envs.Environment) map[string]types.XValue {
 	}
 
 	var child, parent *relatedRunContext
-	if r.session.findCurrentChild(r) != nil {
-		child = newRelatedRunContext(r.session.findCurrentChild(r))
+	if c := r.session.findCurrentChild(r); c != nil {
+		child = newRelatedRunContext(c)
+		child.returns = c.returns.ToXValue(env, c.declaredParams(false))
 	}
 	if r.Parent() != nil {
 		parent = newRelatedRunContext(r.Parent())
@@ -235,6 +385,8 @@ func (r *run) RootContext(env envs.Environment) map[string]types.XValue {
 		"input":        core.Context(env, r.Session().Input()),
 		"globals":      core.Context(env, r.Session().Assets().Globals()),
 		"webhook":      core.Context(env, r.webhook),
+		"error":        core.Context(env, r.error),
+		"params":       r.params.ToXValue(env, r.declaredParams(true)),
 		"node":         node,
 		"legacy_extra": r.legacyExtra.ToXValue(env),
 	}
@@ -273,6 +425,16 @@ func (r *run) Context(env envs.Environment) map[string]types.XValue {
 	}
 }
 
+// gets the parameters or return values declared by the flow of this run
+func (r *run) declaredParams(params bool) []*flows.Param {
+	if r.flow == nil {
+		return nil
+	} else if params {
+		return r.flow.Params()
+	}
+	return r.flow.Returns()
+}
+
 // returns the context representation of the current node
 //
 //	uuid:text -> the UUID of the node
@@ -306,7 +468,8 @@ func (r *run) nodeContext(env envs.Environment) map[string]types.XValue {
 
 // EvaluateTemplate evaluates the given template in the context of this run
 func (r *run) EvaluateTemplateValue(ctx context.Context, template string, log events.EventLogger) (types.XValue, bool) {
-	root := types.NewXObject(r.RootContext(r.session.MergedEnvironment()))
+	root := r.evaluationRoot(ctx, r.session.MergedEnvironment())
+	ctx = r.withFunctions(ctx)
 
 	value, warnings, err := r.session.Engine().Evaluator().TemplateValue(ctx, r.session.MergedEnvironment(), root, template)
 	if err != nil {
@@ -321,7 +484,8 @@ func (r *run) EvaluateTemplateValue(ctx context.Context, template string, log ev
 
 // EvaluateTemplateText evaluates the given template as text in the context of this run
 func (r *run) EvaluateTemplateText(ctx context.Context, template string, escaping excellent.Escaping, truncate bool, log events.EventLogger) (string, bool) {
-	root := types.NewXObject(r.RootContext(r.session.MergedEnvironment()))
+	root := r.evaluationRoot(ctx, r.session.MergedEnvironment())
+	ctx = r.withFunctions(ctx)
 
 	value, warnings, err := r.session.Engine().Evaluator().Template(ctx, r.session.MergedEnvironment(), root, template, escaping)
 	if err != nil {
@@ -337,6 +501,21 @@ func (r *run) EvaluateTemplateText(ctx context.Context, template string, escapin
 	return value, err == nil
 }
 
+// builds the root context for evaluating a template, which only includes the actual values of secret globals if ctx
+// says they can be revealed
+func (r *run) evaluationRoot(ctx context.Context, env envs.Environment) *types.XObject {
+	root := r.RootContext(env)
+	if core.SecretsRevealed(ctx) {
+		root["globals"] = core.ContextFunc(env, r.session.Assets().Globals().RevealedContext)
+	}
+	return types.NewXObject(root)
+}
+
+// makes the custom functions in the session assets callable in expressions
+func (r *run) withFunctions(ctx context.Context) context.Context {
+	return excellent.WithCustomFunctions(ctx, r.session.Assets().Functions().CustomFunctions())
+}
+
 func (r *run) errorToEvents(err error, log events.EventLogger) {
 	var tplErrs *excellent.TemplateErrors
 	if errors.As(err, &tplErrs) {
@@ -433,6 +612,10 @@ type runEnvelope struct {
 	HadInput   bool                  `json:"had_input,omitzero"`
 	ParentUUID core.RunUUID          `json:"parent_uuid,omitempty" validate:"omitempty,uuid"`
 	Webhook    *flows.WebhookCall    `json:"webhook,omitempty"`
+	Forks      []*fork               `json:"forks,omitempty"       validate:"dive"`
+	Error      *runError             `json:"error,omitempty"`
+	Params     flows.ParamValues     `json:"params,omitempty"`
+	Returns    flows.ParamValues     `json:"returns,omitempty"`
 
 	CreatedOn  time.Time  `json:"created_on"  validate:"required"`
 	ModifiedOn time.Time  `json:"modified_on" validate:"required"`
@@ -456,6 +639,10 @@ func readRun(s *session, data []byte, missing assets.MissingCallback) (*run, err
 		status:     e.Status,
 		hadInput:   e.HadInput,
 		webhook:    e.Webhook,
+		forks:      e.Forks,
+		error:      e.Error,
+		params:     e.Params,
+		returns:    e.Returns,
 		createdOn:  e.CreatedOn,
 		modifiedOn: e.ModifiedOn,
 		exitedOn:   e.ExitedOn,
@@ -505,6 +692,10 @@ func (r *run) MarshalJSON() ([]byte, error) {
 		Results:    r.results,
 		Status:     r.status,
 		HadInput:   r.hadInput,
+		Forks:      r.forks,
+		Error:      r.error,
+		Params:     r.params,
+		Returns:    r.returns,
 		CreatedOn:  r.createdOn,
 		ModifiedOn: r.modifiedOn,
 		ExitedOn:   r.exitedOn,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/nyaruka/gocommon/jsonx"
@@ -24,6 +25,7 @@ type pushedFlow struct {
 	flow      flows.Flow
 	parentRun flows.Run
 	terminal  bool
+	params    flows.ParamValues
 }
 
 type session struct {
@@ -78,8 +80,8 @@ func (s *session) setInput(input flows.Input) {
 
 func (s *session) BatchStart() bool { return s.batchStart }
 
-func (s *session) PushFlow(flow flows.Flow, parentRun flows.Run, terminal bool) {
-	s.pushedFlow = &pushedFlow{flow: flow, parentRun: parentRun, terminal: terminal}
+func (s *session) PushFlow(flow flows.Flow, parentRun flows.Run, terminal bool, params flows.ParamValues) {
+	s.pushedFlow = &pushedFlow{flow: flow, parentRun: parentRun, terminal: terminal, params: params}
 }
 
 func (s *session) Runs() []flows.Run { return s.runs }
@@ -183,7 +185,7 @@ func (s *session) start(ctx context.Context, trigger flows.Trigger, flow flows.F
 		return sprint, err
 	}
 
-	s.PushFlow(flow, nil, false)
+	s.PushFlow(flow, nil, false, nil)
 
 	// if trigger provides input, set it
 	s.setInput(s.trigger.Input(s.assets))
@@ -284,7 +286,7 @@ func (s *session) tryToResume(ctx context.Context, sprint *sprint, waitingRun *r
 	}
 
 	// check that the wait accepts this resume - not a permanent error - caller can retry with different resume
-	if !node.Router().Wait().Accepts(resume) {
+	if !node.Router().Wait().Accepts(waitingRun, resume) {
 		return newError(ErrorResumeRejectedByWait, "resume of type %s not accepted by wait of type %s", resume.Type(), node.Router().Wait().Type())
 	}
 
@@ -380,6 +382,7 @@ func (s *session) continueUntilWait(ctx context.Context, sprint *sprint, current
 				// create a new run for it
 				flow := s.pushedFlow.flow
 				currentRun = newRun(s, s.pushedFlow.flow, currentRun)
+				currentRun.bindParams(s.pushedFlow.params)
 				s.addRun(currentRun)
 				sprint.logEvent(events.NewRunStarted(currentRun.FlowReference(), currentRun.UUID(), parentRunUUID(currentRun), s.pushedFlow.terminal))
 				sprint.logFlow(flow)
@@ -393,6 +396,11 @@ func (s *session) continueUntilWait(ctx context.Context, sprint *sprint, current
 
 				s.pushedFlow = nil // clear the trigger
 			}
+		} else if currentRun.catching {
+			// an error has been caught so go to the error node of the flow
+			destination = currentRun.Flow().ErrorNode()
+			currentRun.catching = false
+			exit, operand = nil, ""
 		} else if exit != nil {
 			// if we're at an exit, use its destination
 			destination = exit.DestinationUUID()
@@ -401,7 +409,7 @@ func (s *session) continueUntilWait(ctx context.Context, sprint *sprint, current
 			if destination != "" {
 				destNode := currentRun.Flow().GetNode(destination)
 				if destNode != nil {
-					sprint.logSegment(currentRun.Flow(), node, exit, operand, destNode)
+					sprint.logSegment(currentRun.Flow(), exitOrigin(currentRun.Flow(), node, exit), exit, operand, destNode)
 				}
 			}
 
@@ -411,9 +419,19 @@ func (s *session) continueUntilWait(ctx context.Context, sprint *sprint, current
 			destination = ""
 		}
 
+		// if we're in a branch which ended without reaching its join, move on to the next pending branch
+		for destination == "" && s.pushedFlow == nil && currentRun.Status() == core.RunStatusActive && len(currentRun.forks) > 0 {
+			if exit = currentRun.nextBranch(true); exit != nil {
+				destination = exit.DestinationUUID()
+				sprint.logSegment(currentRun.Flow(), exitOrigin(currentRun.Flow(), node, exit), exit, "", currentRun.Flow().GetNode(destination))
+				exit = nil
+			}
+		}
+
 		// if we have no destination then we're done with the current run which may have completed, expired or errored
 		if destination == "" {
 			if currentRun.ExitedOn() == nil {
+				currentRun.collectReturns(sprint.logEvent)
 				currentRun.Exit(core.RunStatusCompleted)
 				sprint.logEvent(events.NewRunEnded(currentRun.UUID(), currentRun.FlowReference(), core.RunStatusCompleted))
 			}
@@ -505,7 +523,7 @@ func (s *session) visitNode(ctx context.Context, sprint *sprint, r *run, node fl
 			}
 
 			// check if this action has errored the run
-			if r.Status() == core.RunStatusFailed {
+			if r.Status() == core.RunStatusFailed || r.catching {
 				return step, nil, "", nil
 			}
 		}
@@ -546,6 +564,13 @@ func (s *session) pickNodeExit(ctx context.Context, sprint *sprint, r *run, node
 	var err error
 
 	if node.Router() != nil {
+		// a join sends the run back to the next pending branch of its fork until all necessary branches are done
+		if join, isJoin := node.Router().(flows.JoinRouter); isJoin {
+			if exit := r.nextBranch(!join.JoinsAny()); exit != nil {
+				return exit, "", nil
+			}
+		}
+
 		if isTimeout {
 			exitUUID, err = node.Router().RouteTimeout(ctx, r, step, logEvent)
 		} else {
@@ -557,9 +582,17 @@ func (s *session) pickNodeExit(ctx context.Context, sprint *sprint, r *run, node
 		}
 		// router didn't error.. but it failed to pick a category
 		if exitUUID == "" {
-			failRun(sprint, r, step, "Router failed to pick a category")
+			r.Fail("Router failed to pick a category", logEvent)
 			return nil, "", nil
 		}
+
+		// a fork takes its first branch and records the others to be taken when that one reaches the join
+		if fork, isFork := node.Router().(flows.ForkRouter); isFork {
+			if !r.startFork(node.UUID(), fork.BranchExits()[1:]) {
+				r.Fail(fmt.Sprintf("Reached maximum depth of nested forks (%d)", flows.MaxForkDepth), logEvent)
+				return nil, "", nil
+			}
+		}
 	} else if len(node.Exits()) > 0 {
 		// no router, pick our first exit if we have one
 		exitUUID = node.Exits()[0].UUID()
@@ -575,6 +608,20 @@ func (s *session) pickNodeExit(ctx context.Context, sprint *sprint, r *run, node
 	return nil, "", nil // no where to go in the flow...
 }
 
+// finds the node which the given exit belongs to - usually the current node, except when a join or the end of a branch
+// sends the run back to a fork
+func exitOrigin(flow flows.Flow, node flows.Node, exit flows.Exit) flows.Node {
+	if node != nil && slices.Contains(node.Exits(), exit) {
+		return node
+	}
+	for _, n := range flow.Nodes() {
+		if _, isFork := n.Router().(flows.ForkRouter); isFork && slices.Contains(n.Exits(), exit) {
+			return n
+		}
+	}
+	return node
+}
+
 // ensures that our session contact is in the correct query based groups as as far as the engine is concerned
 func (s *session) ensureQueryBasedGroups(logEvent events.EventLogger) {
 	if s.contact == nil {
@@ -44,7 +44,8 @@ var _ flows.RunSummary = (*runSummary)(nil)
 
 // wrapper for a run summary (concrete like runSummary or view of child run via interface)
 type relatedRunContext struct {
-	run flows.RunSummary
+	run     flows.RunSummary
+	returns types.XValue
 }
 
 func newRelatedRunContext(r flows.RunSummary) *relatedRunContext {
@@ -61,6 +62,7 @@ func newRelatedRunContext(r flows.RunSummary) *relatedRunContext {
 //	urns:urns -> the URN values of the run's contact
 //	results:any -> the results saved by the run
 //	status:text -> the current status of the run
+//	returns:any -> the values returned by the run (only for @child)
 //
 // @context related_run
 func (c *relatedRunContext) Context(env envs.Environment) map[string]types.XValue {
@@ -82,6 +84,7 @@ func (c *relatedRunContext) Context(env envs.Environment) map[string]types.XValu
 		"fields":      fields,
 		"results":     core.Context(env, c.run.Results()),
 		"status":      types.NewXText(string(c.run.Status())),
+		"returns":     c.returns,
 
 		// deprecated but used by a lot of flows for @child.run.status as that is what editor has
 		// been using for subflow splits
@@ -80,3 +80,18 @@ func (e *sessionEnvironment) DefaultLocale() i18n.Locale {
 func (e *sessionEnvironment) LLMPrompt(name string) *template.Template {
 	return e.session.Engine().Options().LLMPrompts[name]
 }
+
+// SendWindows returns the send windows which apply to the given run, i.e. those of its flow if it has any, otherwise
+// those of the session environment
+func SendWindows(run Run) envs.SendWindows {
+	if windows := run.Flow().SendWindows(); len(windows) > 0 {
+		return windows
+	}
+	return run.Session().Environment().SendWindows()
+}
+
+// NextSendTime returns the given time if it's within the send windows which apply to the given run, otherwise the time
+// when the next window opens. Windows are evaluated in the contact's timezone.
+func NextSendTime(run Run, t time.Time) time.Time {
+	return SendWindows(run).NextOpen(t, run.Session().MergedEnvironment().Timezone())
+}
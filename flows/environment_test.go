@@ -11,6 +11,7 @@ import (
 	"github.com/nyaruka/goflow/envs"
 	"github.com/nyaruka/goflow/flows/engine"
 	"github.com/nyaruka/goflow/flows/triggers"
+	"github.com/nyaruka/goflow/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -91,6 +92,9 @@ func TestLocationResolver(t *testing.T) {
 	matches := resolver.FindLocationsFuzzy(env, "gisozi town", core.LocationLevelWard, nil)
 	assert.Equal(t, 1, len(matches))
 	assert.Equal(t, "Gisozi", matches[0].Name())
+
+	matches = resolver.FindLocationsByPoint(env, utils.GeoPoint{Lat: -1.93, Lng: 30.1}, core.LocationLevelDistrict, nil)
+	assert.Equal(t, 0, len(matches)) // no boundaries
 }
 
 const contactJSON = `{
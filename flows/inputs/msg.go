@@ -59,16 +59,19 @@ func NewMsg(sa flows.SessionAssets, evt *events.MsgReceived) *Msg {
 //	channel:channel -> the channel that the input was received on
 //	urn:text -> the contact URN that the input was received on
 //	text:text -> the text part of the input
-//	attachments:[]text -> any attachments on the input
+//	attachments:[]attachment -> any attachments on the input
 //	external_id:text -> the external ID of the input
-//	payload:any -> any structured data on the input, e.g. a form submission
+//	payload:any -> any structured data on the input, e.g. a form submission or the payload of a tapped button
 //
 // @context input
 func (i *Msg) Context(env envs.Environment) map[string]types.XValue {
 	attachments := make([]types.XValue, len(i.attachments))
 
 	for i, attachment := range i.attachments {
-		attachments[i] = types.NewXText(string(attachment))
+		// attachments were previously text so they're still serialized as text
+		obj := types.NewXObject(attachmentContext(attachment))
+		obj.SetMarshalAsDefault(true)
+		attachments[i] = obj
 	}
 
 	var urn types.XValue
@@ -95,6 +98,33 @@ func (i *Msg) Context(env envs.Environment) map[string]types.XValue {
 	}
 }
 
+// attachmentContext returns the properties of an attachment available in expressions
+//
+//	__default__:text -> the content type and URL of the attachment
+//	content_type:text -> the content type of the attachment, e.g. image/jpeg
+//	url:text -> the URL of the attachment
+//	size:number -> the size in bytes of the attachment if known
+//	duration:number -> the duration in seconds of an audio or video attachment if known
+//
+// @context attachment
+func attachmentContext(a utils.Attachment) map[string]types.XValue {
+	var size, duration types.XValue
+	if a.Size() > 0 {
+		size = types.NewXNumberFromInt(a.Size())
+	}
+	if a.Duration() > 0 {
+		duration = types.NewXNumberFromInt(a.Duration())
+	}
+
+	return map[string]types.XValue{
+		"__default__":  types.NewXText(string(a)),
+		"content_type": types.NewXText(a.ContentType()),
+		"url":          types.NewXText(a.URL()),
+		"size":         size,
+		"duration":     duration,
+	}
+}
+
 func (i *Msg) format() string {
 	var parts []string
 	if i.text != "" {
@@ -31,7 +31,7 @@ func TestMsgInput(t *testing.T) {
 		"Hi there!",
 		[]utils.Attachment{
 			"image/jpg:http://example.com/test.jpg",
-			"video/mp4:http://example.com/test.mp4",
+			"video/mp4;size=20480;duration=15:http://example.com/test.mp4",
 		},
 		"ext12345",
 		json.RawMessage(`{"service": "checkup", "count": 2}`),
@@ -52,7 +52,22 @@ func TestMsgInput(t *testing.T) {
 		"created_on":  types.NewXDateTime(input.CreatedOn()),
 		"urn":         types.NewXText("tel:+1234567890"),
 		"text":        types.NewXText("Hi there!"),
-		"attachments": types.NewXArray(types.NewXText("image/jpg:http://example.com/test.jpg"), types.NewXText("video/mp4:http://example.com/test.mp4")),
+		"attachments": types.NewXArray(
+			types.NewXObject(map[string]types.XValue{
+				"__default__":  types.NewXText("image/jpg:http://example.com/test.jpg"),
+				"content_type": types.NewXText("image/jpg"),
+				"url":          types.NewXText("http://example.com/test.jpg"),
+				"size":         nil,
+				"duration":     nil,
+			}),
+			types.NewXObject(map[string]types.XValue{
+				"__default__":  types.NewXText("video/mp4;size=20480;duration=15:http://example.com/test.mp4"),
+				"content_type": types.NewXText("video/mp4"),
+				"url":          types.NewXText("http://example.com/test.mp4"),
+				"size":         types.NewXNumberFromInt(20480),
+				"duration":     types.NewXNumberFromInt(15),
+			}),
+		),
 		"external_id": types.NewXText("ext12345"),
 		"payload": types.NewXObject(map[string]types.XValue{
 			"service": types.NewXText("checkup"),
@@ -77,5 +92,19 @@ func TestMsgInput(t *testing.T) {
 	// check marshaling to JSON
 	marshaled, err := jsonx.Marshal(input)
 	assert.NoError(t, err)
-	assert.Equal(t, `{"type":"msg","uuid":"01969b47-76cb-76f8-89aa-1577771fa183","channel":{"uuid":"57f1078f-88aa-46f4-a59a-948a5739c03d","name":"My Android Phone"},"created_on":"2025-05-04T12:31:15.123456789Z","urn":"tel:+1234567890","text":"Hi there!","attachments":["image/jpg:http://example.com/test.jpg","video/mp4:http://example.com/test.mp4"],"external_id":"ext12345","payload":{"service":"checkup","count":2}}`, string(marshaled))
+	assert.Equal(t, `{"type":"msg","uuid":"01969b47-76cb-76f8-89aa-1577771fa183","channel":{"uuid":"57f1078f-88aa-46f4-a59a-948a5739c03d","name":"My Android Phone"},"created_on":"2025-05-04T12:31:15.123456789Z","urn":"tel:+1234567890","text":"Hi there!","attachments":["image/jpg:http://example.com/test.jpg","video/mp4;size=20480;duration=15:http://example.com/test.mp4"],"external_id":"ext12345","payload":{"service":"checkup","count":2}}`, string(marshaled))
+
+	// a reply from a button or list row has that payload which can be routed on instead of its localized text
+	buttonEvt := events.NewMsgReceived(core.NewMsgIn(
+		urns.URN("tel:+1234567890"),
+		assets.NewChannelReference("57f1078f-88aa-46f4-a59a-948a5739c03d", "Nexmo"),
+		"Si",
+		nil,
+		"",
+		json.RawMessage(`"start"`),
+	), "")
+	buttonInput := inputs.NewMsg(session.Assets(), buttonEvt)
+	buttonContext, _ := core.Context(env, buttonInput).(*types.XObject)
+	payload, _ = buttonContext.Get("payload")
+	test.AssertXEqual(t, types.NewXText("start"), payload)
 }
@@ -0,0 +1,280 @@
+package inspect
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nyaruka/gocommon/uuids"
+	"github.com/nyaruka/goflow/assets"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/actions"
+)
+
+// max length of action text shown in node labels
+const graphTextMaxLength = 30
+
+// Graph is a diagram of the nodes of one or more flows, with enter_flow actions linked to the flows they enter. It
+// can optionally be annotated with how many times each exit was taken in a set of sessions.
+type Graph struct {
+	flows  []flows.Flow
+	counts map[flows.ExitUUID]int
+}
+
+// NewGraph creates a new graph of the given flows
+func NewGraph(fs ...flows.Flow) *Graph {
+	return &Graph{flows: fs}
+}
+
+// AddSegments annotates the graph with the exits taken in the given segments, e.g. from the sprints of many sessions
+func (g *Graph) AddSegments(segments []flows.Segment) {
+	counts := make(map[flows.ExitUUID]int, len(segments))
+	for _, s := range segments {
+		counts[s.Exit().UUID()]++
+	}
+	g.AddExitCounts(counts)
+}
+
+// AddExitCounts annotates the graph with the given counts of how many times each exit was taken
+func (g *Graph) AddExitCounts(counts map[flows.ExitUUID]int) {
+	if g.counts == nil {
+		g.counts = make(map[flows.ExitUUID]int, len(counts))
+	}
+	for u, c := range counts {
+		g.counts[u] += c
+	}
+}
+
+// DOT renders this graph in the Graphviz DOT language
+func (g *Graph) DOT() string {
+	m := g.build()
+	b := &strings.Builder{}
+
+	b.WriteString("digraph {\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for i, f := range g.flows {
+		fmt.Fprintf(b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(b, "    label=%s;\n", dotQuote(f.Name()))
+		for _, n := range m.nodes {
+			if n.flow == i {
+				fmt.Fprintf(b, "    %s [label=%s];\n", n.id, dotQuote(n.label))
+			}
+		}
+		b.WriteString("  }\n")
+	}
+	for _, n := range m.nodes {
+		if n.flow < 0 {
+			fmt.Fprintf(b, "  %s [label=%s, shape=folder];\n", n.id, dotQuote(n.label))
+		}
+	}
+	for _, e := range m.edges {
+		attrs := make([]string, 0, 2)
+		if label := e.fullLabel(); label != "" {
+			attrs = append(attrs, "label="+dotQuote(label))
+		}
+		if e.subflow {
+			attrs = append(attrs, "style=dashed")
+		}
+
+		if len(attrs) > 0 {
+			fmt.Fprintf(b, "  %s -> %s [%s];\n", e.from, e.to, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(b, "  %s -> %s;\n", e.from, e.to)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders this graph as a Mermaid flowchart
+func (g *Graph) Mermaid() string {
+	m := g.build()
+	b := &strings.Builder{}
+
+	b.WriteString("flowchart TD\n")
+
+	for i, f := range g.flows {
+		fmt.Fprintf(b, "  subgraph flow%d [%s]\n", i, mermaidQuote(f.Name()))
+		for _, n := range m.nodes {
+			if n.flow == i {
+				fmt.Fprintf(b, "    %s[%s]\n", n.id, mermaidQuote(n.label))
+			}
+		}
+		b.WriteString("  end\n")
+	}
+	for _, n := range m.nodes {
+		if n.flow < 0 {
+			fmt.Fprintf(b, "  %s[[%s]]\n", n.id, mermaidQuote(n.label))
+		}
+	}
+	for _, e := range m.edges {
+		arrow := "-->"
+		if e.subflow {
+			arrow = "-.->"
+		}
+
+		if label := e.fullLabel(); label != "" {
+			fmt.Fprintf(b, "  %s %s|%s| %s\n", e.from, arrow, mermaidQuote(label), e.to)
+		} else {
+			fmt.Fprintf(b, "  %s %s %s\n", e.from, arrow, e.to)
+		}
+	}
+
+	return b.String()
+}
+
+type graphNode struct {
+	id    string
+	flow  int // index of the flow or -1 for flows entered which aren't part of this graph
+	label string
+}
+
+type graphEdge struct {
+	from, to string
+	label    string
+	count    int
+	counted  bool
+	subflow  bool
+}
+
+// gets the label of this edge including its count if the graph has been annotated
+func (e *graphEdge) fullLabel() string {
+	if !e.counted {
+		return e.label
+	}
+	if e.label == "" {
+		return fmt.Sprint(e.count)
+	}
+	return fmt.Sprintf("%s (%d)", e.label, e.count)
+}
+
+type graphModel struct {
+	nodes []*graphNode
+	edges []*graphEdge
+}
+
+// builds the nodes and edges of this graph. Nodes are given IDs based on their position so that output is stable
+// and valid in both DOT and Mermaid.
+func (g *Graph) build() *graphModel {
+	m := &graphModel{}
+	nodeIDs := make(map[core.NodeUUID]string)
+	flowIDs := make(map[assets.FlowUUID]string)
+
+	for i, f := range g.flows {
+		for _, n := range f.Nodes() {
+			nodeIDs[n.UUID()] = fmt.Sprintf("n%d", len(nodeIDs))
+		}
+		if len(f.Nodes()) > 0 {
+			flowIDs[f.UUID()] = nodeIDs[f.Nodes()[0].UUID()]
+		}
+		for _, n := range f.Nodes() {
+			m.nodes = append(m.nodes, &graphNode{id: nodeIDs[n.UUID()], flow: i, label: nodeLabel(n)})
+		}
+	}
+
+	// gets the ID of the first node of an entered flow, adding a node for it if it isn't part of this graph
+	enteredFlowID := func(ref *assets.FlowReference) string {
+		id, exists := flowIDs[ref.UUID]
+		if !exists {
+			id = fmt.Sprintf("f%d", len(flowIDs))
+			flowIDs[ref.UUID] = id
+			m.nodes = append(m.nodes, &graphNode{id: id, flow: -1, label: ref.Name})
+		}
+		return id
+	}
+
+	for _, f := range g.flows {
+		for _, n := range f.Nodes() {
+			from := nodeIDs[n.UUID()]
+
+			for _, a := range n.Actions() {
+				if enter, isEnter := a.(*actions.EnterFlow); isEnter {
+					m.edges = append(m.edges, &graphEdge{from: from, to: enteredFlowID(enter.Flow), subflow: true})
+				}
+			}
+
+			for _, e := range n.Exits() {
+				to, exists := nodeIDs[e.DestinationUUID()]
+				if !exists {
+					continue
+				}
+
+				m.edges = append(m.edges, &graphEdge{from: from, to: to, label: exitLabel(n, e), count: g.counts[e.UUID()], counted: g.counts != nil})
+			}
+		}
+	}
+
+	return m
+}
+
+// gets the label for a node which lists its actions and router
+func nodeLabel(n flows.Node) string {
+	lines := make([]string, 0, len(n.Actions())+2)
+
+	for _, a := range n.Actions() {
+		if text := actionText(a); text != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", a.Type(), text))
+		} else {
+			lines = append(lines, a.Type())
+		}
+	}
+
+	if r := n.Router(); r != nil {
+		if r.Wait() != nil {
+			lines = append(lines, fmt.Sprintf("wait for %s", r.Wait().Type()))
+		}
+		if r.ResultName() != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", r.Type(), r.ResultName()))
+		} else {
+			lines = append(lines, r.Type())
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// gets a short excerpt of the main text of an action, e.g. the text of a message, or the name of an entered flow
+func actionText(a flows.Action) string {
+	if enter, isEnter := a.(*actions.EnterFlow); isEnter {
+		return enter.Flow.Name
+	}
+
+	var text string
+	LocalizableText(a, func(u uuids.UUID, p string, values []string, w func([]string)) {
+		if text == "" && len(values) > 0 {
+			text = values[0]
+		}
+	})
+
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > graphTextMaxLength {
+		text = string(runes[:graphTextMaxLength]) + "…"
+	}
+	return text
+}
+
+// gets the label for an exit which is the names of the categories which use it
+func exitLabel(n flows.Node, e flows.Exit) string {
+	if n.Router() == nil {
+		return ""
+	}
+
+	names := make([]string, 0, 1)
+	for _, c := range n.Router().Categories() {
+		if c.ExitUUID() == e.UUID() && !slices.Contains(names, c.Name()) {
+			names = append(names, c.Name())
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", "<br/>").Replace(s) + `"`
+}
@@ -0,0 +1,48 @@
+package inspect_test
+
+import (
+	"testing"
+
+	"github.com/nyaruka/goflow/flows/definition"
+	"github.com/nyaruka/goflow/flows/inspect"
+	"github.com/nyaruka/goflow/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph(t *testing.T) {
+	sa, session, sprint := test.NewSessionBuilder().
+		WithAssetsPath("../../test/testdata/runner/subflow.json").
+		WithFlow("76f0a02f-3b75-4b86-9064-e9195e1b3a02").
+		MustBuild()
+
+	parent, err := sa.Flows().Get("76f0a02f-3b75-4b86-9064-e9195e1b3a02")
+	require.NoError(t, err)
+	child, err := sa.Flows().Get("a8d27b94-d3d0-4a96-8074-0f162f342195")
+	require.NoError(t, err)
+
+	// entered flow which isn't part of the graph
+	graph := inspect.NewGraph(parent)
+	test.AssertSnapshot(t, "parent_dot", graph.DOT())
+	test.AssertSnapshot(t, "parent_mermaid", graph.Mermaid())
+
+	// entered flow which is part of the graph, annotated with path counts
+	graph = inspect.NewGraph(parent, child)
+	graph.AddSegments(sprint.Segments())
+
+	_, sprint, err = test.ResumeSession(session, sa, "Hello")
+	require.NoError(t, err)
+	graph.AddSegments(sprint.Segments())
+
+	test.AssertSnapshot(t, "all_dot", graph.DOT())
+	test.AssertSnapshot(t, "all_mermaid", graph.Mermaid())
+
+	// an empty flow
+	empty, err := definition.ReadFlow([]byte(`{"uuid": "8ca44c09-791d-453a-9799-a70dd3303306", "name": "Empty", "spec_version": "14.4.2", "language": "eng", "type": "messaging", "nodes": []}`), nil)
+	require.NoError(t, err)
+
+	graph = inspect.NewGraph(empty)
+	graph.AddSegments(nil)
+	assert.Equal(t, "digraph {\n  node [shape=box];\n  subgraph cluster_0 {\n    label=\"Empty\";\n  }\n}\n", graph.DOT())
+	assert.Equal(t, "flowchart TD\n  subgraph flow0 [\"Empty\"]\n  end\n", graph.Mermaid())
+}
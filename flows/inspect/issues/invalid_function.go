@@ -0,0 +1,57 @@
+package issues
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/gocommon/i18n"
+	"github.com/nyaruka/goflow/assets"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/flows"
+)
+
+func init() {
+	registerType(TypeInvalidFunction, InvalidFunctionCheck)
+}
+
+// TypeInvalidFunction is our type for an invalid function issue
+const TypeInvalidFunction string = "invalid_function"
+
+// InvalidFunction is a call to a function whose asset isn't valid and so can't be called
+type InvalidFunction struct {
+	baseIssue
+
+	Function *assets.FunctionReference `json:"function"`
+}
+
+func newInvalidFunction(nodeUUID core.NodeUUID, actionUUID flows.ActionUUID, language i18n.Language, function *assets.FunctionReference, err error) *InvalidFunction {
+	return &InvalidFunction{
+		baseIssue: newBaseIssue(
+			TypeInvalidFunction,
+			nodeUUID,
+			actionUUID,
+			language,
+			fmt.Sprintf("function '%s' isn't valid: %s", function.Name, err),
+		),
+		Function: function,
+	}
+}
+
+// InvalidFunctionCheck checks for calls to functions whose assets couldn't be loaded because they aren't valid
+func InvalidFunctionCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, report func(flows.Issue)) {
+	// skip check if we don't have assets
+	if sa == nil {
+		return
+	}
+
+	for _, ref := range refs {
+		if functionRef, isFunction := ref.Reference.(*assets.FunctionReference); isFunction {
+			if err := sa.Functions().Invalid(functionRef.Name); err != nil {
+				var actionUUID flows.ActionUUID
+				if ref.Action != nil {
+					actionUUID = ref.Action.UUID()
+				}
+				report(newInvalidFunction(ref.Node.UUID(), actionUUID, ref.Language, functionRef, err))
+			}
+		}
+	}
+}
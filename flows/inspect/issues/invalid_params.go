@@ -0,0 +1,66 @@
+package issues
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/nyaruka/gocommon/i18n"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/actions"
+)
+
+func init() {
+	registerType(TypeInvalidParams, InvalidParamsCheck)
+}
+
+// TypeInvalidParams is our type for an invalid params issue
+const TypeInvalidParams string = "invalid_params"
+
+// InvalidParams is an enter flow action whose params don't match those declared by the subflow
+type InvalidParams struct {
+	baseIssue
+
+	Param string `json:"param"`
+}
+
+func newInvalidParams(nodeUUID core.NodeUUID, actionUUID flows.ActionUUID, param, description string) *InvalidParams {
+	return &InvalidParams{
+		baseIssue: newBaseIssue(TypeInvalidParams, nodeUUID, actionUUID, i18n.NilLanguage, description),
+		Param:     param,
+	}
+}
+
+// InvalidParamsCheck checks for enter flow actions which pass undeclared params or omit required ones
+func InvalidParamsCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, report func(flows.Issue)) {
+	// skip check if we don't have assets
+	if sa == nil {
+		return
+	}
+
+	for _, node := range flow.Nodes() {
+		for _, a := range node.Actions() {
+			if a.Type() != actions.TypeEnterFlow {
+				continue
+			}
+
+			action := a.(*actions.EnterFlow)
+			subflow, err := sa.Flows().Get(action.Flow.UUID)
+			if err != nil {
+				continue // reported as a missing dependency
+			}
+
+			for _, name := range slices.Sorted(maps.Keys(action.Params)) {
+				if flows.FindParam(subflow.Params(), name) == nil {
+					report(newInvalidParams(node.UUID(), a.UUID(), name, fmt.Sprintf("parameter '%s' isn't declared by flow '%s'", name, subflow.Name())))
+				}
+			}
+			for _, p := range subflow.Params() {
+				if _, provided := action.Params[p.Name]; p.Required && !provided {
+					report(newInvalidParams(node.UUID(), a.UUID(), p.Name, fmt.Sprintf("required parameter '%s' of flow '%s' isn't provided", p.Name, subflow.Name())))
+				}
+			}
+		}
+	}
+}
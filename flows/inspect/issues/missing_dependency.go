@@ -45,6 +45,11 @@ func MissingDependencyCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flow
 	}
 
 	for _, ref := range refs {
+		// functions which exist but aren't valid are reported as invalid functions instead
+		if functionRef, isFunction := ref.Reference.(*assets.FunctionReference); isFunction && sa.Functions().Invalid(functionRef.Name) != nil {
+			continue
+		}
+
 		if !inspect.CheckReference(sa, ref.Reference) {
 			var actionUUID flows.ActionUUID
 			if ref.Action != nil {
@@ -0,0 +1,84 @@
+package issues
+
+import (
+	"fmt"
+
+	"github.com/nyaruka/gocommon/i18n"
+	"github.com/nyaruka/gocommon/uuids"
+	"github.com/nyaruka/goflow/assets"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/actions"
+	"github.com/nyaruka/goflow/flows/inspect"
+)
+
+func init() {
+	registerType(TypeSecretGlobal, SecretGlobalCheck)
+}
+
+// TypeSecretGlobal is our type for a secret global issue
+const TypeSecretGlobal string = "secret_global"
+
+// SecretGlobal is a use of a secret global in the text of a message, where its value will be masked
+type SecretGlobal struct {
+	baseIssue
+
+	Global *assets.GlobalReference `json:"global"`
+}
+
+func newSecretGlobal(nodeUUID core.NodeUUID, actionUUID flows.ActionUUID, language i18n.Language, global *assets.GlobalReference) *SecretGlobal {
+	return &SecretGlobal{
+		baseIssue: newBaseIssue(
+			TypeSecretGlobal,
+			nodeUUID,
+			actionUUID,
+			language,
+			fmt.Sprintf("secret global '%s' can't be used in a message", global.Key),
+		),
+		Global: global,
+	}
+}
+
+// SecretGlobalCheck checks for secret globals used in the text of messages
+func SecretGlobalCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, report func(flows.Issue)) {
+	// skip check if we don't have assets
+	if sa == nil {
+		return
+	}
+
+	checkTemplate := func(n flows.Node, a flows.Action, l i18n.Language, t string) {
+		assetRefs, _ := inspect.ExtractFromTemplate(t)
+		for _, ref := range assetRefs {
+			if globalRef, isGlobal := ref.(*assets.GlobalReference); isGlobal {
+				if global := sa.Globals().Get(globalRef.Key); global != nil && global.Secret() {
+					report(newSecretGlobal(n.UUID(), a.UUID(), l, global.Reference()))
+				}
+			}
+		}
+	}
+
+	for _, node := range flow.Nodes() {
+		for _, a := range node.Actions() {
+			var text string
+
+			switch typed := a.(type) {
+			case *actions.SendMsg:
+				text = typed.Text
+			case *actions.SendBroadcast:
+				text = typed.Text
+			case *actions.SayMsg:
+				text = typed.Text
+			case *actions.ScheduleMsg:
+				text = typed.Text
+			default:
+				continue
+			}
+
+			checkTemplate(node, a, i18n.NilLanguage, text)
+
+			inspect.Translations(flow.Localization(), uuids.UUID(a.UUID()), "text", func(l i18n.Language, t string) {
+				checkTemplate(node, a, l, t)
+			})
+		}
+	}
+}
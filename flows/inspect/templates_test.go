@@ -125,6 +125,16 @@ func TestExtractFromTemplate(t *testing.T) {
 			},
 			[]string{"state"},
 		},
+		{
+			`Your ID is @(National_ID(fields.id)) @(upper(format_id(fields.id)))`,
+			[]assets.Reference{
+				assets.NewFieldReference("id", ""),
+				assets.NewFieldReference("id", ""),
+				assets.NewFunctionReference("national_id"),
+				assets.NewFunctionReference("format_id"),
+			},
+			[]string{},
+		},
 	}
 
 	for _, tc := range testCases {
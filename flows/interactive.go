@@ -0,0 +1,106 @@
+package flows
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nyaruka/gocommon/uuids"
+)
+
+// possible types of message buttons
+const (
+	ButtonTypeReply = "reply"
+	ButtonTypeURL   = "url"
+)
+
+// Button is an interactive button on a message. A reply button has a payload which is sent back as the payload of
+// the contact's reply, which unlike its text, doesn't vary by language. A url button opens its URL.
+type Button struct {
+	UUID    uuids.UUID `json:"uuid"              validate:"required,uuid"`
+	Type    string     `json:"type"              validate:"required,eq=reply|eq=url"`
+	Text    string     `json:"text"              validate:"required,max=1000" engine:"localized,evaluated"`
+	Payload string     `json:"payload,omitempty" validate:"max=1000"          engine:"evaluated"`
+	URL     string     `json:"url,omitempty"     validate:"max=2048"          engine:"localized,evaluated"`
+}
+
+// NewReplyButton creates a new reply button
+func NewReplyButton(uuid uuids.UUID, text, payload string) *Button {
+	return &Button{UUID: uuid, Type: ButtonTypeReply, Text: text, Payload: payload}
+}
+
+// NewURLButton creates a new call-to-action URL button
+func NewURLButton(uuid uuids.UUID, text, url string) *Button {
+	return &Button{UUID: uuid, Type: ButtonTypeURL, Text: text, URL: url}
+}
+
+// LocalizationUUID gets the UUID which identifies this object for localization
+func (b *Button) LocalizationUUID() uuids.UUID { return b.UUID }
+
+// Validate validates that this button has the fields its type requires
+func (b *Button) Validate() error {
+	if b.Type == ButtonTypeURL && b.URL == "" {
+		return errors.New("url buttons must have a url")
+	}
+	if b.Type == ButtonTypeReply && b.URL != "" {
+		return errors.New("reply buttons can't have a url")
+	}
+	return nil
+}
+
+// List is an interactive list on a message which is opened by a button and lets the contact pick a row
+type List struct {
+	UUID     uuids.UUID     `json:"uuid"     validate:"required,uuid"`
+	Button   string         `json:"button"   validate:"required,max=1000" engine:"localized,evaluated"`
+	Sections []*ListSection `json:"sections" validate:"required,min=1,max=10,dive"`
+}
+
+// NewList creates a new interactive list
+func NewList(uuid uuids.UUID, button string, sections []*ListSection) *List {
+	return &List{UUID: uuid, Button: button, Sections: sections}
+}
+
+// LocalizationUUID gets the UUID which identifies this object for localization
+func (l *List) LocalizationUUID() uuids.UUID { return l.UUID }
+
+// Validate validates that this list doesn't have more rows than can be sent
+func (l *List) Validate() error {
+	numRows := 0
+	for _, s := range l.Sections {
+		numRows += len(s.Rows)
+	}
+	if numRows > MaxValuesPerProperty {
+		return fmt.Errorf("list can't have more than %d rows (has %d)", MaxValuesPerProperty, numRows)
+	}
+	return nil
+}
+
+// ListSection is an optionally titled section of rows in an interactive list
+type ListSection struct {
+	UUID  uuids.UUID `json:"uuid"            validate:"required,uuid"`
+	Title string     `json:"title,omitempty" validate:"max=1000" engine:"localized,evaluated"`
+	Rows  []*ListRow `json:"rows"            validate:"required,min=1,max=10,dive"`
+}
+
+// NewListSection creates a new interactive list section
+func NewListSection(uuid uuids.UUID, title string, rows []*ListRow) *ListSection {
+	return &ListSection{UUID: uuid, Title: title, Rows: rows}
+}
+
+// LocalizationUUID gets the UUID which identifies this object for localization
+func (s *ListSection) LocalizationUUID() uuids.UUID { return s.UUID }
+
+// ListRow is a row in an interactive list which, like a reply button, sends its payload back when picked
+type ListRow struct {
+	UUID        uuids.UUID `json:"uuid"                  validate:"required,uuid"`
+	Title       string     `json:"title"                 validate:"required,max=1000" engine:"localized,evaluated"`
+	Description string     `json:"description,omitempty" validate:"max=1000"          engine:"localized,evaluated"`
+	Payload     string     `json:"payload,omitempty"     validate:"max=1000"          engine:"evaluated"`
+}
+
+// NewListRow creates a new interactive list row
+func NewListRow(uuid uuids.UUID, title, description, payload string) *ListRow {
+	return &ListRow{UUID: uuid, Title: title, Description: description, Payload: payload}
+}
+
+// LocalizationUUID gets the UUID which identifies this object for localization
+func (r *ListRow) LocalizationUUID() uuids.UUID { return r.UUID }
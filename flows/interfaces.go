@@ -27,6 +27,8 @@ const (
 	MaxCategoriesPerRouter = 100    // max number of categories a router can have
 	MaxCasesPerRouter      = 100    // max number of categories a switch router can have
 	MaxArgumentsPerCase    = 10     // max number of test arguments a switch router case can have
+	MaxForkDepth           = 10     // max number of forks a run can be inside of at once
+	MaxParamsPerFlow       = 20     // max number of parameters or return values a flow can declare
 	MaxUIBytes             = 262144 // max size in bytes of a flow's _ui section
 
 	// localizable items are actions, cases and categories, so the limits above give us the max possible per language
@@ -83,10 +85,14 @@ type Flow interface {
 	Language() i18n.Language
 	Type() FlowType
 	ExpireAfter() time.Duration
+	SendWindows() envs.SendWindows
+	Params() []*Param
+	Returns() []*Param
 	Localization() Localization
 	UI() json.RawMessage
 	Nodes() []Node
 	GetNode(uuid core.NodeUUID) Node
+	ErrorNode() core.NodeUUID
 
 	Asset() assets.Flow
 	Reference(bool) *assets.FlowReference
@@ -144,11 +150,27 @@ type Router interface {
 	RouteTimeout(context.Context, Run, Step, events.EventLogger) (ExitUUID, error)
 
 	Validate(Flow, []Exit) error
-	Inspect(func(*ResultInfo), func(assets.Reference))
+	Inspect(func(*ResultInfo), func(assets.Reference), func(string))
 	EnumerateTemplates(Localization, func(i18n.Language, string))
 	EnumerateLocalizables(func(uuids.UUID, string, []string, func([]string)))
 }
 
+// ForkRouter is a router which starts a branch for each of its exits. The engine takes the first branch and takes each
+// of the others in turn when the previous one reaches a JoinRouter or ends.
+type ForkRouter interface {
+	Router
+
+	BranchExits() []ExitUUID
+}
+
+// JoinRouter is a router which ends the branches started by the most recent ForkRouter, and only routes once all
+// branches, or just the first if JoinsAny is true, have reached it.
+type JoinRouter interface {
+	Router
+
+	JoinsAny() bool
+}
+
 // Exit is a route out of a node and optionally to another node
 type Exit interface {
 	UUID() ExitUUID
@@ -170,7 +192,7 @@ type Wait interface {
 	Timeout() Timeout
 
 	Begin(context.Context, Run, events.EventLogger) bool
-	Accepts(Resume) bool
+	Accepts(Run, Resume) bool
 }
 
 // Localization provide a way to get the translations for a specific language
@@ -23,7 +23,9 @@ func init() {
 const TypeField string = "field"
 
 // Field modifies a field value on the contact, creating a contact_field_changed event if the value
-// changed. Text values are truncated to the engine's max field length.
+// changed. Text values are truncated to the engine's max field length. Values are normalized according
+// to the rules of the field, and values which aren't valid for the type or rules of the field, e.g. a
+// value which isn't one of the choices of a choice field, are skipped with an error event.
 type Field struct {
 	baseModifier
 
@@ -42,9 +44,15 @@ func NewField(field *core.Field, value string) *Field {
 
 // Apply applies this modification to the given contact
 func (m *Field) Apply(ctx context.Context, eng flows.Engine, env envs.Environment, sa flows.SessionAssets, contact *core.Contact, log events.EventLogger) (bool, error) {
+	value, err := m.field.Normalize(env, m.value)
+	if err != nil {
+		log(events.NewError(err.Error(), events.ErrorCodeFieldValueInvalid, "field", m.field.Key()))
+		return false, nil
+	}
+
 	oldValue := contact.Fields().Get(m.field)
 
-	newValue := contact.Fields().Parse(env, sa.Fields(), m.field, m.value)
+	newValue := contact.Fields().Parse(env, sa.Fields(), m.field, value)
 
 	// truncate text value if necessary
 	if newValue != nil {
@@ -0,0 +1,104 @@
+package flows
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/nyaruka/goflow/envs"
+	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/utils"
+)
+
+func init() {
+	utils.RegisterValidatorAlias("param_type", "eq=text|eq=number|eq=datetime", func(validator.FieldError) string {
+		return "is not a valid parameter type"
+	})
+}
+
+// ParamType is the type of a flow parameter or return value
+type ParamType string
+
+// possible types of flow parameters and return values
+const (
+	ParamTypeText     ParamType = "text"
+	ParamTypeNumber   ParamType = "number"
+	ParamTypeDatetime ParamType = "datetime"
+)
+
+// Param is a named and typed parameter or return value of a flow. Defaults must be valid for the type, and only
+// parameters can be required.
+type Param struct {
+	Name     string    `json:"name"               validate:"required,local_ref"`
+	Type     ParamType `json:"type"               validate:"required,param_type"`
+	Default  string    `json:"default,omitempty"  validate:"max=1000"`
+	Required bool      `json:"required,omitempty"`
+}
+
+// NewParam creates a new parameter
+func NewParam(name string, type_ ParamType, default_ string, required bool) *Param {
+	return &Param{Name: name, Type: type_, Default: default_, Required: required}
+}
+
+// Convert converts the given value to the type of this parameter, returning nil if the value is empty
+func (p *Param) Convert(env envs.Environment, value string) (types.XValue, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var converted types.XValue = types.NewXText(value)
+	var xerr *types.XError
+
+	switch p.Type {
+	case ParamTypeNumber:
+		converted, xerr = types.ToXNumber(env, converted)
+	case ParamTypeDatetime:
+		converted, xerr = types.ToXDateTime(env, converted)
+	}
+
+	if xerr != nil {
+		return nil, xerr
+	}
+	return converted, nil
+}
+
+// Normalize converts the given value to the type of this parameter and returns it as text
+func (p *Param) Normalize(env envs.Environment, value string) (string, error) {
+	converted, err := p.Convert(env, value)
+	if err != nil {
+		return "", fmt.Errorf("'%s' is not a valid %s", value, p.Type)
+	}
+
+	asText, _ := types.ToXText(env, converted)
+	return asText.Native(), nil
+}
+
+// FindParam finds the parameter with the given name
+func FindParam(params []*Param, name string) *Param {
+	for _, p := range params {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// ParamValues is the set of values bound to the parameters or return values of a run, stored as text
+type ParamValues map[string]string
+
+// ToXValue returns a representation of these values for use in expressions, converted to the types of the given
+// parameters. Values whose parameters are no longer declared are treated as text.
+func (v ParamValues) ToXValue(env envs.Environment, params []*Param) types.XValue {
+	vals := make(map[string]types.XValue, len(v))
+	for name, value := range v {
+		var val types.XValue = types.NewXText(value)
+
+		if p := FindParam(params, name); p != nil {
+			if converted, err := p.Convert(env, value); err == nil {
+				val = converted
+			}
+		}
+
+		vals[name] = val
+	}
+	return types.NewXObject(vals)
+}
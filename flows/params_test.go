@@ -0,0 +1,54 @@
+package flows_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nyaruka/goflow/envs"
+	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/test"
+	"github.com/nyaruka/goflow/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParams(t *testing.T) {
+	env := envs.NewBuilder().WithDateFormat(envs.DateFormatDayMonthYear).Build()
+
+	name := flows.NewParam("name", flows.ParamTypeText, "", true)
+	age := flows.NewParam("age", flows.ParamTypeNumber, "18", false)
+	dob := flows.NewParam("dob", flows.ParamTypeDatetime, "", false)
+	params := []*flows.Param{name, age, dob}
+
+	assert.Equal(t, age, flows.FindParam(params, "age"))
+	assert.Nil(t, flows.FindParam(params, "color"))
+
+	v, err := age.Normalize(env, "23.50")
+	assert.NoError(t, err)
+	assert.Equal(t, "23.5", v)
+
+	v, err = dob.Normalize(env, "2/3/2001")
+	assert.NoError(t, err)
+	assert.Equal(t, "2001-03-02T00:00:00.000000Z", v)
+
+	v, err = name.Normalize(env, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", v)
+
+	_, err = age.Normalize(env, "old")
+	assert.EqualError(t, err, "'old' is not a valid number")
+
+	values := flows.ParamValues{"name": "Bob", "age": "23", "dob": "2001-03-02T00:00:00.000000Z", "color": "red"}
+
+	test.AssertXEqual(t, types.NewXObject(map[string]types.XValue{
+		"name":  types.NewXText("Bob"),
+		"age":   types.NewXNumberFromInt(23),
+		"dob":   types.NewXDateTime(time.Date(2001, 3, 2, 0, 0, 0, 0, time.UTC)),
+		"color": types.NewXText("red"),
+	}), values.ToXValue(env, params))
+}
+
+func TestParamValidation(t *testing.T) {
+	err := utils.Validate(&flows.Param{Name: "1foo", Type: "boolean"})
+	assert.EqualError(t, err, "field 'name' is not a valid local variable reference, field 'type' is not a valid parameter type")
+}
@@ -61,7 +61,7 @@ func (r *baseRouter) AllowTimeout() bool {
 // ResultName returns the name which the result of this router should be saved as (if any)
 func (r *baseRouter) ResultName() string { return r.resultName }
 
-func (r *baseRouter) Inspect(result func(*flows.ResultInfo), dependency func(assets.Reference)) {
+func (r *baseRouter) Inspect(result func(*flows.ResultInfo), dependency func(assets.Reference), local func(string)) {
 	if r.resultName != "" {
 		categoryNames := make([]string, len(r.categories))
 		for i := range r.categories {
@@ -132,6 +132,15 @@ func (r *baseRouter) isValidCategory(uuid flows.CategoryUUID) bool {
 	return false
 }
 
+func (r *baseRouter) findCategory(uuid flows.CategoryUUID) flows.Category {
+	for _, c := range r.categories {
+		if c.UUID() == uuid {
+			return c
+		}
+	}
+	return nil
+}
+
 func (r *baseRouter) isValidExit(uuid flows.ExitUUID, exits []flows.Exit) bool {
 	for _, e := range exits {
 		if e.UUID() == uuid {
@@ -157,14 +166,7 @@ func (r *baseRouter) routeToCategory(run flows.Run, step flows.Step, categoryUUI
 	}
 
 	// find the actual category
-	var category flows.Category
-	for _, c := range r.categories {
-		if c.UUID() == categoryUUID {
-			category = c
-			break
-		}
-	}
-
+	category := r.findCategory(categoryUUID)
 	if category == nil {
 		return "", fmt.Errorf("category %s is not a valid category", categoryUUID)
 	}
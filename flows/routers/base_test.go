@@ -55,6 +55,7 @@ func testRouterType(t *testing.T, assetsJSON []byte, typeName string) {
 		ReadError         string          `json:"read_error,omitempty"`
 		DependenciesError string          `json:"dependencies_error,omitempty"`
 		Results           json.RawMessage `json:"results,omitempty"`
+		Locals            json.RawMessage `json:"locals,omitempty"`
 		Events            json.RawMessage `json:"events,omitempty"`
 		Templates         []string        `json:"templates,omitempty"`
 		LocalizedText     []string        `json:"localizables,omitempty"`
@@ -105,6 +106,9 @@ func testRouterType(t *testing.T, assetsJSON []byte, typeName string) {
 
 		run := session.Runs()[0]
 		actual.Results = jsonx.MustMarshal(run.Results())
+		if tc.Locals != nil {
+			actual.Locals = jsonx.MustMarshal(run.Locals())
+		}
 		actual.Events = jsonx.MustMarshal(sprint.Events()[1 : len(sprint.Events())-1]) // trim initial run_started and final run_ended
 
 		if tc.Templates != nil {
@@ -123,6 +127,9 @@ func testRouterType(t *testing.T, assetsJSON []byte, typeName string) {
 			// check results are what we expected
 			test.AssertEqualJSON(t, tc.Results, actual.Results, "results mismatch in %s", testName)
 
+			// check locals are what we expected
+			test.AssertEqualJSON(t, tc.Locals, actual.Locals, "locals mismatch in %s", testName)
+
 			// check events are what we expected
 			test.AssertEqualJSON(t, tc.Events, actual.Events, "events mismatch in %s", testName)
 
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/nyaruka/gocommon/dates"
 	"github.com/nyaruka/gocommon/i18n"
@@ -16,6 +18,7 @@ import (
 	"github.com/nyaruka/goflow/excellent/functions"
 	"github.com/nyaruka/goflow/excellent/types"
 	"github.com/nyaruka/goflow/utils"
+	"github.com/shopspring/decimal"
 )
 
 //------------------------------------------------------------------------------------------
@@ -30,9 +33,9 @@ func init() {
 		"has_error": functions.OneArgFunction(HasError),
 
 		"has_only_text":   functions.TwoTextFunction(HasOnlyText),
-		"has_phrase":      functions.TwoTextFunction(HasPhrase),
-		"has_only_phrase": functions.TwoTextFunction(HasOnlyPhrase),
-		"has_any_word":    functions.TwoTextFunction(HasAnyWord),
+		"has_phrase":      functions.InitialTextFunction(1, 2, HasPhrase),
+		"has_only_phrase": functions.InitialTextFunction(1, 2, HasOnlyPhrase),
+		"has_any_word":    functions.InitialTextFunction(1, 2, HasAnyWord),
 		"has_all_words":   functions.TwoTextFunction(HasAllWords),
 		"has_beginning":   functions.TwoTextFunction(HasBeginning),
 		"has_text":        functions.OneTextFunction(HasText),
@@ -56,6 +59,15 @@ func init() {
 		"has_email": functions.OneTextFunction(HasEmail),
 		"has_group": functions.MinAndMaxArgsCheck(2, 3, HasGroup),
 
+		"has_payload":       functions.MinAndMaxArgsCheck(1, 2, HasPayload),
+		"has_payload_field": functions.MinAndMaxArgsCheck(2, 3, HasPayloadField),
+
+		"has_attachment":        functions.MinArgsCheck(1, HasAttachment),
+		"has_image":             functions.OneArgFunction(HasImage),
+		"has_video":             functions.OneArgFunction(HasVideo),
+		"has_audio":             functions.OneArgFunction(HasAudio),
+		"has_audio_longer_than": functions.TwoArgFunction(HasAudioLongerThan),
+
 		"has_category":   functions.ObjectAndTextsFunction(HasCategory),
 		"has_intent":     functions.ObjectTextAndNumberFunction(HasIntent),
 		"has_top_intent": functions.ObjectTextAndNumberFunction(HasTopIntent),
@@ -64,6 +76,9 @@ func init() {
 		"has_district": functions.MinAndMaxArgsCheck(1, 2, HasDistrict),
 		"has_ward":     HasWard,
 
+		"has_location_within": functions.NumArgsCheck(4, HasLocationWithin),
+		"has_location_in":     functions.TwoTextFunction(HasLocationIn),
+
 		// for backward compatibility
 		"has_value": functions.OneTextFunction(HasText),
 	}
@@ -188,19 +203,256 @@ func HasGroup(ctx context.Context, env envs.Environment, args ...types.XValue) t
 	return FalseResult
 }
 
+// HasPayload tests whether `payload` isn't empty, and if `value` is provided, whether it equals `value` (case
+// sensitive). This can be used with `@input.payload` to route on the payload of a tapped button or list row, which
+// unlike the text of the reply, doesn't change when the button text is localized.
+//
+//	@(has_payload("start")) -> true
+//	@(has_payload("start").match) -> start
+//	@(has_payload("start", "start")) -> true
+//	@(has_payload("start", "START")) -> false
+//	@(has_payload(parse_json("{\"id\": 12}")).match) -> {id: 12}
+//	@(has_payload(input.payload)) -> false
+//
+// @test has_payload(payload [,value])
+func HasPayload(ctx context.Context, env envs.Environment, args ...types.XValue) types.XValue {
+	payload := args[0]
+	if types.IsXError(payload) {
+		return payload
+	}
+	if isEmptyPayload(payload) {
+		return FalseResult
+	}
+
+	if len(args) > 1 {
+		return matchPayloadValue(env, payload, args[1])
+	}
+
+	return NewTrueResult(payload)
+}
+
+// HasPayloadField tests whether the field at `path` in a structured `payload` isn't empty, and if `value` is provided,
+// whether it equals `value` (case sensitive). The path is a list of property names or array indexes separated by
+// periods.
+//
+//	@(has_payload_field(parse_json("{\"form\": {\"answer\": \"yes\"}}"), "form.answer", "yes")) -> true
+//	@(has_payload_field(parse_json("{\"form\": {\"answer\": \"yes\"}}"), "form.answer").match) -> yes
+//	@(has_payload_field(parse_json("{\"form\": {\"answer\": \"yes\"}}"), "form.answer", "no")) -> false
+//	@(has_payload_field(parse_json("{\"form\": {\"answer\": \"yes\"}}"), "form.question")) -> false
+//	@(has_payload_field(parse_json("{\"items\": [{\"id\": 3}]}"), "items.0.id", "3")) -> true
+//	@(has_payload_field(parse_json("{\"choice\": 0}"), "choice", "0")) -> true
+//	@(has_payload_field(input.payload, "id")) -> false
+//
+// @test has_payload_field(payload, path [,value])
+func HasPayloadField(ctx context.Context, env envs.Environment, args ...types.XValue) types.XValue {
+	if types.IsXError(args[0]) {
+		return args[0]
+	}
+
+	path, xerr := types.ToXText(env, args[1])
+	if xerr != nil {
+		return xerr
+	}
+
+	field := args[0]
+	for _, key := range strings.Split(path.Native(), ".") {
+		switch typed := field.(type) {
+		case *types.XObject:
+			field, _ = typed.Get(key)
+		case *types.XArray:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= typed.Count() {
+				return FalseResult
+			}
+			field = typed.Get(index)
+		default:
+			return FalseResult
+		}
+	}
+
+	if isEmptyPayload(field) {
+		return FalseResult
+	}
+
+	if len(args) > 2 {
+		return matchPayloadValue(env, field, args[2])
+	}
+
+	return NewTrueResult(field)
+}
+
+// checks whether the given payload or payload field is empty, i.e. missing, empty text or an empty object or array,
+// but unlike checking truthiness, values like 0 and false aren't empty
+func isEmptyPayload(payload types.XValue) bool {
+	switch typed := payload.(type) {
+	case nil:
+		return true
+	case *types.XText:
+		return typed.Empty()
+	case *types.XObject:
+		return typed.Count() == 0
+	case *types.XArray:
+		return typed.Count() == 0
+	}
+	return false
+}
+
+// checks whether the text of the given payload or payload field equals the given value
+func matchPayloadValue(env envs.Environment, payload types.XValue, value types.XValue) types.XValue {
+	payloadAsText, xerr := types.ToXText(env, payload)
+	if xerr != nil {
+		return xerr
+	}
+	valueAsText, xerr := types.ToXText(env, value)
+	if xerr != nil {
+		return xerr
+	}
+
+	if payloadAsText.Equals(valueAsText) {
+		return NewTrueResult(payload)
+	}
+
+	return FalseResult
+}
+
+// HasAttachment tests whether `attachments` contains an attachment, and if `types` are provided, whether it contains
+// an attachment matching one of them. A type can be a media type like `image` or a content type like `image/jpeg`.
+// The attachments can be a list such as `@input.attachments` or a single attachment.
+//
+//	@(has_attachment(input.attachments)) -> true
+//	@(has_attachment(input.attachments, "image").match) -> image/jpeg:http://s3.amazon.com/bucket/test.jpg
+//	@(has_attachment(array("image/webp:https://example.com/sticker.webp"), "image/jpeg", "image/png")) -> false
+//	@(has_attachment(array("image/png:https://example.com/photo.png"), "image/jpeg", "image/png")) -> true
+//	@(has_attachment(array())) -> false
+//
+// @test has_attachment(attachments, [types...])
+func HasAttachment(ctx context.Context, env envs.Environment, args ...types.XValue) types.XValue {
+	contentTypes := make([]string, len(args)-1)
+	for i, arg := range args[1:] {
+		asText, xerr := types.ToXText(env, arg)
+		if xerr != nil {
+			return xerr
+		}
+		contentTypes[i] = strings.ToLower(asText.Native())
+	}
+
+	return findAttachment(env, args[0], func(a utils.Attachment) bool {
+		if len(contentTypes) == 0 {
+			return true
+		}
+		for _, t := range contentTypes {
+			if t == a.ContentType() || t == a.MediaType() {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// HasImage tests whether `attachments` contains an image
+//
+//	@(has_image(input.attachments)) -> true
+//	@(has_image(input.attachments).match) -> image/jpeg:http://s3.amazon.com/bucket/test.jpg
+//	@(has_image(array("audio/mp4:https://example.com/note.m4a"))) -> false
+//
+// @test has_image(attachments)
+func HasImage(env envs.Environment, attachments types.XValue) types.XValue {
+	return hasMediaType(env, attachments, "image")
+}
+
+// HasVideo tests whether `attachments` contains a video
+//
+//	@(has_video(array("video/mp4:https://example.com/clip.mp4"))) -> true
+//	@(has_video(input.attachments)) -> false
+//
+// @test has_video(attachments)
+func HasVideo(env envs.Environment, attachments types.XValue) types.XValue {
+	return hasMediaType(env, attachments, "video")
+}
+
+// HasAudio tests whether `attachments` contains an audio recording
+//
+//	@(has_audio(input.attachments)) -> true
+//	@(has_audio(input.attachments).match) -> audio/mp3:http://s3.amazon.com/bucket/test.mp3
+//	@(has_audio(array("image/jpeg:https://example.com/photo.jpg"))) -> false
+//
+// @test has_audio(attachments)
+func HasAudio(env envs.Environment, attachments types.XValue) types.XValue {
+	return hasMediaType(env, attachments, "audio")
+}
+
+// HasAudioLongerThan tests whether `attachments` contains an audio recording which is longer than `seconds`.
+// Recordings whose duration isn't known never match.
+//
+//	@(has_audio_longer_than(array("audio/mp4;duration=30:https://example.com/note.m4a"), 10)) -> true
+//	@(has_audio_longer_than(array("audio/mp4;duration=30:https://example.com/note.m4a"), 30)) -> false
+//	@(has_audio_longer_than(input.attachments, 0)) -> false
+//	@(has_audio_longer_than(input.attachments, "xx")) -> ERROR
+//
+// @test has_audio_longer_than(attachments, seconds)
+func HasAudioLongerThan(env envs.Environment, attachments types.XValue, seconds types.XValue) types.XValue {
+	limit, xerr := types.ToInteger(env, seconds)
+	if xerr != nil {
+		return xerr
+	}
+
+	return findAttachment(env, attachments, func(a utils.Attachment) bool {
+		return a.MediaType() == "audio" && a.Duration() > limit
+	})
+}
+
+func hasMediaType(env envs.Environment, attachments types.XValue, mediaType string) types.XValue {
+	return findAttachment(env, attachments, func(a utils.Attachment) bool { return a.MediaType() == mediaType })
+}
+
+// finds the first valid attachment in the given list or single value which satisfies the given predicate
+func findAttachment(env envs.Environment, attachments types.XValue, predicate func(utils.Attachment) bool) types.XValue {
+	if types.IsXError(attachments) {
+		return attachments
+	}
+
+	var items []types.XValue
+	if array, isArray := attachments.(*types.XArray); isArray {
+		items = make([]types.XValue, array.Count())
+		for i := range items {
+			items[i] = array.Get(i)
+		}
+	} else if attachments != nil {
+		items = []types.XValue{attachments}
+	}
+
+	for _, item := range items {
+		asText, xerr := types.ToXText(env, item)
+		if xerr != nil {
+			return xerr
+		}
+
+		attachment := utils.Attachment(asText.Native())
+		if attachment.ContentType() != "" && attachment.URL() != "" && predicate(attachment) {
+			return NewTrueResult(asText)
+		}
+	}
+
+	return FalseResult
+}
+
 // HasPhrase tests whether `phrase` is contained in `text`
 //
 // The words in the test phrase must appear in the same order with no other words
-// in between.
+// in between. If `tolerance` is provided, words can be misspelled by up to that many
+// characters, though words shorter than 4 characters must always match exactly and longer
+// words can't differ by more than a quarter of their length.
 //
 //	@(has_phrase("the quick brown fox", "brown fox")) -> true
 //	@(has_phrase("the quick brown fox", "brown fox").match) -> brown fox
 //	@(has_phrase("the Quick Brown fox", "quick fox")) -> false
 //	@(has_phrase("the Quick Brown fox", "").match) ->
+//	@(has_phrase("the quik brwn fox", "quick brown", 1).match) -> quik brwn
+//	@(has_phrase("the quick brown fox", "quick brown", -1)) -> ERROR
 //
-// @test has_phrase(text, phrase)
-func HasPhrase(env envs.Environment, text *types.XText, test *types.XText) types.XValue {
-	return testTextTokens(env, text, test, hasPhraseTest)
+// @test has_phrase(text, phrase [,tolerance])
+func HasPhrase(env envs.Environment, text *types.XText, args ...types.XValue) types.XValue {
+	return testTextTokensWithTolerance(env, text, args, hasPhraseTest)
 }
 
 // HasAllWords tests whether all the `words` are contained in `text`
@@ -213,25 +465,33 @@ func HasPhrase(env envs.Environment, text *types.XText, test *types.XText) types
 //
 // @test has_all_words(text, words)
 func HasAllWords(env envs.Environment, text *types.XText, test *types.XText) types.XValue {
-	return testTextTokens(env, text, test, hasAllWordsTest)
+	return testTextTokens(env, text, test, newWordMatcher(env, 0), hasAllWordsTest)
 }
 
 // HasAnyWord tests whether any of the `words` are contained in the `text`
 //
-// Only one of the words needs to match and it may appear more than once.
+// Only one of the words needs to match and it may appear more than once. If `tolerance`
+// is provided, words can be misspelled by up to that many characters, though words shorter
+// than 4 characters must always match exactly and longer words can't differ by more than
+// a quarter of their length.
 //
 //	@(has_any_word("The Quick Brown Fox", "fox quick")) -> true
 //	@(has_any_word("The Quick Brown Fox", "fox quick").match) -> Quick Fox
 //	@(has_any_word("The Quick Brown Fox", "red fox").match) -> Fox
+//	@(has_any_word("I want to regster", "register join", 2).match) -> regster
+//	@(has_any_word("I want to regster", "register join")) -> false
 //
-// @test has_any_word(text, words)
-func HasAnyWord(env envs.Environment, text *types.XText, test *types.XText) types.XValue {
-	return testTextTokens(env, text, test, hasAnyWordTest)
+// @test has_any_word(text, words [,tolerance])
+func HasAnyWord(env envs.Environment, text *types.XText, args ...types.XValue) types.XValue {
+	return testTextTokensWithTolerance(env, text, args, hasAnyWordTest)
 }
 
 // HasOnlyPhrase tests whether the `text` contains only `phrase`
 //
-// The phrase must be the only text in the text to match
+// The phrase must be the only text in the text to match. If `tolerance` is provided, words
+// can be misspelled by up to that many characters, though words shorter than 4 characters
+// must always match exactly and longer words can't differ by more than a quarter of their
+// length.
 //
 //	@(has_only_phrase("Quick Brown", "quick brown")) -> true
 //	@(has_only_phrase("Quick Brown", "quick brown").match) -> Quick Brown
@@ -239,10 +499,12 @@ func HasAnyWord(env envs.Environment, text *types.XText, test *types.XText) type
 //	@(has_only_phrase("the Quick Brown fox", "")) -> false
 //	@(has_only_phrase("", "").match) ->
 //	@(has_only_phrase("The Quick Brown Fox", "red fox")) -> false
+//	@(has_only_phrase("Yess", "yes", 1)) -> false
+//	@(has_only_phrase("Unsubscrbe", "unsubscribe", 1)) -> true
 //
-// @test has_only_phrase(text, phrase)
-func HasOnlyPhrase(env envs.Environment, text *types.XText, test *types.XText) types.XValue {
-	return testTextTokens(env, text, test, hasOnlyPhraseTest)
+// @test has_only_phrase(text, phrase [,tolerance])
+func HasOnlyPhrase(env envs.Environment, text *types.XText, args ...types.XValue) types.XValue {
+	return testTextTokensWithTolerance(env, text, args, hasOnlyPhraseTest)
 }
 
 // HasText tests whether there the text has any characters in it
@@ -303,11 +565,13 @@ func HasBeginning(env envs.Environment, text *types.XText, beginning *types.XTex
 
 // HasPattern tests whether `text` matches the regex `pattern`
 //
-// Both text values are trimmed of surrounding whitespace and matching is case-insensitive.
+// Both text values are trimmed of surrounding whitespace and matching is case-insensitive. Named
+// groups are included in the extra by name as well as by index.
 //
 //	@(has_pattern("Buy cheese please", "buy (\w+)")) -> true
 //	@(has_pattern("Buy cheese please", "buy (\w+)").match) -> Buy cheese
 //	@(has_pattern("Buy cheese please", "buy (\w+)").extra) -> {0: Buy cheese, 1: cheese}
+//	@(has_pattern("REG JOHN 25", "reg (?P<name>\w+) (?P<age>\d+)").extra.age) -> 25
 //	@(has_pattern("Sell cheese please", "buy (\w+)")) -> false
 //
 // @test has_pattern(text, pattern)
@@ -320,9 +584,14 @@ func HasPattern(env envs.Environment, text *types.XText, pattern *types.XText) t
 	matches := regex.FindStringSubmatch(text.Native())
 	if matches != nil {
 		extra := make(map[string]types.XValue, len(matches))
+		names := regex.SubexpNames()
 
 		for i, group := range matches {
 			extra[strconv.Itoa(i)] = types.NewXText(group)
+
+			if names[i] != "" {
+				extra[names[i]] = types.NewXText(group)
+			}
 		}
 		return NewTrueResultWithExtra(types.NewXText(matches[0]), types.NewXObject(extra))
 	}
@@ -332,6 +601,10 @@ func HasPattern(env envs.Environment, text *types.XText, pattern *types.XText) t
 
 // HasNumber tests whether `text` contains a number
 //
+// Numbers written out in words aren't found unless the environment has `input_number_words`
+// enabled, in which case whole numbers written in the default language, e.g. "forty two", are
+// also found. This applies to all of the number tests.
+//
 //	@(has_number("the number is 42")) -> true
 //	@(has_number("the number is 42").match) -> 42
 //	@(has_number("العدد ٤٢").match) -> 42
@@ -435,8 +708,12 @@ func HasNumberGT(env envs.Environment, text *types.XText, num *types.XNumber) ty
 
 // HasDate tests whether `text` contains a date formatted according to our environment
 //
+// Dates written relative to today aren't matched unless the environment has `input_relative_dates`
+// enabled, in which case dates in the default language, e.g. "tomorrow" or "in 3 days", are also matched.
+//
 //	@(has_date("the date is 15/01/2017")) -> true
 //	@(has_date("the date is 15/01/2017").match) -> 2017-01-15T13:24:30.123456-05:00
+//	@(has_date("see you tomorrow")) -> false
 //	@(has_date("there is no date here, just a year 2017")) -> false
 //
 // @test has_date(text)
@@ -551,11 +828,13 @@ func HasPhone(env envs.Environment, text *types.XText, args ...types.XValue) typ
 	return NewTrueResult(types.NewXText(numbers[0]))
 }
 
-// HasState tests whether a state name is contained in the `text`
+// HasState tests whether a state name is contained in the `text`. If `text` is instead a shared location, e.g. a
+// `geo:<lat>,<long>` attachment, then it matches the state whose boundary contains it.
 //
 //	@(has_state("Kigali").match) -> Rwanda > Kigali City
 //	@(has_state("¡Kigali!").match) -> Rwanda > Kigali City
 //	@(has_state("I live in Kigali").match) -> Rwanda > Kigali City
+//	@(has_state("geo:-1.93,30.1").match) -> Rwanda > Kigali City
 //	@(has_state("Boston")) -> false
 //
 // @test has_state(text)
@@ -566,6 +845,9 @@ func HasState(env envs.Environment, text *types.XText) types.XValue {
 	}
 
 	states := locations.FindLocationsFuzzy(env, text.Native(), core.LocationLevelState, nil)
+	if len(states) == 0 {
+		states = findLocationsByPoint(env, locations, text.Native(), core.LocationLevelState, nil)
+	}
 	if len(states) > 0 {
 		return NewTrueResult(types.NewXText(string(states[0].Path())))
 	}
@@ -573,12 +855,15 @@ func HasState(env envs.Environment, text *types.XText) types.XValue {
 }
 
 // HasDistrict tests whether a district name is contained in the `text`. If `state` is also provided
-// then the returned district must be within that state.
+// then the returned district must be within that state. If `text` is instead a shared location, e.g. a
+// `geo:<lat>,<long>` attachment, then it matches the district whose boundary contains it.
 //
 //	@(has_district("Gasabo", "Kigali").match) -> Rwanda > Kigali City > Gasabo
 //	@(has_district("I live in Gasabo", "Kigali").match) -> Rwanda > Kigali City > Gasabo
 //	@(has_district("Gasabo", "Boston")) -> false
 //	@(has_district("Gasabo").match) -> Rwanda > Kigali City > Gasabo
+//	@(has_district("geo:-1.93,30.1", "Kigali").match) -> Rwanda > Kigali City > Gasabo
+//	@(has_district("geo:-1.95,30.04", "Kigali")) -> false
 //
 // @test has_district(text, state)
 func HasDistrict(ctx context.Context, env envs.Environment, args ...types.XValue) types.XValue {
@@ -605,6 +890,9 @@ func HasDistrict(ctx context.Context, env envs.Environment, args ...types.XValue
 	states := locations.FindLocationsFuzzy(env, stateText.Native(), core.LocationLevelState, nil)
 	if len(states) > 0 {
 		districts := locations.FindLocationsFuzzy(env, text.Native(), core.LocationLevelDistrict, states[0])
+		if len(districts) == 0 {
+			districts = findLocationsByPoint(env, locations, text.Native(), core.LocationLevelDistrict, states[0])
+		}
 		if len(districts) > 0 {
 			return NewTrueResult(types.NewXText(string(districts[0].Path())))
 		}
@@ -613,6 +901,9 @@ func HasDistrict(ctx context.Context, env envs.Environment, args ...types.XValue
 	// try without a parent state - it's ok as long as we get a single match
 	if stateText.Empty() {
 		districts := locations.FindLocationsFuzzy(env, text.Native(), core.LocationLevelDistrict, nil)
+		if len(districts) == 0 {
+			districts = findLocationsByPoint(env, locations, text.Native(), core.LocationLevelDistrict, nil)
+		}
 		if len(districts) == 1 {
 			return NewTrueResult(types.NewXText(string(districts[0].Path())))
 		}
@@ -621,7 +912,8 @@ func HasDistrict(ctx context.Context, env envs.Environment, args ...types.XValue
 	return FalseResult
 }
 
-// HasWard tests whether a ward name is contained in the `text`
+// HasWard tests whether a ward name is contained in the `text`. If `text` is instead a shared location, e.g. a
+// `geo:<lat>,<long>` attachment, then it matches the ward whose boundary contains it.
 //
 //	@(has_ward("Gisozi", "Kigali", "Gasabo").match) -> Rwanda > Kigali City > Gasabo > Gisozi
 //	@(has_ward("I live in Gisozi", "Kigali", "Gasabo").match) -> Rwanda > Kigali City > Gasabo > Gisozi
@@ -667,6 +959,9 @@ func HasWard(ctx context.Context, env envs.Environment, args ...types.XValue) ty
 		districts := locations.FindLocationsFuzzy(env, districtText.Native(), core.LocationLevelDistrict, states[0])
 		if len(districts) > 0 {
 			wards := locations.FindLocationsFuzzy(env, text.Native(), core.LocationLevelWard, districts[0])
+			if len(wards) == 0 {
+				wards = findLocationsByPoint(env, locations, text.Native(), core.LocationLevelWard, districts[0])
+			}
 			if len(wards) > 0 {
 				return NewTrueResult(types.NewXText(string(wards[0].Path())))
 			}
@@ -676,6 +971,9 @@ func HasWard(ctx context.Context, env envs.Environment, args ...types.XValue) ty
 	// try without a parent district - it's ok as long as we get a single match
 	if districtText.Empty() {
 		wards := locations.FindLocationsFuzzy(env, text.Native(), core.LocationLevelWard, nil)
+		if len(wards) == 0 {
+			wards = findLocationsByPoint(env, locations, text.Native(), core.LocationLevelWard, nil)
+		}
 		if len(wards) == 1 {
 			return NewTrueResult(types.NewXText(string(wards[0].Path())))
 		}
@@ -684,20 +982,197 @@ func HasWard(ctx context.Context, env envs.Environment, args ...types.XValue) ty
 	return FalseResult
 }
 
+// finds locations at the given level whose boundaries contain the shared location in the given text
+func findLocationsByPoint(env envs.Environment, locations envs.LocationResolver, text string, level envs.LocationLevel, parent *envs.Location) []*envs.Location {
+	resolver, canFindByPoint := locations.(envs.PointLocationResolver)
+	if !canFindByPoint {
+		return nil
+	}
+	point, found := utils.ParseGeoPoint(text)
+	if !found {
+		return nil
+	}
+	return resolver.FindLocationsByPoint(env, point, level, parent)
+}
+
+// HasLocationWithin tests whether `text` contains a shared location, e.g. a `geo:<lat>,<long>` attachment, which is
+// within `radius` kilometers of the point at `latitude` and `longitude`.
+//
+// If successful, the match is the shared location and the extra has the `distance` to it in kilometers.
+//
+//	@(has_location_within("geo:-1.9441,30.0619", -1.95, 30.06, 5)) -> true
+//	@(has_location_within("geo:-1.9441,30.0619", -1.95, 30.06, 5).match) -> -1.9441,30.0619
+//	@(has_location_within("geo:-1.9441,30.0619", -1.95, 30.06, 5).extra.distance) -> 0.689
+//	@(has_location_within("-2.6,29.75", -1.95, 30.06, 5)) -> false
+//	@(has_location_within("I'm at home", -1.95, 30.06, 5)) -> false
+//	@(has_location_within("geo:-1.9441,30.0619", -1.95, 30.06, "far")) -> ERROR
+//
+// @test has_location_within(text, latitude, longitude, radius)
+func HasLocationWithin(ctx context.Context, env envs.Environment, args ...types.XValue) types.XValue {
+	text, xerr := types.ToXText(env, args[0])
+	if xerr != nil {
+		return xerr
+	}
+
+	nums := make([]float64, 3)
+	for i, arg := range args[1:] {
+		num, xerr := types.ToXNumber(env, arg)
+		if xerr != nil {
+			return xerr
+		}
+		nums[i], _ = num.Native().Float64()
+	}
+
+	center, valid := utils.NewGeoPoint(nums[0], nums[1])
+	if !valid {
+		return types.NewXErrorf("%s,%s is not a valid location", types.Render(args[1]), types.Render(args[2]))
+	}
+
+	point, found := utils.ParseGeoPoint(text.Native())
+	if !found {
+		return FalseResult
+	}
+
+	distance := point.DistanceTo(center)
+	if distance > nums[2] {
+		return FalseResult
+	}
+
+	return NewTrueResultWithExtra(types.NewXText(point.String()), types.NewXObject(map[string]types.XValue{
+		"distance": types.NewXNumber(decimal.NewFromFloat(distance).Round(3)),
+	}))
+}
+
+// HasLocationIn tests whether `text` contains a shared location, e.g. a `geo:<lat>,<long>` attachment, which is inside
+// the location with the path `location`. The path can be partial, e.g. `Kigali > Gasabo`, and names can be aliases.
+//
+// If successful, the match is the full path of the location. Locations without boundaries of their own are checked
+// against the boundaries of their descendants, and never match if none of those have boundaries either.
+//
+//	@(has_location_in("geo:-1.93,30.1", "Rwanda > Kigali City > Gasabo")) -> true
+//	@(has_location_in("geo:-1.93,30.1", "rwanda > kigali city > gasabo").match) -> Rwanda > Kigali City > Gasabo
+//	@(has_location_in("geo:-1.93,30.1", "Kigali > Gasabo").match) -> Rwanda > Kigali City > Gasabo
+//	@(has_location_in("geo:-1.93,30.1", "Rwanda > Kigali City").match) -> Rwanda > Kigali City
+//	@(has_location_in("geo:-1.95,30.04", "Rwanda > Kigali City > Gasabo")) -> false
+//	@(has_location_in("I'm in Gasabo", "Rwanda > Kigali City > Gasabo")) -> false
+//
+// @test has_location_in(text, location)
+func HasLocationIn(env envs.Environment, text *types.XText, path *types.XText) types.XValue {
+	locations := env.LocationResolver()
+	if locations == nil {
+		return types.NewXErrorf("can't find locations in environment which is not location enabled")
+	}
+
+	location := lookupPartialPath(env, locations, path.Native())
+	if location == nil {
+		return FalseResult
+	}
+
+	point, found := utils.ParseGeoPoint(text.Native())
+	if found && containsPoint(location, point) {
+		return NewTrueResult(types.NewXText(string(location.Path())))
+	}
+
+	return FalseResult
+}
+
+// looks up a location by a path which may be partial, i.e. start below the root, and whose names may be aliases
+func lookupPartialPath(env envs.Environment, locations envs.LocationResolver, path string) *envs.Location {
+	if location := locations.LookupLocation(envs.LocationPath(path)); location != nil {
+		return location
+	}
+
+	names := strings.Split(path, envs.LocationPathSeparator)
+
+	// the first name can be at any level and then each following name must be a child of the previous
+	for level := envs.LocationLevel(0); level <= core.LocationLevelWard; level++ {
+		for _, first := range locations.FindLocations(env, strings.TrimSpace(names[0]), level, nil) {
+			if location := lookupChildPath(env, locations, first, names[1:]); location != nil {
+				return location
+			}
+		}
+	}
+	return nil
+}
+
+func lookupChildPath(env envs.Environment, locations envs.LocationResolver, parent *envs.Location, names []string) *envs.Location {
+	if len(names) == 0 {
+		return parent
+	}
+	for _, child := range locations.FindLocations(env, strings.TrimSpace(names[0]), parent.Level()+1, parent) {
+		if location := lookupChildPath(env, locations, child, names[1:]); location != nil {
+			return location
+		}
+	}
+	return nil
+}
+
+// checks whether the given point is inside the given location, using the boundaries of its descendants if it
+// doesn't have a boundary of its own
+func containsPoint(location *envs.Location, point utils.GeoPoint) bool {
+	if location.Boundary() != nil {
+		return location.Contains(point)
+	}
+	for _, child := range location.Children() {
+		if containsPoint(child, point) {
+			return true
+		}
+	}
+	return false
+}
+
 //------------------------------------------------------------------------------------------
 // Text Test Functions
 //------------------------------------------------------------------------------------------
 
-type stringTokenTest func(env envs.Environment, hayTokens []string, pinTokens []string) types.XValue
+type stringTokenTest func(isMatch wordMatcher, hayTokens []string, pinTokens []string) types.XValue
+
+// a wordMatcher returns whether a word from the text matches a word from the test
+type wordMatcher func(hay, pin string) bool
+
+// creates a word matcher which uses the environment's collation and tolerates up to the given number of edits
+func newWordMatcher(env envs.Environment, tolerance int) wordMatcher {
+	return func(hay, pin string) bool {
+		hay, pin = envs.CollateTransform(env, hay), envs.CollateTransform(env, pin)
+		if hay == pin {
+			return true
+		}
 
-func testTextTokens(env envs.Environment, str *types.XText, testStr *types.XText, testFunc stringTokenTest) types.XValue {
+		// short words have to match exactly and longer words can't differ by more than a quarter of their length
+		maxEdits := min(tolerance, utf8.RuneCountInString(pin)/4)
+
+		return maxEdits > 0 && utils.EditDistance(hay, pin) <= maxEdits
+	}
+}
+
+func testTextTokens(env envs.Environment, str *types.XText, testStr *types.XText, isMatch wordMatcher, testFunc stringTokenTest) types.XValue {
 	hays := utils.TokenizeString(strings.TrimSpace(str.Native()))
 	needles := utils.TokenizeString(strings.TrimSpace(testStr.Native()))
 
-	return testFunc(env, hays, needles)
+	return testFunc(isMatch, hays, needles)
 }
 
-func hasPhraseTest(env envs.Environment, hays []string, pins []string) types.XValue {
+// tests text tokens against the test text and optional tolerance in the given args
+func testTextTokensWithTolerance(env envs.Environment, str *types.XText, args []types.XValue, testFunc stringTokenTest) types.XValue {
+	testStr, xerr := types.ToXText(env, args[0])
+	if xerr != nil {
+		return xerr
+	}
+
+	tolerance := 0
+	if len(args) == 2 {
+		if tolerance, xerr = types.ToInteger(env, args[1]); xerr != nil {
+			return xerr
+		}
+		if tolerance < 0 {
+			return types.NewXErrorf("tolerance can't be negative")
+		}
+	}
+
+	return testTextTokens(env, str, testStr, newWordMatcher(env, tolerance), testFunc)
+}
+
+func hasPhraseTest(isMatch wordMatcher, hays []string, pins []string) types.XValue {
 	if len(pins) == 0 {
 		return NewTrueResult(types.XTextEmpty)
 	}
@@ -705,7 +1180,7 @@ func hasPhraseTest(env envs.Environment, hays []string, pins []string) types.XVa
 	pinIdx := 0
 	matches := make([]string, len(pins))
 	for i, hay := range hays {
-		if envs.CollateEquals(env, hay, pins[pinIdx]) {
+		if isMatch(hay, pins[pinIdx]) {
 			matches[pinIdx] = hays[i]
 			pinIdx++
 			if pinIdx == len(pins) {
@@ -723,14 +1198,14 @@ func hasPhraseTest(env envs.Environment, hays []string, pins []string) types.XVa
 	return FalseResult
 }
 
-func hasAllWordsTest(env envs.Environment, hays []string, pins []string) types.XValue {
+func hasAllWordsTest(isMatch wordMatcher, hays []string, pins []string) types.XValue {
 	matches := make([]string, 0, len(pins))
 	pinMatches := make([]int, len(pins))
 
 	for i, hay := range hays {
 		matched := false
 		for j, pin := range pins {
-			if envs.CollateEquals(env, hay, pin) {
+			if isMatch(hay, pin) {
 				matched = true
 				pinMatches[j]++
 			}
@@ -757,12 +1232,12 @@ func hasAllWordsTest(env envs.Environment, hays []string, pins []string) types.X
 	return FalseResult
 }
 
-func hasAnyWordTest(env envs.Environment, hays []string, pins []string) types.XValue {
+func hasAnyWordTest(isMatch wordMatcher, hays []string, pins []string) types.XValue {
 	matches := make([]string, 0, len(pins))
 	for i, hay := range hays {
 		matched := false
 		for _, pin := range pins {
-			if envs.CollateEquals(env, hay, pin) {
+			if isMatch(hay, pin) {
 				matched = true
 				break
 			}
@@ -780,7 +1255,7 @@ func hasAnyWordTest(env envs.Environment, hays []string, pins []string) types.XV
 	return FalseResult
 }
 
-func hasOnlyPhraseTest(env envs.Environment, hays []string, pins []string) types.XValue {
+func hasOnlyPhraseTest(isMatch wordMatcher, hays []string, pins []string) types.XValue {
 	// must be same length
 	if len(hays) != len(pins) {
 		return FalseResult
@@ -789,7 +1264,7 @@ func hasOnlyPhraseTest(env envs.Environment, hays []string, pins []string) types
 	// and every token must match
 	matches := make([]string, 0, len(pins))
 	for i := range hays {
-		if !envs.CollateEquals(env, hays[i], pins[i]) {
+		if !isMatch(hays[i], pins[i]) {
 			return FalseResult
 		}
 		matches = append(matches, hays[i])
@@ -831,13 +1306,32 @@ func testNumber(env envs.Environment, str *types.XText, testNum1 *types.XNumber,
 		return types.NewXErrorf("environment has a number format which can't be used to find numbers")
 	}
 
-	// look for number like things in the input and use the first one that we can actually parse
-	for _, value := range pattern.FindAllString(str.Native(), -1) {
-		num, err := ParseNumber(value, env.NumberFormat())
+	type foundNumber struct {
+		start int
+		num   *types.XNumber
+	}
+	found := make([]foundNumber, 0)
+
+	// look for number like things in the input that we can actually parse
+	for _, loc := range pattern.FindAllStringIndex(str.Native(), -1) {
+		num, err := ParseNumber(str.Native()[loc[0]:loc[1]], env.NumberFormat())
 		if err == nil {
-			if testFunc(num, testNum1, testNum2) {
-				return NewTrueResult(num)
-			}
+			found = append(found, foundNumber{loc[0], num})
+		}
+	}
+
+	// and if the environment allows it, numbers written in words in the default language
+	if env.InputNumberWords() {
+		for _, m := range envs.FindNumberWords(env.DefaultLanguage(), str.Native()) {
+			found = append(found, foundNumber{m.Start, types.NewXNumberFromInt64(m.Value)})
+		}
+		sort.SliceStable(found, func(i, j int) bool { return found[i].start < found[j].start })
+	}
+
+	// and use the first one which passes the test
+	for _, f := range found {
+		if testFunc(f.num, testNum1, testNum2) {
+			return NewTrueResult(f.num)
 		}
 	}
 
@@ -882,14 +1376,21 @@ func testDate(env envs.Environment, str *types.XText, testDate *types.XDateTime,
 	// first parse with time filling which will be the test result
 	value, xerr := types.ToXDateTimeWithTimeFill(env, str)
 
-	// but comparison should be against only the date portions
-	valueAsDate := dates.ExtractDate(value.In(env.Timezone()).Native())
-	testAsDate := dates.ExtractDate(testDate.In(env.Timezone()).Native())
+	// and if that fails, maybe it's written relative to today
+	if xerr != nil && env.InputRelativeDates() {
+		if relative, err := envs.RelativeDateTimeFromString(env, str.Native(), true); err == nil {
+			value, xerr = types.NewXDateTime(relative), nil
+		}
+	}
 
 	if xerr != nil {
 		return FalseResult
 	}
 
+	// but comparison should be against only the date portions
+	valueAsDate := dates.ExtractDate(value.In(env.Timezone()).Native())
+	testAsDate := dates.ExtractDate(testDate.In(env.Timezone()).Native())
+
 	if testFunc(valueAsDate, testAsDate) {
 		return NewTrueResult(value)
 	}
@@ -43,6 +43,22 @@ var dmy = envs.NewBuilder().
 var ara = envs.NewBuilder().
 	WithInputCollation(envs.CollationArabicVariants).
 	Build()
+var trl = envs.NewBuilder().
+	WithInputCollation(envs.CollationTransliterate).
+	Build()
+var phn = envs.NewBuilder().
+	WithInputCollation(envs.CollationPhonetic).
+	Build()
+var wrd = envs.NewBuilder().
+	WithAllowedLanguages("eng", "spa").
+	WithInputNumberWords(true).
+	Build()
+var rel = envs.NewBuilder().
+	WithDateFormat(envs.DateFormatDayMonthYear).
+	WithTimezone(kgl).
+	WithAllowedLanguages("eng").
+	WithInputRelativeDates(true).
+	Build()
 
 var assetsJSON = `{
 	"flows": [
@@ -66,9 +82,12 @@ var assetsJSON = `{
 					"children": [
 						{
 							"name": "Gasabo",
+							"boundary": {"type": "Polygon", "coordinates": [[[30.05, -2.0], [30.25, -2.0], [30.25, -1.85], [30.05, -1.85], [30.05, -2.0]]]},
 							"children": [
 								{
-									"name": "Gisozi"
+									"name": "Gisozi",
+									"bbox": [30.08, -1.95, 30.12, -1.91],
+									"boundary": {"type": "Polygon", "coordinates": [[[30.08, -1.95], [30.12, -1.95], [30.12, -1.91], [30.08, -1.91], [30.08, -1.95]]]}
 								},
 								{
 									"name": "Ndera"
@@ -152,6 +171,22 @@ var testTests = []struct {
 	{"has_any_word", ara, []types.XValue{xs("بلی"), xs("بلی")}, result(xs("بلی"))}, // using ara-far collation
 	{"has_any_word", ara, []types.XValue{xs("بلي"), xs("بلی")}, result(xs("بلي"))}, // using ara-far collation
 	{"has_any_word", ara, []types.XValue{xs("بلى"), xs("بلی")}, result(xs("بلى"))}, // using ara-far collation
+	{"has_any_word", trl, []types.XValue{xs("Привет"), xs("privet")}, result(xs("Привет"))},
+	{"has_any_word", trl, []types.XValue{xs("नमस्ते जी"), xs("namaste")}, result(xs("नमस्ते"))},
+	{"has_any_word", trl, []types.XValue{xs("ሰላም"), xs("selam")}, result(xs("ሰላም"))},
+	{"has_any_word", phn, []types.XValue{xs("I want to rejister plz"), xs("register please")}, result(xs("rejister plz"))},
+	{"has_any_word", phn, []types.XValue{xs("I want to stop"), xs("register")}, falseResult},
+	{"has_any_word", dmy, []types.XValue{xs("I want to regster"), xs("register join"), xn("1")}, result(xs("regster"))},
+	{"has_any_word", dmy, []types.XValue{xs("I want to regstr"), xs("register join"), xn("1")}, falseResult},
+	{"has_any_word", dmy, []types.XValue{xs("I want to regstr"), xs("register join"), xn("2")}, result(xs("regstr"))},
+	{"has_any_word", dmy, []types.XValue{xs("I want to regstr"), xs("register join"), xn("5")}, result(xs("regstr"))}, // capped by word length
+	{"has_any_word", dmy, []types.XValue{xs("I want to rgstr"), xs("register join"), xn("5")}, falseResult},           // capped by word length
+	{"has_any_word", dmy, []types.XValue{xs("no"), xs("so yes"), xn("2")}, falseResult},                               // short words are exact
+	{"has_any_word", dmy, []types.XValue{xs("I want to regster"), xs("register join"), xn("0")}, falseResult},         // no tolerance
+	{"has_any_word", phn, []types.XValue{xs("pleeeze registar"), xs("please register"), xn("1")}, result(xs("pleeeze registar"))},
+	{"has_any_word", dmy, []types.XValue{xs("I want to regster"), xs("register"), xn("-1")}, ERROR},
+	{"has_any_word", dmy, []types.XValue{xs("I want to regster"), xs("register"), ERROR}, ERROR},
+	{"has_any_word", dmy, []types.XValue{xs("one"), xs("two"), xn("1"), xn("2")}, ERROR},
 	{"has_any_word", dmy, []types.XValue{}, ERROR},
 
 	{"has_all_words", dmy, []types.XValue{xs("this.is.my.word"), xs("WORD word")}, result(xs("word"))},
@@ -165,6 +200,10 @@ var testTests = []struct {
 	{"has_phrase", dmy, []types.XValue{xs("this world Too"), xs("")}, result(xs(""))},
 	{"has_phrase", dmy, []types.XValue{xs("this is not world"), xs("this world")}, falseResult},
 	{"has_phrase", dmy, []types.XValue{xs("one"), xs("two"), xs("three")}, ERROR},
+	{"has_phrase", dmy, []types.XValue{xs("the quik brwn fox"), xs("quick brown"), xn("1")}, result(xs("quik brwn"))},
+	{"has_phrase", dmy, []types.XValue{xs("the quik brwn fox"), xs("quick brown")}, falseResult},
+	{"has_phrase", phn, []types.XValue{xs("pls stopp sending"), xs("please stop")}, result(xs("pls stopp"))},
+	{"has_phrase", dmy, []types.XValue{xs("the quick brown fox"), xs("quick brown"), xs("x")}, ERROR},
 	{"has_phrase", dmy, []types.XValue{}, ERROR},
 
 	{"has_only_phrase", dmy, []types.XValue{xs("Must resist"), xs("must resist")}, result(xs("Must resist"))},
@@ -174,6 +213,10 @@ var testTests = []struct {
 	{"has_only_phrase", dmy, []types.XValue{xs("this world is my world"), xs("this world")}, falseResult},
 	{"has_only_phrase", dmy, []types.XValue{xs("this world"), xs("this mighty")}, falseResult},
 	{"has_only_phrase", dmy, []types.XValue{xs("one"), xs("two"), xs("three")}, ERROR},
+	{"has_only_phrase", dmy, []types.XValue{xs("Unsubscrbe"), xs("unsubscribe"), xn("1")}, result(xs("Unsubscrbe"))},
+	{"has_only_phrase", dmy, []types.XValue{xs("Unsubscrbe now"), xs("unsubscribe"), xn("1")}, falseResult},
+	{"has_only_phrase", trl, []types.XValue{xs("Да"), xs("da")}, result(xs("Да"))},
+	{"has_only_phrase", dmy, []types.XValue{xs("Unsubscrbe"), xs("unsubscribe"), xn("-2")}, ERROR},
 	{"has_only_phrase", dmy, []types.XValue{}, ERROR},
 
 	{"has_beginning", dmy, []types.XValue{xs("Must resist"), xs("must resist")}, result(xs("Must resist"))},
@@ -192,6 +235,7 @@ var testTests = []struct {
 	{"has_pattern", dmy, []types.XValue{xs(`hi there 😀`), xs("[\U0001F600-\U0001F64F]")}, resultWithExtra(xs("😀"), types.NewXObject(map[string]types.XValue{"0": xs("😀")}))},
 	{"has_pattern", dmy, []types.XValue{xs(`hi there`), xs("[\U0001F600-\U0001F64F]")}, falseResult},
 	{"has_pattern", dmy, []types.XValue{xs(`hi there 😂`), xs("[😀-🙏]")}, resultWithExtra(xs("😂"), types.NewXObject(map[string]types.XValue{"0": xs("😂")}))},
+	{"has_pattern", dmy, []types.XValue{xs("REG John 25 F"), xs(`reg (?P<name>\w+) (?P<age>\d+)( (?P<gender>[mf]))?( (?P<district>\w+))?`)}, resultWithExtra(xs("REG John 25 F"), types.NewXObject(map[string]types.XValue{"0": xs("REG John 25 F"), "1": xs("John"), "2": xs("25"), "3": xs(" F"), "4": xs("F"), "5": xs(""), "6": xs(""), "name": xs("John"), "age": xs("25"), "gender": xs("F"), "district": xs("")}))},
 	{"has_pattern", dmy, []types.XValue{xs("<html>x</html>"), xs(`[`)}, ERROR},
 	{"has_pattern", dmy, []types.XValue{}, ERROR},
 
@@ -213,6 +257,13 @@ var testTests = []struct {
 	{"has_number", dmy, []types.XValue{xs("nothing here")}, falseResult},
 	{"has_number", dmy, []types.XValue{xs("lOO")}, falseResult}, // no longer do substitutions
 	{"has_number", dmy, []types.XValue{xs("one"), xs("two"), xs("three")}, ERROR},
+	{"has_number", dmy, []types.XValue{xs("forty two")}, falseResult}, // words not enabled
+	{"has_number", wrd, []types.XValue{xs("forty two")}, result(xn("42"))},
+	{"has_number", wrd, []types.XValue{xs("I have one hundred and five cows")}, result(xn("105"))},
+	{"has_number", wrd, []types.XValue{xs("2 or three")}, result(xn("2"))},
+	{"has_number", wrd, []types.XValue{xs("three or 2")}, result(xn("3"))},
+	{"has_number", wrd, []types.XValue{xs("cuarenta y dos")}, falseResult}, // only default language
+	{"has_number", wrd, []types.XValue{xs("nothing here")}, falseResult},
 	{"has_number", dmy, []types.XValue{}, ERROR},
 
 	{"has_number_lt", dmy, []types.XValue{xs("the number 10"), xs("11")}, result(xn("10"))},
@@ -220,6 +271,7 @@ var testTests = []struct {
 	{"has_number_lt", dmy, []types.XValue{xs("١٠"), xs("11")}, result(xn("10"))},
 	{"has_number_lt", dmy, []types.XValue{xs("nothing here"), xs("12")}, falseResult},
 	{"has_number_lt", dmy, []types.XValue{xs("too big 15"), xs("12")}, falseResult},
+	{"has_number_lt", wrd, []types.XValue{xs("fifteen or ten"), xs("12")}, result(xn("10"))},
 	{"has_number_lt", dmy, []types.XValue{xs("one"), xs("two"), xs("three")}, ERROR},
 	{"has_number_lt", dmy, []types.XValue{xs("but foo"), falseResult}, ERROR},
 	{"has_number_lt", dmy, []types.XValue{nil, xs("but foo")}, ERROR},
@@ -253,6 +305,7 @@ var testTests = []struct {
 	{"has_number_gt", dmy, []types.XValue{xs("another is -12.51"), xs("-13")}, result(xn("-12.51"))},
 	{"has_number_gt", dmy, []types.XValue{xs("١٠"), xs("9")}, result(xn("10"))},
 	{"has_number_gt", dmy, []types.XValue{xs("nothing here"), xs("12")}, falseResult},
+	{"has_number_gt", wrd, []types.XValue{xs("ninety nine"), xs("90")}, result(xn("99"))},
 	{"has_number_gt", dmy, []types.XValue{xs("not great -12.51"), xs("-12.51")}, falseResult},
 	{"has_number_gt", dmy, []types.XValue{xs("one"), xs("two"), xs("three")}, ERROR},
 	{"has_number_gt", dmy, []types.XValue{}, ERROR},
@@ -260,6 +313,7 @@ var testTests = []struct {
 	{"has_number_between", dmy, []types.XValue{xs("the number 10"), xs("8"), xs("12")}, result(xn("10"))},
 	{"has_number_between", dmy, []types.XValue{xs("24ans"), xn("20"), xn("24")}, result(xn("24"))},
 	{"has_number_between", dmy, []types.XValue{xs("another is -12.51"), xs("-12.51"), xs("-10")}, result(xn("-12.51"))},
+	{"has_number_between", wrd, []types.XValue{xs("between five and twenty"), xn("8"), xn("30")}, result(xn("20"))},
 	{"has_number_between", dmy, []types.XValue{xs("١٠"), xs("8"), xs("12")}, result(xn("10"))},
 	{"has_number_between", dmy, []types.XValue{xs("nothing here"), xs("10"), xs("15")}, falseResult},
 	{"has_number_between", dmy, []types.XValue{xs("one"), xs("two")}, ERROR},
@@ -276,12 +330,20 @@ var testTests = []struct {
 	{"has_date", dmy, []types.XValue{xs("last date was 1.10.99")}, result(xd(time.Date(1999, 10, 1, 15, 24, 30, 123456000, kgl)))},
 	{"has_date", dmy, []types.XValue{xs("this isn't a valid date 33.2.99")}, falseResult},
 	{"has_date", dmy, []types.XValue{xs("no date at all")}, falseResult},
+	{"has_date", dmy, []types.XValue{xs("tomorrow")}, falseResult}, // relative dates not enabled
+	{"has_date", rel, []types.XValue{xs("tomorrow")}, result(xd(time.Date(2018, 4, 12, 15, 24, 30, 123456000, kgl)))},
+	{"has_date", rel, []types.XValue{xs("next friday")}, result(xd(time.Date(2018, 4, 13, 15, 24, 30, 123456000, kgl)))},
+	{"has_date", rel, []types.XValue{xs("in 2 weeks at 9:30")}, result(xd(time.Date(2018, 4, 25, 9, 30, 0, 0, kgl)))},
+	{"has_date", rel, []types.XValue{xs("last date was 1.10.2017")}, result(xd(time.Date(2017, 10, 1, 15, 24, 30, 123456000, kgl)))},
+	{"has_date", rel, []types.XValue{xs("no date at all")}, falseResult},
 	{"has_date", dmy, []types.XValue{xs("too"), xs("many"), xs("args")}, ERROR},
 	{"has_date", dmy, []types.XValue{}, ERROR},
 
 	{"has_date_lt", dmy, []types.XValue{xs("last date was 1.10.2017"), xs("3.10.2017")}, result(xd(time.Date(2017, 10, 1, 15, 24, 30, 123456000, kgl)))},
 	{"has_date_lt", dmy, []types.XValue{xs("last date was 1.10.99"), xs("3.10.98")}, falseResult},
 	{"has_date_lt", dmy, []types.XValue{xs("no date at all"), xs("3.10.98")}, falseResult},
+	{"has_date_lt", rel, []types.XValue{xs("yesterday"), xs("11.4.2018")}, result(xd(time.Date(2018, 4, 10, 15, 24, 30, 123456000, kgl)))},
+	{"has_date_lt", rel, []types.XValue{xs("tomorrow"), xs("11.4.2018")}, falseResult},
 	{"has_date_lt", dmy, []types.XValue{xs("too"), xs("many"), xs("args")}, ERROR},
 	{"has_date_lt", dmy, []types.XValue{xs("last date was 1.10.2017"), nil}, ERROR},
 	{"has_date_lt", dmy, []types.XValue{nil, xs("but foo")}, ERROR},
@@ -292,12 +354,14 @@ var testTests = []struct {
 	{"has_date_eq", dmy, []types.XValue{xs("2017-10-01T23:55:55.123456+02:00"), xs("1.10.2017")}, result(xd(time.Date(2017, 10, 1, 23, 55, 55, 123456000, kgl)))},
 	{"has_date_eq", dmy, []types.XValue{xs("2017-10-01T23:55:55.123456+01:00"), xs("1.10.2017")}, falseResult}, // would have been 2017-10-02 in env timezone
 	{"has_date_eq", dmy, []types.XValue{xs("no date at all"), xs("3.10.98")}, falseResult},
+	{"has_date_eq", rel, []types.XValue{xs("today"), xs("11.4.2018")}, result(xd(time.Date(2018, 4, 11, 15, 24, 30, 123456000, kgl)))},
 	{"has_date_eq", dmy, []types.XValue{xs("too"), xs("many"), xs("args")}, ERROR},
 	{"has_date_eq", dmy, []types.XValue{}, ERROR},
 
 	{"has_date_gt", dmy, []types.XValue{xs("last date was 1.10.2017"), xs("3.10.2016")}, result(xd(time.Date(2017, 10, 1, 15, 24, 30, 123456000, kgl)))},
 	{"has_date_gt", dmy, []types.XValue{xs("last date was 1.10.99"), xs("3.10.01")}, falseResult},
 	{"has_date_gt", dmy, []types.XValue{xs("no date at all"), xs("3.10.98")}, falseResult},
+	{"has_date_gt", rel, []types.XValue{xs("next monday"), xs("15.4.2018")}, result(xd(time.Date(2018, 4, 16, 15, 24, 30, 123456000, kgl)))},
 	{"has_date_gt", dmy, []types.XValue{xs("too"), xs("many"), xs("args")}, ERROR},
 	{"has_date_gt", dmy, []types.XValue{}, ERROR},
 
@@ -341,6 +405,71 @@ var testTests = []struct {
 	{"has_group", dmy, []types.XValue{xa(), ERROR}, ERROR},
 	{"has_group", dmy, []types.XValue{}, ERROR},
 
+	{"has_payload", dmy, []types.XValue{xs("start")}, result(xs("start"))},
+	{"has_payload", dmy, []types.XValue{xs("start"), xs("start")}, result(xs("start"))},
+	{"has_payload", dmy, []types.XValue{xs("start"), xs("Start")}, falseResult},
+	{"has_payload", dmy, []types.XValue{xj(`"start"`), xs("start")}, result(xs("start"))},
+	{"has_payload", dmy, []types.XValue{xj(`12`), xs("12")}, result(xn("12"))},
+	{"has_payload", dmy, []types.XValue{xj(`{"id": 12}`)}, result(xj(`{"id": 12}`))},
+	{"has_payload", dmy, []types.XValue{types.XObjectEmpty}, falseResult},
+	{"has_payload", dmy, []types.XValue{types.XObjectEmpty, xs("start")}, falseResult},
+	{"has_payload", dmy, []types.XValue{xs("")}, falseResult},
+	{"has_payload", dmy, []types.XValue{xj(`0`), xs("0")}, result(xn("0"))},
+	{"has_payload", dmy, []types.XValue{types.XBooleanFalse}, result(types.XBooleanFalse)},
+	{"has_payload", dmy, []types.XValue{nil}, falseResult},
+	{"has_payload", dmy, []types.XValue{ERROR}, ERROR},
+	{"has_payload", dmy, []types.XValue{xs("start"), ERROR}, ERROR},
+	{"has_payload", dmy, []types.XValue{}, ERROR},
+	{"has_payload", dmy, []types.XValue{xs("too"), xs("many"), xs("args")}, ERROR},
+
+	{"has_payload_field", dmy, []types.XValue{xj(`{"button": {"id": "yes"}}`), xs("button.id")}, result(xs("yes"))},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"button": {"id": "yes"}}`), xs("BUTTON.ID"), xs("yes")}, result(xs("yes"))},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"button": {"id": "yes"}}`), xs("button.id"), xs("no")}, falseResult},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"button": {"id": "yes"}}`), xs("button.name")}, falseResult},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"button": {"id": "yes"}}`), xs("button.id.more")}, falseResult},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"button": {"id": ""}}`), xs("button.id")}, falseResult},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"items": [{"qty": 3}]}`), xs("items.0.qty"), xs("3")}, result(xn("3"))},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"items": [{"qty": 3}]}`), xs("items.1.qty")}, falseResult},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"items": [{"qty": 3}]}`), xs("items.first.qty")}, falseResult},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"done": true}`), xs("done"), xs("true")}, result(types.XBooleanTrue)},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"choice": 0}`), xs("choice"), xs("0")}, result(xn("0"))},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"done": false}`), xs("done"), xs("false")}, result(types.XBooleanFalse)},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"choice": null}`), xs("choice")}, falseResult},
+	{"has_payload_field", dmy, []types.XValue{xs("start"), xs("id")}, falseResult},
+	{"has_payload_field", dmy, []types.XValue{types.XObjectEmpty, xs("id")}, falseResult},
+	{"has_payload_field", dmy, []types.XValue{ERROR, xs("id")}, ERROR},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"id": 1}`), ERROR}, ERROR},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"id": 1}`), xs("id"), ERROR}, ERROR},
+	{"has_payload_field", dmy, []types.XValue{xj(`{"id": 1}`)}, ERROR},
+
+	{"has_attachment", dmy, []types.XValue{xa(xs("image/jpeg:http://a.com/1.jpg"))}, result(xs("image/jpeg:http://a.com/1.jpg"))},
+	{"has_attachment", dmy, []types.XValue{xs("image/jpeg:http://a.com/1.jpg")}, result(xs("image/jpeg:http://a.com/1.jpg"))},
+	{"has_attachment", dmy, []types.XValue{xa(xs("audio/mp3:http://a.com/1.mp3"), xs("image/png:http://a.com/2.png")), xs("image")}, result(xs("image/png:http://a.com/2.png"))},
+	{"has_attachment", dmy, []types.XValue{xa(xs("image/webp:http://a.com/1.webp")), xs("image/jpeg"), xs("IMAGE/PNG")}, falseResult},
+	{"has_attachment", dmy, []types.XValue{xa(xs("image/png;size=2048:http://a.com/1.png")), xs("image/jpeg"), xs("IMAGE/PNG")}, result(xs("image/png;size=2048:http://a.com/1.png"))},
+	{"has_attachment", dmy, []types.XValue{xa(xs("not an attachment"))}, falseResult},
+	{"has_attachment", dmy, []types.XValue{xa()}, falseResult},
+	{"has_attachment", dmy, []types.XValue{nil}, falseResult},
+	{"has_attachment", dmy, []types.XValue{ERROR}, ERROR},
+	{"has_attachment", dmy, []types.XValue{xa(xs("image/png:http://a.com/1.png")), ERROR}, ERROR},
+	{"has_attachment", dmy, []types.XValue{}, ERROR},
+
+	{"has_image", dmy, []types.XValue{xa(xs("audio/mp3:http://a.com/1.mp3"), xs("image:http://a.com/2.jpg"))}, result(xs("image:http://a.com/2.jpg"))},
+	{"has_image", dmy, []types.XValue{xa(xs("video/mp4:http://a.com/1.mp4"))}, falseResult},
+	{"has_image", dmy, []types.XValue{ERROR}, ERROR},
+	{"has_video", dmy, []types.XValue{xa(xs("video/mp4:http://a.com/1.mp4"))}, result(xs("video/mp4:http://a.com/1.mp4"))},
+	{"has_video", dmy, []types.XValue{xa(xs("image/jpeg:http://a.com/1.jpg"))}, falseResult},
+	{"has_audio", dmy, []types.XValue{xa(xs("audio/ogg:http://a.com/1.ogg"))}, result(xs("audio/ogg:http://a.com/1.ogg"))},
+	{"has_audio", dmy, []types.XValue{xa(xs("video/mp4:http://a.com/1.mp4"))}, falseResult},
+	{"has_audio", dmy, []types.XValue{}, ERROR},
+
+	{"has_audio_longer_than", dmy, []types.XValue{xa(xs("audio/mp4;duration=12:http://a.com/1.m4a")), xn("10")}, result(xs("audio/mp4;duration=12:http://a.com/1.m4a"))},
+	{"has_audio_longer_than", dmy, []types.XValue{xa(xs("audio/mp4;duration=12:http://a.com/1.m4a")), xn("12")}, falseResult},
+	{"has_audio_longer_than", dmy, []types.XValue{xa(xs("video/mp4;duration=30:http://a.com/1.mp4")), xn("10")}, falseResult},
+	{"has_audio_longer_than", dmy, []types.XValue{xa(xs("audio/mp4:http://a.com/1.m4a")), xn("0")}, falseResult},
+	{"has_audio_longer_than", dmy, []types.XValue{xa(xs("audio/mp4;duration=12:http://a.com/1.m4a")), xs("x")}, ERROR},
+	{"has_audio_longer_than", dmy, []types.XValue{ERROR, xn("10")}, ERROR},
+
 	{"has_state", dmy, []types.XValue{xs("Quebec")}, result(xs("Rwanda > Québec"))},
 	{"has_state", dmy, []types.XValue{xs("Québec")}, result(xs("Rwanda > Québec"))},
 	{"has_state", dmy, []types.XValue{xs("Je suis dans la province du Québec")}, result(xs("Rwanda > Québec"))},
@@ -355,20 +484,51 @@ var testTests = []struct {
 	{"has_state", dmy, []types.XValue{xs("غم ځپلې هلمند")}, falseResult},
 	{"has_state", dmy, []types.XValue{xs("\u063a\u0645 \u0681\u067e\u0644\u06d0 \u0647\u0644\u0645\u0646\u062f")}, falseResult},
 	{"has_state", dmy, []types.XValue{xs("xyz")}, falseResult},
+	{"has_state", dmy, []types.XValue{xs("geo:-1.93,30.1")}, falseResult}, // no state boundaries
 	{"has_state", dmy, []types.XValue{ERROR}, ERROR},
 
 	{"has_district", dmy, []types.XValue{xs("Gasabo"), xs("kigali")}, result(xs("Rwanda > Kigali City > Gasabo"))},
 	{"has_district", dmy, []types.XValue{xs("I live in gasabo"), xs("kigali")}, result(xs("Rwanda > Kigali City > Gasabo"))},
 	{"has_district", dmy, []types.XValue{xs("Gasabo")}, result(xs("Rwanda > Kigali City > Gasabo"))},
 	{"has_district", dmy, []types.XValue{xs("xyz"), xs("kigali")}, falseResult},
+	{"has_district", dmy, []types.XValue{xs("geo:-1.93,30.1"), xs("kigali")}, result(xs("Rwanda > Kigali City > Gasabo"))},
+	{"has_district", dmy, []types.XValue{xs("I'm at -1.93,30.1")}, result(xs("Rwanda > Kigali City > Gasabo"))},
+	{"has_district", dmy, []types.XValue{xs("geo:-1.95,30.04"), xs("kigali")}, falseResult},
+	{"has_district", dmy, []types.XValue{xs("geo:-1.93,30.1"), xs("québec")}, falseResult},
 	{"has_district", dmy, []types.XValue{ERROR}, ERROR},
 
 	{"has_ward", dmy, []types.XValue{xs("Gisozi"), xs("kigali"), xs("Gasabo")}, result(xs("Rwanda > Kigali City > Gasabo > Gisozi"))},
 	{"has_ward", dmy, []types.XValue{xs("I live in gisozi"), xs("kigali"), xs("Gasabo")}, result(xs("Rwanda > Kigali City > Gasabo > Gisozi"))},
 	{"has_ward", dmy, []types.XValue{xs("Gisozi")}, result(xs("Rwanda > Kigali City > Gasabo > Gisozi"))},
 	{"has_ward", dmy, []types.XValue{xs("xyz"), xs("kigali"), xs("Gasabo")}, falseResult},
+	{"has_ward", dmy, []types.XValue{xs("geo:-1.93,30.1"), xs("kigali"), xs("Gasabo")}, result(xs("Rwanda > Kigali City > Gasabo > Gisozi"))},
+	{"has_ward", dmy, []types.XValue{xs("geo:-1.93,30.1")}, result(xs("Rwanda > Kigali City > Gasabo > Gisozi"))},
+	{"has_ward", dmy, []types.XValue{xs("geo:-1.88,30.2"), xs("kigali"), xs("Gasabo")}, falseResult},
 	{"has_ward", dmy, []types.XValue{ERROR}, ERROR},
 
+	{"has_location_within", dmy, []types.XValue{xs("geo:-1.9441,30.0619"), xn("-1.95"), xn("30.06"), xn("5")}, resultWithExtra(xs("-1.9441,30.0619"), types.NewXObject(map[string]types.XValue{"distance": xn("0.689")}))},
+	{"has_location_within", dmy, []types.XValue{xs("Here I am\n-1.9441,30.0619"), xs("-1.95"), xs("30.06"), xs("1")}, resultWithExtra(xs("-1.9441,30.0619"), types.NewXObject(map[string]types.XValue{"distance": xn("0.689")}))},
+	{"has_location_within", dmy, []types.XValue{xs("geo:-1.9441,30.0619"), xn("-1.95"), xn("30.06"), xn("0.5")}, falseResult},
+	{"has_location_within", dmy, []types.XValue{xs("I'm at home"), xn("-1.95"), xn("30.06"), xn("5")}, falseResult},
+	{"has_location_within", dmy, []types.XValue{xs("geo:-1.9441,30.0619"), xn("-95"), xn("30.06"), xn("5")}, ERROR},
+	{"has_location_within", dmy, []types.XValue{xs("geo:-1.9441,30.0619"), xn("-1.95"), xs("east"), xn("5")}, ERROR},
+	{"has_location_within", dmy, []types.XValue{ERROR, xn("-1.95"), xn("30.06"), xn("5")}, ERROR},
+	{"has_location_within", dmy, []types.XValue{xs("geo:-1.9441,30.0619"), xn("-1.95"), xn("30.06")}, ERROR},
+
+	{"has_location_in", dmy, []types.XValue{xs("geo:-1.93,30.1"), xs("Rwanda > Kigali City > Gasabo")}, result(xs("Rwanda > Kigali City > Gasabo"))},
+	{"has_location_in", dmy, []types.XValue{xs("-1.93,30.1"), xs("RWANDA > KIGALI CITY > GASABO")}, result(xs("Rwanda > Kigali City > Gasabo"))},
+	{"has_location_in", dmy, []types.XValue{xs("geo:-1.95,30.04"), xs("Rwanda > Kigali City > Gasabo")}, falseResult},
+	{"has_location_in", dmy, []types.XValue{xs("geo:-1.93,30.1"), xs("Rwanda > Kigali City")}, result(xs("Rwanda > Kigali City"))}, // from descendant boundary
+	{"has_location_in", dmy, []types.XValue{xs("geo:-1.95,30.04"), xs("Rwanda > Kigali City")}, falseResult},
+	{"has_location_in", dmy, []types.XValue{xs("geo:-1.93,30.1"), xs("Kigali > Gasabo")}, result(xs("Rwanda > Kigali City > Gasabo"))},
+	{"has_location_in", dmy, []types.XValue{xs("geo:-1.93,30.1"), xs("kigari > gasabo > gisozi")}, result(xs("Rwanda > Kigali City > Gasabo > Gisozi"))},
+	{"has_location_in", dmy, []types.XValue{xs("geo:-1.93,30.1"), xs("Gasabo")}, result(xs("Rwanda > Kigali City > Gasabo"))},
+	{"has_location_in", dmy, []types.XValue{xs("geo:-1.93,30.1"), xs("Kigali > Nyarugenge")}, falseResult}, // no boundaries
+	{"has_location_in", dmy, []types.XValue{xs("geo:-1.93,30.1"), xs("Gasabo > Kigali")}, falseResult},
+	{"has_location_in", dmy, []types.XValue{xs("geo:-1.93,30.1"), xs("Rwanda > Boston")}, falseResult},
+	{"has_location_in", dmy, []types.XValue{xs("Gasabo"), xs("Rwanda > Kigali City > Gasabo")}, falseResult},
+	{"has_location_in", dmy, []types.XValue{ERROR, xs("Rwanda > Kigali City > Gasabo")}, ERROR},
+
 	{
 		"has_category",
 		dmy,
@@ -0,0 +1,447 @@
+package routers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/nyaruka/gocommon/i18n"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/goflow/assets"
+	"github.com/nyaruka/goflow/core"
+	"github.com/nyaruka/goflow/core/events"
+	"github.com/nyaruka/goflow/envs"
+	"github.com/nyaruka/goflow/excellent/types"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/flows/routers/cases"
+	"github.com/nyaruka/goflow/utils"
+)
+
+func init() {
+	registerType(TypeCommand, func() flows.Router { return &Command{} })
+}
+
+// TypeCommand is the constant for our command router
+const TypeCommand string = "command"
+
+// CommandArgumentType is the type of a command argument
+type CommandArgumentType string
+
+// possible types of command arguments
+const (
+	CommandArgumentTypeText     CommandArgumentType = "text"
+	CommandArgumentTypeNumber   CommandArgumentType = "number"
+	CommandArgumentTypeDate     CommandArgumentType = "date"
+	CommandArgumentTypeLocation CommandArgumentType = "location"
+	CommandArgumentTypeEnum     CommandArgumentType = "enum"
+)
+
+// the tests used to parse each type of argument, and for locations, each level
+var commandArgumentTests = map[CommandArgumentType]string{
+	CommandArgumentTypeText:   "has_text",
+	CommandArgumentTypeNumber: "has_number",
+	CommandArgumentTypeDate:   "has_date",
+}
+var commandLocationTests = map[string]string{
+	"state":    "has_state",
+	"district": "has_district",
+	"ward":     "has_ward",
+}
+
+// CommandArgument is an argument of a command which is saved as a result with its name
+type CommandArgument struct {
+	Name         string              `json:"name"                    validate:"required,result_name"`
+	Type         CommandArgumentType `json:"type"                    validate:"required,eq=text|eq=number|eq=date|eq=location|eq=enum"`
+	Level        string              `json:"level,omitempty"         validate:"omitempty,eq=state|eq=district|eq=ward"`
+	Options      []string            `json:"options,omitempty"       validate:"max=100,dive,max=100"`
+	CategoryUUID flows.CategoryUUID  `json:"category_uuid,omitempty" validate:"omitempty,uuid"`
+}
+
+// NewCommandArgument creates a new command argument
+func NewCommandArgument(name string, type_ CommandArgumentType, level string, options []string, categoryUUID flows.CategoryUUID) *CommandArgument {
+	return &CommandArgument{Name: name, Type: type_, Level: level, Options: options, CategoryUUID: categoryUUID}
+}
+
+func (a *CommandArgument) validate(r *Command) error {
+	if a.CategoryUUID != "" && !r.isValidCategory(a.CategoryUUID) {
+		return fmt.Errorf("category %s is not a valid category", a.CategoryUUID)
+	}
+	if a.Type == CommandArgumentTypeLocation && a.Level == "" {
+		return errors.New("location arguments must have a level")
+	}
+	if a.Type == CommandArgumentTypeEnum && len(a.Options) == 0 {
+		return errors.New("enum arguments must have options")
+	}
+	return nil
+}
+
+// parses the given input as a value of this argument, returning the value and a key to compare it with other values
+// of this argument, or empty strings if it isn't valid
+func (a *CommandArgument) parse(ctx context.Context, env envs.Environment, input string) (string, string, *types.XError) {
+	if a.Type == CommandArgumentTypeEnum {
+		for _, option := range a.Options {
+			if envs.CollateEquals(env, input, option) {
+				return option, option, nil
+			}
+		}
+		return "", "", nil
+	}
+
+	test := commandArgumentTests[a.Type]
+	if a.Type == CommandArgumentTypeLocation {
+		test = commandLocationTests[a.Level]
+	}
+
+	result := cases.XTESTS[test].Call(ctx, env, []types.XValue{types.NewXText(input)})
+
+	switch typed := result.(type) {
+	case *types.XError:
+		return "", "", types.NewXErrorf("Error calling test %s: %s", strings.ToUpper(test), typed.Error())
+	case *types.XObject:
+		if typed.Truthy() {
+			match, _ := typed.Get("match")
+			asText, _ := types.ToXText(env, match)
+
+			// dates are matched with the current time of day so compare them by their date
+			if asDateTime, isDateTime := match.(*types.XDateTime); isDateTime {
+				return asText.Native(), dates.ExtractDate(asDateTime.Native()).String(), nil
+			}
+			return asText.Native(), asText.Native(), nil
+		}
+	}
+	return "", "", nil
+}
+
+// Command is a router which parses its operand as a command, i.e. a keyword followed by arguments separated by
+// spaces, e.g. "REPORT MALARIA 5 KIGALI CITY". The last argument takes the rest of the text, text arguments before
+// it take a single word, and other arguments take as many words as their test matches, up to 5, e.g. a date like
+// "15 01 2024" or a location like "KIGALI CITY", preferring spans where every word counts towards the match. If the
+// command is valid it takes the success category and each argument is saved as a result. If an argument is missing
+// or invalid, it takes that argument's category, or the default category if it doesn't have one. If the text
+// doesn't start with the keyword, it takes the default category.
+type Command struct {
+	baseRouter
+
+	operand             string
+	keyword             string
+	arguments           []*CommandArgument
+	successCategoryUUID flows.CategoryUUID
+	defaultCategoryUUID flows.CategoryUUID
+}
+
+// NewCommand creates a new command router
+func NewCommand(wait flows.Wait, resultName string, categories []flows.Category, operand, keyword string, arguments []*CommandArgument, successCategoryUUID, defaultCategoryUUID flows.CategoryUUID) *Command {
+	return &Command{
+		baseRouter:          newBaseRouter(TypeCommand, wait, resultName, categories),
+		operand:             operand,
+		keyword:             keyword,
+		arguments:           arguments,
+		successCategoryUUID: successCategoryUUID,
+		defaultCategoryUUID: defaultCategoryUUID,
+	}
+}
+
+// Keyword returns the keyword of this command
+func (r *Command) Keyword() string { return r.keyword }
+
+// Arguments returns the arguments of this command
+func (r *Command) Arguments() []*CommandArgument { return r.arguments }
+
+// Validate validates the arguments for this router
+func (r *Command) Validate(flow flows.Flow, exits []flows.Exit) error {
+	if !r.isValidCategory(r.successCategoryUUID) {
+		return fmt.Errorf("success category %s is not a valid category", r.successCategoryUUID)
+	}
+	if r.defaultCategoryUUID != "" && !r.isValidCategory(r.defaultCategoryUUID) {
+		return fmt.Errorf("default category %s is not a valid category", r.defaultCategoryUUID)
+	}
+
+	names := make(map[string]bool, len(r.arguments))
+	for _, a := range r.arguments {
+		if err := a.validate(r); err != nil {
+			return fmt.Errorf("invalid argument[name=%s]: %s", a.Name, err)
+		}
+
+		key := utils.Snakify(a.Name)
+		if names[key] {
+			return fmt.Errorf("duplicate argument name %s", a.Name)
+		}
+		names[key] = true
+	}
+
+	return r.validate(flow, exits)
+}
+
+// Route determines which exit to take from a node
+func (r *Command) Route(ctx context.Context, run flows.Run, step flows.Step, log events.EventLogger) (flows.ExitUUID, string, error) {
+	env := run.Session().MergedEnvironment()
+
+	// first evaluate our operand
+	operand, _ := run.EvaluateTemplateValue(ctx, r.operand, log)
+
+	var operandAsStr string
+
+	if operand != nil {
+		asText, _ := types.ToXText(env, operand)
+		operandAsStr = asText.Native()
+	}
+
+	categoryUUID, values := r.parse(ctx, env, operandAsStr, log)
+
+	var extra *types.XObject
+	if len(values) > 0 {
+		props := make(map[string]types.XValue, len(values))
+		for i, value := range values {
+			props[utils.Snakify(r.arguments[i].Name)] = types.NewXText(value)
+		}
+		extra = types.NewXObject(props)
+	}
+
+	exit, err := r.routeToCategory(run, step, categoryUUID, strings.TrimSpace(operandAsStr), operandAsStr, extra, log)
+	if err != nil {
+		return "", "", err
+	}
+
+	// save each valid argument as a result
+	for i, value := range values {
+		result := core.NewResult(r.arguments[i].Name, value, "", "", step.NodeUUID(), operandAsStr, nil, dates.Now())
+		prev, changed := run.SetResult(result)
+		if changed {
+			log(events.NewRunResultChanged(result, prev))
+		}
+	}
+
+	return exit, operandAsStr, nil
+}
+
+// parses the given text as this command, returning the category to route to and the values of the valid arguments
+func (r *Command) parse(ctx context.Context, env envs.Environment, text string, log events.EventLogger) (flows.CategoryUUID, []string) {
+	words := strings.Fields(text)
+	if len(words) == 0 || !envs.CollateEquals(env, words[0], r.keyword) {
+		return r.defaultCategoryUUID, nil
+	}
+
+	p := &commandParser{
+		ctx:       ctx,
+		env:       env,
+		arguments: r.arguments,
+		words:     words[1:],
+		parses:    make(map[[2]int]commandParse),
+		matches:   make(map[[3]int]commandMatch),
+		errors:    make(map[string]bool),
+	}
+	values, failed := p.parse(0, 0)
+
+	// log each distinct error from calling tests, as the same span of words can be tried more than once
+	for _, e := range p.errorsInOrder {
+		log(events.NewError(e, ""))
+	}
+
+	if failed < len(r.arguments) {
+		if r.arguments[failed].CategoryUUID != "" {
+			return r.arguments[failed].CategoryUUID, values
+		}
+		return r.defaultCategoryUUID, values
+	}
+
+	return r.successCategoryUUID, values
+}
+
+// the most words that an argument before the last can take, which also limits how many ways a command can be split
+const maxCommandArgumentWords = 5
+
+// parses the arguments of a command, backtracking when an argument can take different numbers of words. Results are
+// memoized by position so that each argument is only parsed once at each word and each test is only called once for
+// each span of words.
+type commandParser struct {
+	ctx           context.Context
+	env           envs.Environment
+	arguments     []*CommandArgument
+	words         []string
+	parses        map[[2]int]commandParse
+	matches       map[[3]int]commandMatch
+	errors        map[string]bool
+	errorsInOrder []string
+}
+
+type commandParse struct {
+	values []string
+	failed int
+}
+
+type commandMatch struct {
+	value string
+	key   string
+}
+
+// parses the arguments from arg onwards starting at the given word, returning the values of the valid arguments and
+// the index of the argument which failed, or the number of arguments if none did
+func (p *commandParser) parse(arg, word int) ([]string, int) {
+	if arg == len(p.arguments) {
+		return []string{}, arg
+	}
+	if parsed, seen := p.parses[[2]int{arg, word}]; seen {
+		return parsed.values, parsed.failed
+	}
+
+	var bestValues []string
+	bestFailed := arg
+
+	for _, span := range p.spans(arg, word) {
+		values, failed := p.parse(arg+1, word+span.words)
+		values = append([]string{span.value}, values...)
+
+		if failed == len(p.arguments) {
+			bestValues, bestFailed = values, failed
+			break
+		}
+
+		// if nothing works, report the attempt that got furthest
+		if bestValues == nil || failed > bestFailed {
+			bestValues, bestFailed = values, failed
+		}
+	}
+
+	if bestValues == nil {
+		bestValues = []string{}
+	}
+
+	p.parses[[2]int{arg, word}] = commandParse{bestValues, bestFailed}
+	return bestValues, bestFailed
+}
+
+type commandSpan struct {
+	value string
+	words int
+}
+
+// finds the valid values of the given argument starting at the given word. Spans where every word counts towards the
+// match come first, longest first, followed by spans with words that don't, shortest first.
+func (p *commandParser) spans(arg, word int) []commandSpan {
+	start := min(word, len(p.words))
+	remaining := len(p.words) - start
+
+	// the last argument takes the rest of the text
+	if arg == len(p.arguments)-1 {
+		if value, _ := p.match(arg, start, len(p.words)); value != "" {
+			return []commandSpan{{value, remaining}}
+		}
+		return nil
+	}
+
+	// other arguments leave at least a word for each argument after them, and text arguments take a single word
+	maxWords := min(max(remaining-(len(p.arguments)-arg-1), 1), maxCommandArgumentWords)
+	if p.arguments[arg].Type == CommandArgumentTypeText {
+		maxWords = 1
+	}
+
+	tight := make([]commandSpan, 0, 1)
+	loose := make([]commandSpan, 0)
+
+	for n := min(maxWords, remaining); n > 0; n-- {
+		value, key := p.match(arg, start, start+n)
+		if value == "" {
+			continue
+		}
+
+		// a word counts towards the match if dropping it changes the match
+		if n > 1 {
+			_, withoutFirst := p.match(arg, start+1, start+n)
+			_, withoutLast := p.match(arg, start, start+n-1)
+
+			if withoutFirst == key || withoutLast == key {
+				loose = append([]commandSpan{{value, n}}, loose...)
+				continue
+			}
+		}
+
+		tight = append(tight, commandSpan{value, n})
+	}
+	return append(tight, loose...)
+}
+
+// matches the words from start to end against the given argument
+func (p *commandParser) match(arg, start, end int) (string, string) {
+	if start >= end {
+		return "", ""
+	}
+	if m, seen := p.matches[[3]int{arg, start, end}]; seen {
+		return m.value, m.key
+	}
+
+	value, key, xerr := p.arguments[arg].parse(p.ctx, p.env, strings.Join(p.words[start:end], " "))
+	if xerr != nil && !p.errors[xerr.Error()] {
+		p.errors[xerr.Error()] = true
+		p.errorsInOrder = append(p.errorsInOrder, xerr.Error())
+	}
+
+	p.matches[[3]int{arg, start, end}] = commandMatch{value, key}
+	return value, key
+}
+
+func (r *Command) Inspect(result func(*flows.ResultInfo), dependency func(assets.Reference), local func(string)) {
+	r.baseRouter.Inspect(result, dependency, local)
+
+	for _, a := range r.arguments {
+		result(flows.NewResultInfo(a.Name, []string{}))
+	}
+}
+
+// EnumerateTemplates enumerates all expressions on this object and its children
+func (r *Command) EnumerateTemplates(localization flows.Localization, include func(i18n.Language, string)) {
+	include(i18n.NilLanguage, r.operand)
+
+	r.baseRouter.EnumerateTemplates(localization, include)
+}
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+type commandEnvelope struct {
+	baseEnvelope
+
+	Operand             string             `json:"operand"                         validate:"required,max=10000"`
+	Keyword             string             `json:"keyword"                         validate:"required,max=64"`
+	Arguments           []*CommandArgument `json:"arguments"                       validate:"max=10,dive"`
+	SuccessCategoryUUID flows.CategoryUUID `json:"success_category_uuid"           validate:"required,uuid"`
+	DefaultCategoryUUID flows.CategoryUUID `json:"default_category_uuid,omitempty" validate:"omitempty,uuid"`
+}
+
+func (r *Command) UnmarshalJSON(data []byte) error {
+	e := &commandEnvelope{}
+	if err := utils.UnmarshalAndValidate(data, e); err != nil {
+		return err
+	}
+
+	r.operand = e.Operand
+	r.keyword = e.Keyword
+	r.arguments = e.Arguments
+	r.successCategoryUUID = e.SuccessCategoryUUID
+	r.defaultCategoryUUID = e.DefaultCategoryUUID
+
+	if err := r.unmarshal(&e.baseEnvelope); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalJSON marshals this router into JSON
+func (r *Command) MarshalJSON() ([]byte, error) {
+	e := &commandEnvelope{
+		Operand:             r.operand,
+		Keyword:             r.keyword,
+		Arguments:           r.arguments,
+		SuccessCategoryUUID: r.successCategoryUUID,
+		DefaultCategoryUUID: r.defaultCategoryUUID,
+	}
+
+	if err := r.marshal(&e.baseEnvelope); err != nil {
+		return nil, err
+	}
+
+	return jsonx.Marshal(e)
+}
@@ -0,0 +1,87 @@
+package routers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/goflow/core/events"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/utils"
+)
+
+func init() {
+	registerType(TypeFork, func() flows.Router { return &Fork{} })
+}
+
+// TypeFork is the type for a fork router
+const TypeFork string = "fork"
+
+// Fork is a router which starts a branch for each of its categories. Branches are executed one after the other by the
+// same run, each continuing until it reaches a join node, after which the next branch is started. Once all branches
+// have reached the join node (or just one, depending on the mode of the join), the run continues from the join node.
+type Fork struct {
+	baseRouter
+}
+
+// NewFork creates a new fork router
+func NewFork(categories []flows.Category) *Fork {
+	return &Fork{newBaseRouter(TypeFork, nil, "", categories)}
+}
+
+// Validate validates that the fields on this router are valid
+func (r *Fork) Validate(flow flows.Flow, exits []flows.Exit) error {
+	if r.wait != nil {
+		return errors.New("fork routers can't have a wait")
+	}
+	if r.resultName != "" {
+		return errors.New("fork routers can't save a result")
+	}
+
+	return r.validate(flow, exits)
+}
+
+// BranchExits returns the exits of each branch in the order they should be executed
+func (r *Fork) BranchExits() []flows.ExitUUID {
+	exits := make([]flows.ExitUUID, len(r.categories))
+	for i, c := range r.categories {
+		exits[i] = c.ExitUUID()
+	}
+	return exits
+}
+
+// Route determines which exit to take from a node, which for a fork is always the first branch
+func (r *Fork) Route(ctx context.Context, run flows.Run, step flows.Step, logEvent events.EventLogger) (flows.ExitUUID, string, error) {
+	exit, err := r.routeToCategory(run, step, r.categories[0].UUID(), "", "", nil, logEvent)
+	return exit, "", err
+}
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+func (r *Fork) UnmarshalJSON(data []byte) error {
+	e := &baseEnvelope{}
+	if err := utils.UnmarshalAndValidate(data, e); err != nil {
+		return err
+	}
+
+	if err := r.unmarshal(e); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalJSON marshals this router into JSON
+func (r *Fork) MarshalJSON() ([]byte, error) {
+	e := &baseEnvelope{}
+
+	if err := r.marshal(e); err != nil {
+		return nil, err
+	}
+
+	return jsonx.Marshal(e)
+}
+
+var _ flows.ForkRouter = (*Fork)(nil)
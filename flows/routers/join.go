@@ -0,0 +1,112 @@
+package routers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/goflow/core/events"
+	"github.com/nyaruka/goflow/flows"
+	"github.com/nyaruka/goflow/utils"
+)
+
+func init() {
+	registerType(TypeJoin, func() flows.Router { return &Join{} })
+
+	utils.RegisterValidatorAlias("join_mode", "eq=all|eq=any", func(validator.FieldError) string {
+		return "is not a valid join mode"
+	})
+}
+
+// TypeJoin is the type for a join router
+const TypeJoin string = "join"
+
+// JoinMode is the mode of a join router
+type JoinMode string
+
+// possible join modes
+const (
+	JoinModeAll JoinMode = "all"
+	JoinModeAny JoinMode = "any"
+)
+
+// Join is a router which ends the branches started by the most recent fork router in the run. In `all` mode, the run
+// only continues from the join once every branch has been executed. In `any` mode, the run continues from the join as
+// soon as one branch reaches it and the remaining branches are skipped. A branch which ends without reaching the join
+// is followed by the next pending branch.
+type Join struct {
+	baseRouter
+
+	mode JoinMode
+}
+
+// NewJoin creates a new join router
+func NewJoin(mode JoinMode, category flows.Category) *Join {
+	return &Join{baseRouter: newBaseRouter(TypeJoin, nil, "", []flows.Category{category}), mode: mode}
+}
+
+// Mode returns the mode of this join
+func (r *Join) Mode() JoinMode { return r.mode }
+
+// JoinsAny returns whether this join continues as soon as one branch reaches it
+func (r *Join) JoinsAny() bool { return r.mode == JoinModeAny }
+
+// Validate validates that the fields on this router are valid
+func (r *Join) Validate(flow flows.Flow, exits []flows.Exit) error {
+	if r.wait != nil {
+		return errors.New("join routers can't have a wait")
+	}
+	if r.resultName != "" {
+		return errors.New("join routers can't save a result")
+	}
+	if len(r.categories) != 1 {
+		return errors.New("join routers must have exactly one category")
+	}
+
+	return r.validate(flow, exits)
+}
+
+// Route determines which exit to take from a node once all necessary branches are done
+func (r *Join) Route(ctx context.Context, run flows.Run, step flows.Step, logEvent events.EventLogger) (flows.ExitUUID, string, error) {
+	exit, err := r.routeToCategory(run, step, r.categories[0].UUID(), "", "", nil, logEvent)
+	return exit, "", err
+}
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+type joinEnvelope struct {
+	baseEnvelope
+
+	Mode JoinMode `json:"mode" validate:"required,join_mode"`
+}
+
+func (r *Join) UnmarshalJSON(data []byte) error {
+	e := &joinEnvelope{}
+	if err := utils.UnmarshalAndValidate(data, e); err != nil {
+		return err
+	}
+
+	r.mode = e.Mode
+
+	if err := r.unmarshal(&e.baseEnvelope); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalJSON marshals this router into JSON
+func (r *Join) MarshalJSON() ([]byte, error) {
+	e := &joinEnvelope{Mode: r.mode}
+
+	if err := r.marshal(&e.baseEnvelope); err != nil {
+		return nil, err
+	}
+
+	return jsonx.Marshal(e)
+}
+
+var _ flows.JoinRouter = (*Join)(nil)
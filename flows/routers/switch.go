@@ -24,12 +24,14 @@ func init() {
 // TypeSwitch is the constant for our switch router
 const TypeSwitch string = "switch"
 
-// Case represents a single case and test in our switch
+// Case represents a single case and test in our switch. Cases which test for a pattern can have captures which
+// save named groups of the pattern as results or locals.
 type Case struct {
 	UUID         flows.CaseUUID     `json:"uuid"                   validate:"required,uuid"`
 	Type         string             `json:"type"                   validate:"required"`
 	Arguments    []string           `json:"arguments,omitempty"    validate:"dive,max=10000" engine:"localized,evaluated"`
 	CategoryUUID flows.CategoryUUID `json:"category_uuid"          validate:"required"`
+	Captures     []*flows.Capture   `json:"captures,omitempty"     validate:"omitempty,dive"`
 }
 
 // NewCase creates a new case
@@ -55,6 +57,16 @@ func (c *Case) validate(r *Switch) error {
 		return fmt.Errorf("can't have more than %d arguments (has %d)", flows.MaxArgumentsPerCase, len(c.Arguments))
 	}
 
+	if len(c.Captures) > 0 && c.Type != "has_pattern" {
+		return fmt.Errorf("captures can only be used with has_pattern tests")
+	}
+
+	for _, capture := range c.Captures {
+		if err := capture.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -119,7 +131,7 @@ func (r *Switch) Route(ctx context.Context, run flows.Run, step flows.Step, log
 	}
 
 	// find first matching case
-	match, categoryUUID, extra, err := r.matchCase(ctx, run, operand, log)
+	matched, match, categoryUUID, extra, err := r.matchCase(ctx, run, operand, log)
 	if err != nil {
 		return "", "", err
 	}
@@ -137,17 +149,35 @@ func (r *Switch) Route(ctx context.Context, run flows.Run, step flows.Step, log
 	}
 
 	exit, err := r.routeToCategory(run, step, categoryUUID, match, operandAsStr, extra, log)
-	return exit, operandAsStr, err
+	if err != nil {
+		return "", "", err
+	}
+
+	if matched != nil && len(matched.Captures) > 0 {
+		r.saveCaptures(run, step, matched, operandAsStr, extra, log)
+	}
+
+	return exit, operandAsStr, nil
+}
+
+// saves the named groups of a matched pattern as results or locals
+func (r *Switch) saveCaptures(run flows.Run, step flows.Step, c *Case, operand string, groups *types.XObject, log events.EventLogger) {
+	category := r.findCategory(c.CategoryUUID)
+	localizedCategory, _ := run.GetText(uuids.UUID(category.UUID()), "name", "")
+
+	for _, capture := range c.Captures {
+		capture.Apply(run.Session().MergedEnvironment(), run, step, groups, category.Name(), localizedCategory, operand, log)
+	}
 }
 
-func (r *Switch) matchCase(ctx context.Context, run flows.Run, operand types.XValue, log events.EventLogger) (string, flows.CategoryUUID, *types.XObject, error) {
+func (r *Switch) matchCase(ctx context.Context, run flows.Run, operand types.XValue, log events.EventLogger) (*Case, string, flows.CategoryUUID, *types.XObject, error) {
 	for _, c := range r.cases {
 		test := strings.ToLower(c.Type)
 
 		// try to look up our function
 		xtest := cases.XTESTS[test]
 		if xtest == nil {
-			return "", "", nil, fmt.Errorf("unknown case test '%s'", c.Type)
+			return nil, "", "", nil, fmt.Errorf("unknown case test '%s'", c.Type)
 		}
 
 		// build our argument list which starts with the operand
@@ -189,21 +219,29 @@ func (r *Switch) matchCase(ctx context.Context, run flows.Run, operand types.XVa
 
 			resultAsStr, xerr := types.ToXText(run.Session().MergedEnvironment(), match)
 			if xerr != nil {
-				return "", "", nil, xerr
+				return nil, "", "", nil, xerr
 			}
 
-			return resultAsStr.Native(), c.CategoryUUID, extraAsObject, nil
+			return c, resultAsStr.Native(), c.CategoryUUID, extraAsObject, nil
 		default:
 			panic(fmt.Sprintf("unexpected result type from test %v: %#v", xtest, result))
 		}
 	}
-	return "", "", nil, nil
+	return nil, "", "", nil, nil
 }
 
-func (r *Switch) Inspect(result func(*flows.ResultInfo), dependency func(assets.Reference)) {
-	r.baseRouter.Inspect(result, dependency)
+func (r *Switch) Inspect(result func(*flows.ResultInfo), dependency func(assets.Reference), local func(string)) {
+	r.baseRouter.Inspect(result, dependency, local)
 
 	for _, c := range r.cases {
+		if len(c.Captures) > 0 {
+			categoryNames := []string{r.findCategory(c.CategoryUUID).Name()}
+
+			for _, capture := range c.Captures {
+				capture.Inspect(categoryNames, local, result)
+			}
+		}
+
 		// currently only the HAS_GROUP router test can produce a dependency
 		if c.Type == "has_group" && len(c.Arguments) > 0 {
 			// if we have two args, the second is name
@@ -3,6 +3,7 @@ package waits
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/nyaruka/gocommon/dates"
@@ -55,6 +56,20 @@ func (w *baseWait) Timeout() flows.Timeout {
 	return w.timeout
 }
 
+// returns the number of seconds until this wait should time out, extended if necessary so that it doesn't time out
+// outside of the send windows which apply to the run
+func (w *baseWait) timeoutSeconds(run flows.Run) int {
+	if len(flows.SendWindows(run)) == 0 {
+		return w.timeout.Seconds()
+	}
+
+	now := dates.Now()
+	timeoutOn := now.Add(time.Duration(w.timeout.Seconds()) * time.Second)
+	timeoutOn = flows.NextSendTime(run, timeoutOn)
+
+	return int(math.Ceil(timeoutOn.Sub(now).Seconds()))
+}
+
 func (w *baseWait) expiresOn(run flows.Run) time.Time {
 	return dates.Now().Add(run.Flow().ExpireAfter())
 }
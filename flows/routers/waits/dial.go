@@ -82,7 +82,7 @@ func (w *Dial) Begin(ctx context.Context, run flows.Run, log events.EventLogger)
 }
 
 // Accept returns whether this wait accepts the given resume
-func (w *Dial) Accepts(resume flows.Resume) bool {
+func (w *Dial) Accepts(run flows.Run, resume flows.Resume) bool {
 	return resume.Type() == resumes.TypeDial
 }
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/goflow/core"
 	"github.com/nyaruka/goflow/core/events"
 	"github.com/nyaruka/goflow/core/hints"
 	"github.com/nyaruka/goflow/flows"
@@ -26,9 +27,9 @@ type Msg struct {
 	baseWait
 
 	// Message waits can indicate to the caller what kind of message the flow is expecting. In the case of flows of type
-	// messaging_offline, this should be considered a requirement and the client should only reply with a message containing
-	// an attachment of that type. In the case of other flow types this should be considered only a hint to the channel,
-	// which may or may not support prompting the contact for media of that type.
+	// messaging_offline, this is a requirement and a message without an attachment of that type, or which doesn't satisfy
+	// the hint's content type and size limits, will be rejected. In the case of other flow types this should be considered
+	// only a hint to the channel, which may or may not support prompting the contact for media of that type.
 	hint hints.Hint
 }
 
@@ -59,7 +60,7 @@ func (w *Msg) Begin(ctx context.Context, run flows.Run, log events.EventLogger)
 
 	var timeoutSeconds *int
 	if w.timeout != nil {
-		seconds := w.timeout.Seconds()
+		seconds := w.timeoutSeconds(run)
 		timeoutSeconds = &seconds
 	}
 
@@ -69,9 +70,11 @@ func (w *Msg) Begin(ctx context.Context, run flows.Run, log events.EventLogger)
 }
 
 // Accept returns whether this wait accepts the given resume
-func (w *Msg) Accepts(resume flows.Resume) bool {
+func (w *Msg) Accepts(run flows.Run, resume flows.Resume) bool {
 	switch resume.Type() {
-	case resumes.TypeMsg, resumes.TypeWaitExpiration:
+	case resumes.TypeMsg:
+		return w.acceptsMsg(run, resume.Event().(*events.MsgReceived).Msg)
+	case resumes.TypeWaitExpiration:
 		return true
 	case resumes.TypeWaitTimeout:
 		return w.timeout != nil
@@ -79,6 +82,21 @@ func (w *Msg) Accepts(resume flows.Resume) bool {
 	return false
 }
 
+// offline flows enforce media hints by only accepting messages with a matching attachment
+func (w *Msg) acceptsMsg(run flows.Run, msg *core.MsgIn) bool {
+	hint, isMedia := w.hint.(hints.MediaHint)
+	if !isMedia || run.Flow().Type() != flows.FlowTypeMessagingOffline {
+		return true
+	}
+
+	for _, a := range msg.Attachments() {
+		if hint.Accepts(a) {
+			return true
+		}
+	}
+	return false
+}
+
 var _ flows.Wait = (*Msg)(nil)
 
 //------------------------------------------------------------------------------------------
@@ -2,15 +2,20 @@ package waits_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/nyaruka/gocommon/dates"
 	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/urns"
 	"github.com/nyaruka/goflow/core"
 	"github.com/nyaruka/goflow/core/events"
 	"github.com/nyaruka/goflow/core/hints"
+	"github.com/nyaruka/goflow/envs"
 	"github.com/nyaruka/goflow/flows"
 	"github.com/nyaruka/goflow/flows/resumes"
 	"github.com/nyaruka/goflow/flows/routers/waits"
 	"github.com/nyaruka/goflow/test"
+	"github.com/nyaruka/goflow/utils"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -64,7 +69,7 @@ func TestMsgWait(t *testing.T) {
 	assert.Equal(t, `{"type":"msg"}`, string(marshaled))
 
 	// try to end with timeout resume type
-	assert.False(t, wait.Accepts(resumes.NewWaitTimeout(events.NewWaitTimedOut())))
+	assert.False(t, wait.Accepts(run, resumes.NewWaitTimeout(events.NewWaitTimedOut())))
 
 	// timeout and image hint
 	wait = waits.NewMsg(
@@ -86,10 +91,10 @@ func TestMsgWait(t *testing.T) {
 	assert.Equal(t, "msg_wait", log.Events[0].Type())
 
 	// try to end with incorrect resume type
-	assert.False(t, wait.Accepts(resumes.NewDial(events.NewDialEnded(core.NewDial(core.DialStatusBusy, 0)))))
+	assert.False(t, wait.Accepts(run, resumes.NewDial(events.NewDialEnded(core.NewDial(core.DialStatusBusy, 0)))))
 
 	// can end with timeout resume type
-	assert.True(t, wait.Accepts(resumes.NewWaitTimeout(events.NewWaitTimedOut())))
+	assert.True(t, wait.Accepts(run, resumes.NewWaitTimeout(events.NewWaitTimedOut())))
 }
 
 func TestMsgWaitSkipIfInitial(t *testing.T) {
@@ -114,3 +119,154 @@ func TestMsgWaitSkipIfInitial(t *testing.T) {
 	assert.Equal(t, "run_started", sprint.Events()[0].Type())
 	assert.Equal(t, "run_ended", sprint.Events()[1].Type())
 }
+
+var timeoutWaitJSON = `{
+	"flows": [
+		{
+			"uuid": "615b8a0f-588c-4d20-a05f-363b0b4ce6f4",
+			"name": "Timeout Wait",
+			"spec_version": "14.4.2",
+			"language": "eng",
+			"type": "messaging",
+			"nodes": [
+				{
+					"uuid": "46d51f50-58de-49da-8d13-dadbf322685d",
+					"router": {
+						"type": "switch",
+						"wait": {
+							"type": "msg",
+							"timeout": {"seconds": 600, "category_uuid": "9b7f1c5b-4c0e-4b3a-93d9-1c1d4e3a2f3a"}
+						},
+						"categories": [
+							{
+								"uuid": "c82e161f-fa2d-4e7d-a338-c27f6c349445",
+								"name": "All Responses",
+								"exit_uuid": "598ae7a5-2f81-48f1-afac-595262514aa1"
+							},
+							{
+								"uuid": "9b7f1c5b-4c0e-4b3a-93d9-1c1d4e3a2f3a",
+								"name": "No Response",
+								"exit_uuid": "c1a4c9a1-5c1e-4d0a-9d0e-7e1c2b3a4d5e"
+							}
+						],
+						"operand": "@input.text",
+						"default_category_uuid": "c82e161f-fa2d-4e7d-a338-c27f6c349445"
+					},
+					"exits": [
+						{"uuid": "598ae7a5-2f81-48f1-afac-595262514aa1"},
+						{"uuid": "c1a4c9a1-5c1e-4d0a-9d0e-7e1c2b3a4d5e"}
+					]
+				}
+			]
+		}
+	]
+}`
+
+func TestMsgWaitTimeoutInSendWindow(t *testing.T) {
+	defer dates.SetNowFunc(time.Now)
+
+	env := envs.NewBuilder().
+		WithSendWindows(envs.SendWindows{envs.NewSendWindow(nil, dates.NewTimeOfDay(8, 0, 0, 0), dates.NewTimeOfDay(20, 0, 0, 0))}).
+		Build()
+
+	tcs := []struct {
+		now             time.Time
+		expectedTimeout int
+	}{
+		{time.Date(2025, 5, 4, 12, 0, 0, 0, time.UTC), 600},    // timeout within window
+		{time.Date(2025, 5, 4, 19, 55, 0, 0, time.UTC), 43500}, // timeout after window closes so moved to next morning
+		{time.Date(2025, 5, 4, 22, 0, 0, 0, time.UTC), 36000},  // wait begins outside window
+	}
+
+	for _, tc := range tcs {
+		dates.SetNowFunc(dates.NewFixedNow(tc.now))
+
+		_, session, sprint := test.NewSessionBuilder().WithAssetsJSON([]byte(timeoutWaitJSON)).
+			WithFlow("615b8a0f-588c-4d20-a05f-363b0b4ce6f4").
+			WithEnvironment(env).
+			MustBuild()
+
+		assert.Equal(t, flows.SessionStatusWaiting, session.Status())
+
+		evt := sprint.Events()[1].(*events.MsgWait)
+		if assert.NotNil(t, evt.TimeoutSeconds, "timeout missing for now=%s", tc.now) {
+			assert.Equal(t, tc.expectedTimeout, *evt.TimeoutSeconds, "timeout mismatch for now=%s", tc.now)
+		}
+	}
+}
+
+var offlineImageWaitJSON = `{
+	"flows": [
+		{
+			"uuid": "615b8a0f-588c-4d20-a05f-363b0b4ce6f4",
+			"name": "Photo Evidence",
+			"spec_version": "14.4.2",
+			"language": "eng",
+			"type": "messaging_offline",
+			"nodes": [
+				{
+					"uuid": "46d51f50-58de-49da-8d13-dadbf322685d",
+					"router": {
+						"type": "switch",
+						"wait": {
+							"type": "msg",
+							"hint": {"type": "image", "content_types": ["image/jpeg", "image/png"], "max_size": 1000000}
+						},
+						"categories": [
+							{
+								"uuid": "c82e161f-fa2d-4e7d-a338-c27f6c349445",
+								"name": "All Responses",
+								"exit_uuid": "598ae7a5-2f81-48f1-afac-595262514aa1"
+							}
+						],
+						"operand": "@input.text",
+						"default_category_uuid": "c82e161f-fa2d-4e7d-a338-c27f6c349445"
+					},
+					"exits": [
+						{"uuid": "598ae7a5-2f81-48f1-afac-595262514aa1"}
+					]
+				}
+			]
+		}
+	]
+}`
+
+func TestMsgWaitOfflineMediaHint(t *testing.T) {
+	_, session, _ := test.NewSessionBuilder().WithAssetsJSON([]byte(offlineImageWaitJSON)).
+		WithFlow("615b8a0f-588c-4d20-a05f-363b0b4ce6f4").
+		MustBuild()
+
+	require.Equal(t, flows.SessionStatusWaiting, session.Status())
+
+	run := session.Runs()[0]
+	_, node, err := run.PathLocation()
+	require.NoError(t, err)
+	wait := node.Router().Wait()
+
+	msgResume := func(attachments ...utils.Attachment) flows.Resume {
+		return resumes.NewMsg(events.NewMsgReceived(core.NewMsgIn(urns.URN("tel:+12065551212"), nil, "", attachments, "", nil), ""))
+	}
+
+	assert.False(t, wait.Accepts(run, msgResume()))
+	assert.False(t, wait.Accepts(run, msgResume("image/webp:http://example.com/sticker.webp")))
+	assert.False(t, wait.Accepts(run, msgResume("video/mp4:http://example.com/clip.mp4")))
+	assert.False(t, wait.Accepts(run, msgResume("image/jpeg;size=2000000:http://example.com/photo.jpg")))
+	assert.True(t, wait.Accepts(run, msgResume("image/webp:http://example.com/sticker.webp", "image/jpeg:http://example.com/photo.jpg")))
+	assert.True(t, wait.Accepts(run, msgResume("image/png;size=1000:http://example.com/photo.png")))
+	assert.True(t, wait.Accepts(run, resumes.NewWaitExpiration(events.NewWaitExpired())))
+
+	// a rejected resume leaves the session waiting so the caller can reprompt
+	_, err = session.Resume(t.Context(), msgResume("image/webp:http://example.com/sticker.webp"))
+	assert.EqualError(t, err, "resume of type msg not accepted by wait of type msg")
+	assert.Equal(t, flows.SessionStatusWaiting, session.Status())
+
+	_, err = session.Resume(t.Context(), msgResume("image/jpeg:http://example.com/photo.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, flows.SessionStatusCompleted, session.Status())
+
+	// in other flow types the hint isn't enforced
+	session, _, err = test.CreateTestSession("", envs.RedactionPolicyNone)
+	require.NoError(t, err)
+
+	assert.True(t, waits.NewMsg(nil, hints.NewImage()).Accepts(session.Runs()[0], msgResume()))
+}
@@ -18,11 +18,13 @@ import (
 var RunContextTopLevels = []string{
 	"child",
 	"contact",
+	"error",
 	"fields",
 	"globals",
 	"input",
 	"legacy_extra",
 	"node",
+	"params",
 	"parent",
 	"locals",
 	"results",
@@ -78,4 +80,5 @@ type Run interface {
 	ModifiedOn() time.Time
 	ExitedOn() *time.Time
 	Exit(core.RunStatus)
+	Fail(string, events.EventLogger)
 }
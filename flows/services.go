@@ -21,9 +21,22 @@ type Services interface {
 	Airtime(SessionAssets) (AirtimeService, error)
 }
 
+// Email is an email to be sent by an email service. The body is plain text and if there is an HTML body, the email
+// should be sent as a multipart message with both. Attachments are URLs of files to be attached.
+type Email struct {
+	To          []string
+	CC          []string
+	BCC         []string
+	ReplyTo     string
+	Subject     string
+	Body        string
+	HTMLBody    string
+	Attachments []string
+}
+
 // EmailService provides email functionality to the engine
 type EmailService interface {
-	Send(ctx context.Context, addresses []string, subject, body string) error
+	Send(ctx context.Context, email *Email) error
 }
 
 // WebhookService provides webhook functionality to the engine
@@ -74,7 +74,7 @@ type Session interface {
 	Trigger() Trigger
 	CurrentResume() Resume
 	BatchStart() bool
-	PushFlow(Flow, Run, bool)
+	PushFlow(Flow, Run, bool, ParamValues)
 
 	Resume(context.Context, Resume) (Sprint, error)
 	Runs() []Run
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/nyaruka/gocommon/httpx"
+	"github.com/nyaruka/gocommon/stringsx"
 	"github.com/nyaruka/goflow/envs"
 	"github.com/nyaruka/goflow/excellent/types"
 	"github.com/nyaruka/goflow/utils"
@@ -19,8 +20,8 @@ type WebhookCall struct {
 	ResponseJSON    json.RawMessage   `json:"json"`
 }
 
-// NewWebhookCall creates a new webhook call from a trace
-func NewWebhookCall(t *httpx.Trace) *WebhookCall {
+// NewWebhookCall creates a new webhook call from a trace, using the given redactor (if any) on its URL and response
+func NewWebhookCall(t *httpx.Trace, redact stringsx.Redactor) *WebhookCall {
 	respStatus := 0
 	var respHeaders map[string]string
 
@@ -44,6 +45,16 @@ func NewWebhookCall(t *httpx.Trace) *WebhookCall {
 		c.ResponseJSON = utils.ExtractJSON(t.ResponseBody)
 	}
 
+	if redact != nil {
+		c.URL = redact(c.URL)
+		for k, v := range c.ResponseHeaders {
+			c.ResponseHeaders[k] = redact(v)
+		}
+		if c.ResponseJSON != nil {
+			c.ResponseJSON = json.RawMessage(redact(string(c.ResponseJSON)))
+		}
+	}
+
 	return c
 }
 
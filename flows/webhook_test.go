@@ -36,7 +36,7 @@ func TestWebhookCall(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, trace)
 
-		return flows.NewWebhookCall(trace)
+		return flows.NewWebhookCall(trace, nil)
 	}
 
 	call1 := request("GET")
@@ -0,0 +1,122 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nyaruka/gocommon/smtpx"
+	"github.com/wneessen/go-mail"
+)
+
+// Sender is anything that can send a MIME message over SMTP
+type Sender interface {
+	Send(context.Context, *Config, *mail.Msg) error
+}
+
+type defaultSender struct{}
+
+func (s defaultSender) Send(ctx context.Context, c *Config, m *mail.Msg) error {
+	// use implicit SSL/TLS on port 465, otherwise opportunistic STARTTLS
+	opts := []mail.Option{mail.WithPort(c.Port)}
+	if c.Port == 465 {
+		opts = append(opts, mail.WithSSL())
+	} else {
+		opts = append(opts, mail.WithTLSPolicy(mail.TLSOpportunistic))
+	}
+	if c.Username != "" {
+		opts = append(opts, mail.WithSMTPAuth(mail.SMTPAuthAutoDiscover), mail.WithUsername(c.Username), mail.WithPassword(c.Password))
+	}
+
+	client, err := mail.NewClient(c.Host, opts...)
+	if err != nil {
+		return fmt.Errorf("error creating mail client: %w", err)
+	}
+	return client.DialAndSendWithContext(ctx, m)
+}
+
+// DefaultSender is the default SMTP sender
+var DefaultSender Sender = defaultSender{}
+var currentSender = DefaultSender
+
+// SetSender sets the sender used by the service
+func SetSender(sender Sender) {
+	currentSender = sender
+}
+
+// sends the given message, retrying according to the given config
+func send(ctx context.Context, c *Config, m *mail.Msg, retries *smtpx.RetryConfig) error {
+	var err error
+	retry := 0
+
+	for {
+		err = currentSender.Send(ctx, c, m)
+
+		if err != nil && retries != nil && retry < retries.MaxRetries() && retries.ShouldRetry(err) {
+			select {
+			case <-time.After(retries.Backoff(retry)):
+				retry++
+				continue
+			case <-ctx.Done():
+				return err
+			}
+		}
+		break
+	}
+
+	return err
+}
+
+// MockSender is a mocked sender for testing that just logs would-be commands
+type MockSender struct {
+	errs []error
+	logs []string
+}
+
+// NewMockSender creates a new mock sender
+func NewMockSender(errs ...error) *MockSender {
+	return &MockSender{errs: errs}
+}
+
+// Logs returns the send logs
+func (s *MockSender) Logs() []string {
+	return s.logs
+}
+
+func (s *MockSender) Send(ctx context.Context, c *Config, m *mail.Msg) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(s.errs) == 0 {
+		panic(fmt.Errorf("missing mock for send number %d", len(s.logs)))
+	}
+
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+
+	recipients, rerr := m.GetRecipients()
+	if rerr != nil {
+		return rerr
+	}
+
+	data := &bytes.Buffer{}
+	if _, werr := m.WriteTo(data); werr != nil {
+		return werr
+	}
+
+	b := &strings.Builder{}
+	b.WriteString("HELO localhost\n")
+	b.WriteString(fmt.Sprintf("MAIL FROM:<%s>\n", c.From))
+	for _, r := range recipients {
+		b.WriteString(fmt.Sprintf("RCPT TO:<%s>\n", strings.Trim(r, "<>")))
+	}
+	b.WriteString("DATA\n")
+	b.WriteString(strings.TrimSuffix(strings.ReplaceAll(data.String(), "\r\n", "\n"), "\n"))
+	b.WriteString("\n.\n")
+	b.WriteString("QUIT\n")
+
+	s.logs = append(s.logs, b.String())
+	return err
+}
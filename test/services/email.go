@@ -13,7 +13,7 @@ func NewEmail() *Email {
 	return &Email{}
 }
 
-func (s *Email) Send(ctx context.Context, addresses []string, subject, body string) error {
+func (s *Email) Send(ctx context.Context, email *flows.Email) error {
 	return nil
 }
 
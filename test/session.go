@@ -170,6 +170,7 @@ var sessionAssets = `{
             "spec_version": "13.0.0",
             "language": "eng",
             "type": "messaging",
+            "params": [{"name": "min_age", "type": "number", "default": "18"}],
             "nodes": [{
                 "uuid": "d9dba561-b5ee-4f62-ba44-60c4dc242b84",
                 "actions": [
@@ -243,9 +244,12 @@ var sessionAssets = `{
                 {
                     "name": "Kigali City",
                     "aliases": ["Kigali", "Kigari"],
+                    "boundary": {"type": "Polygon", "coordinates": [[[29.95, -2.1], [30.3, -2.1], [30.3, -1.8], [29.95, -1.8], [29.95, -2.1]]]},
                     "children": [
                         {
                             "name": "Gasabo",
+                            "centroid": [30.15, -1.925],
+                            "boundary": {"type": "Polygon", "coordinates": [[[30.05, -2.0], [30.25, -2.0], [30.25, -1.85], [30.05, -1.85], [30.05, -2.0]]]},
                             "children": [
                                 {
                                     "name": "Gisozi"
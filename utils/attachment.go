@@ -2,6 +2,7 @@ package utils
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -19,29 +20,65 @@ func init() {
 }
 
 // Attachment is a media attachment on a message in the format <content-type>:<url>. Content type may be a full
-// media type or may omit the subtype when it is unknown.
+// media type or may omit the subtype when it is unknown. It may also have size (in bytes) and duration (in seconds)
+// parameters when they are known.
 //
 // Examples:
 //   - image/jpeg:http://s3.amazon.com/bucket/test.jpg
 //   - image:http://s3.amazon.com/bucket/test.jpg
+//   - audio/mp4;size=23041;duration=12:http://s3.amazon.com/bucket/test.m4a
 type Attachment string
 
 // UnavailableType is the pseudo content type we use for attachments that couldn't be fetched
 const UnavailableType = "unavailable"
 
 // we allow outgoing attachments to have types like "image"
-var contentTypeRegex = regexp.MustCompile(`^(image|audio|video|application|geo|unavailable|(\w+/[-+.\w]+))$`)
+var contentTypeRegex = regexp.MustCompile(`^(image|audio|video|application|geo|unavailable|(\w+/[-+.\w]+))((;[-\w]+=[-.\w]+)*)$`)
 
-// ToParts splits an attachment string into content-type and URL
+// ToParts splits an attachment string into content-type (without parameters) and URL
 func (a Attachment) ToParts() (string, string) {
+	contentType, _, url := a.split()
+	return contentType, url
+}
+
+// splits an attachment string into content-type, parameters and URL
+func (a Attachment) split() (string, string, string) {
 	offset := strings.Index(string(a), ":")
 	if offset >= 0 {
 		t, u := strings.ToLower(string(a[:offset])), string(a[offset+1:])
-		if contentTypeRegex.MatchString(t) {
-			return t, u
+		if m := contentTypeRegex.FindStringSubmatch(t); m != nil {
+			return m[1], m[3], u
 		}
 	}
-	return "", string(a)
+	return "", "", string(a)
+}
+
+// Size returns the size in bytes of this attachment or zero if that isn't known
+func (a Attachment) Size() int {
+	return a.intParam("size")
+}
+
+// Duration returns the duration in seconds of this audio or video attachment or zero if that isn't known
+func (a Attachment) Duration() int {
+	return a.intParam("duration")
+}
+
+func (a Attachment) intParam(name string) int {
+	_, params, _ := a.split()
+
+	for _, p := range strings.Split(params, ";") {
+		if k, v, found := strings.Cut(p, "="); found && k == name {
+			i, _ := strconv.Atoi(v)
+			return max(i, 0)
+		}
+	}
+	return 0
+}
+
+// MediaType returns the top-level media type of this attachment, e.g. image
+func (a Attachment) MediaType() string {
+	mediaType, _, _ := strings.Cut(a.ContentType(), "/")
+	return mediaType
 }
 
 // ContentType returns the MIME type of this attachment
@@ -13,6 +13,18 @@ func TestAttachment(t *testing.T) {
 
 	assert.Equal(t, "image/jpeg", attachment.ContentType())
 	assert.Equal(t, "https://example.com/test.jpg", attachment.URL())
+	assert.Equal(t, "image", attachment.MediaType())
+	assert.Equal(t, 0, attachment.Size())
+	assert.Equal(t, 0, attachment.Duration())
+
+	attachment = utils.Attachment("audio/mp4;size=23041;duration=12:https://example.com/test.m4a")
+
+	assert.Equal(t, "audio/mp4", attachment.ContentType())
+	assert.Equal(t, "https://example.com/test.m4a", attachment.URL())
+	assert.Equal(t, "audio", attachment.MediaType())
+	assert.Equal(t, 23041, attachment.Size())
+	assert.Equal(t, 12, attachment.Duration())
+	assert.Equal(t, 0, utils.Attachment("audio;duration=-5:https://example.com/test.m4a").Duration())
 
 	assertParse := func(a string, expectedType, expectedURL string, isValid bool) {
 		actualType, actualURL := utils.Attachment(a).ToParts()
@@ -32,6 +44,10 @@ func TestAttachment(t *testing.T) {
 
 	assertParse("unavailable:http://bad.link", "unavailable", "http://bad.link", true)
 
+	assertParse("audio/mp4;size=23041;duration=12:http://test.m4a", "audio/mp4", "http://test.m4a", true)
+	assertParse("image;size=1024:http://test.jpg", "image", "http://test.jpg", true)
+	assertParse("image/jpeg;size:http://test.jpg", "", "image/jpeg;size:http://test.jpg", false)
+
 	// be lenient with invalid attachments
 	assertParse("", "", "", false)
 	assertParse("foo", "", "foo", false)
@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+// mean radius of the earth in kilometers
+const earthRadiusKm = 6371.0
+
+// geo: prefixed coordinates can be integers, but bare coordinates need decimals so that we don't treat things like
+// "3,5" as locations
+var geoURIRegex = regexp.MustCompile(`(?i)geo:\s*(-?\d{1,3}(?:\.\d+)?)\s*,\s*(-?\d{1,3}(?:\.\d+)?)`)
+var geoPairRegex = regexp.MustCompile(`(-?\d{1,3}\.\d+)\s*,\s*(-?\d{1,3}\.\d+)`)
+
+// GeoPoint is a point on the earth's surface
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+// NewGeoPoint creates a new point, returning false if the given latitude or longitude aren't valid
+func NewGeoPoint(lat, lng float64) (GeoPoint, bool) {
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return GeoPoint{}, false
+	}
+	return GeoPoint{Lat: lat, Lng: lng}, true
+}
+
+// ParseGeoPoint finds the first point in the given text, either as a geo:<lat>,<long> URI or a <lat>,<long> pair,
+// e.g. the URL of a location attachment
+func ParseGeoPoint(s string) (GeoPoint, bool) {
+	for _, re := range []*regexp.Regexp{geoURIRegex, geoPairRegex} {
+		for _, match := range re.FindAllStringSubmatch(s, -1) {
+			lat, _ := strconv.ParseFloat(match[1], 64)
+			lng, _ := strconv.ParseFloat(match[2], 64)
+
+			if p, valid := NewGeoPoint(lat, lng); valid {
+				return p, true
+			}
+		}
+	}
+	return GeoPoint{}, false
+}
+
+// DistanceTo returns the great-circle distance in kilometers between this point and the given point
+func (p GeoPoint) DistanceTo(other GeoPoint) float64 {
+	lat1, lat2 := p.Lat*math.Pi/180, other.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (other.Lng - p.Lng) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+func (p GeoPoint) String() string {
+	return fmt.Sprintf("%s,%s", strconv.FormatFloat(p.Lat, 'f', -1, 64), strconv.FormatFloat(p.Lng, 'f', -1, 64))
+}
+
+// GeoBBox is a bounding box described by its south-west and north-east corners
+type GeoBBox struct {
+	Min GeoPoint
+	Max GeoPoint
+}
+
+// Contains returns whether the given point is inside this bounding box
+func (b *GeoBBox) Contains(pt GeoPoint) bool {
+	return pt.Lat >= b.Min.Lat && pt.Lat <= b.Max.Lat && pt.Lng >= b.Min.Lng && pt.Lng <= b.Max.Lng
+}
+
+// GeoPolygon is a polygon whose first ring is its outer boundary and any other rings are holes
+type GeoPolygon [][]GeoPoint
+
+// Contains returns whether the given point is inside this polygon
+func (p GeoPolygon) Contains(pt GeoPoint) bool {
+	if len(p) == 0 || !ringContains(p[0], pt) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if ringContains(hole, pt) {
+			return false
+		}
+	}
+	return true
+}
+
+// uses ray casting to check whether a point is inside a ring
+func ringContains(ring []GeoPoint, pt GeoPoint) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > pt.Lat) != (b.Lat > pt.Lat) && pt.Lng < (b.Lng-a.Lng)*(pt.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// GeoBoundary is the boundary of an area made up of one or more polygons. It's read and written as a GeoJSON
+// Polygon or MultiPolygon geometry.
+type GeoBoundary struct {
+	polygons []GeoPolygon
+}
+
+// NewGeoBoundary creates a new boundary from the given polygons
+func NewGeoBoundary(polygons ...GeoPolygon) *GeoBoundary {
+	return &GeoBoundary{polygons: polygons}
+}
+
+// Polygons returns the polygons of this boundary
+func (b *GeoBoundary) Polygons() []GeoPolygon { return b.polygons }
+
+// BBox returns the bounding box of this boundary
+func (b *GeoBoundary) BBox() *GeoBBox {
+	var bbox *GeoBBox
+	for _, p := range b.polygons {
+		if len(p) == 0 {
+			continue
+		}
+		for _, pt := range p[0] {
+			if bbox == nil {
+				bbox = &GeoBBox{Min: pt, Max: pt}
+				continue
+			}
+			bbox.Min.Lat, bbox.Min.Lng = math.Min(bbox.Min.Lat, pt.Lat), math.Min(bbox.Min.Lng, pt.Lng)
+			bbox.Max.Lat, bbox.Max.Lng = math.Max(bbox.Max.Lat, pt.Lat), math.Max(bbox.Max.Lng, pt.Lng)
+		}
+	}
+	return bbox
+}
+
+// Contains returns whether the given point is inside this boundary
+func (b *GeoBoundary) Contains(pt GeoPoint) bool {
+	for _, p := range b.polygons {
+		if p.Contains(pt) {
+			return true
+		}
+	}
+	return false
+}
+
+//------------------------------------------------------------------------------------------
+// JSON Encoding / Decoding
+//------------------------------------------------------------------------------------------
+
+// UnmarshalJSON unmarshals a point from a GeoJSON position, i.e. [longitude, latitude]
+func (p *GeoPoint) UnmarshalJSON(data []byte) error {
+	var pos [2]float64
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return err
+	}
+
+	pt, valid := NewGeoPoint(pos[1], pos[0])
+	if !valid {
+		return fmt.Errorf("invalid position [%v, %v]", pos[0], pos[1])
+	}
+	*p = pt
+	return nil
+}
+
+// MarshalJSON marshals this point to a GeoJSON position, i.e. [longitude, latitude]
+func (p GeoPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]float64{p.Lng, p.Lat})
+}
+
+// UnmarshalJSON unmarshals a bounding box from a GeoJSON bbox, i.e. [west, south, east, north]
+func (b *GeoBBox) UnmarshalJSON(data []byte) error {
+	var box [4]float64
+	if err := json.Unmarshal(data, &box); err != nil {
+		return err
+	}
+
+	min, minValid := NewGeoPoint(box[1], box[0])
+	max, maxValid := NewGeoPoint(box[3], box[2])
+	if !minValid || !maxValid || min.Lat > max.Lat || min.Lng > max.Lng {
+		return fmt.Errorf("invalid bounding box [%v, %v, %v, %v]", box[0], box[1], box[2], box[3])
+	}
+	b.Min, b.Max = min, max
+	return nil
+}
+
+// MarshalJSON marshals this bounding box to a GeoJSON bbox, i.e. [west, south, east, north]
+func (b *GeoBBox) MarshalJSON() ([]byte, error) {
+	return json.Marshal([4]float64{b.Min.Lng, b.Min.Lat, b.Max.Lng, b.Max.Lat})
+}
+
+type geoJSONEnvelope struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// GeoJSON positions are [longitude, latitude]
+type geoJSONPolygon [][][2]float64
+
+func (p geoJSONPolygon) toPolygon() (GeoPolygon, error) {
+	polygon := make(GeoPolygon, len(p))
+	for i, ring := range p {
+		if len(ring) < 3 {
+			return nil, fmt.Errorf("polygon rings must have at least 3 positions")
+		}
+
+		polygon[i] = make([]GeoPoint, len(ring))
+		for j, pos := range ring {
+			pt, valid := NewGeoPoint(pos[1], pos[0])
+			if !valid {
+				return nil, fmt.Errorf("invalid position [%v, %v]", pos[0], pos[1])
+			}
+			polygon[i][j] = pt
+		}
+	}
+	return polygon, nil
+}
+
+func fromPolygon(polygon GeoPolygon) geoJSONPolygon {
+	p := make(geoJSONPolygon, len(polygon))
+	for i, ring := range polygon {
+		p[i] = make([][2]float64, len(ring))
+		for j, pt := range ring {
+			p[i][j] = [2]float64{pt.Lng, pt.Lat}
+		}
+	}
+	return p
+}
+
+// UnmarshalJSON unmarshals a boundary from a GeoJSON geometry
+func (b *GeoBoundary) UnmarshalJSON(data []byte) error {
+	var e geoJSONEnvelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+
+	var polygons []geoJSONPolygon
+
+	switch e.Type {
+	case "Polygon":
+		var p geoJSONPolygon
+		if err := json.Unmarshal(e.Coordinates, &p); err != nil {
+			return err
+		}
+		polygons = []geoJSONPolygon{p}
+	case "MultiPolygon":
+		if err := json.Unmarshal(e.Coordinates, &polygons); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported geometry type '%s'", e.Type)
+	}
+
+	b.polygons = make([]GeoPolygon, len(polygons))
+	for i, p := range polygons {
+		polygon, err := p.toPolygon()
+		if err != nil {
+			return err
+		}
+		b.polygons[i] = polygon
+	}
+	return nil
+}
+
+// MarshalJSON marshals this boundary to a GeoJSON geometry
+func (b *GeoBoundary) MarshalJSON() ([]byte, error) {
+	if len(b.polygons) == 1 {
+		return json.Marshal(map[string]any{"type": "Polygon", "coordinates": fromPolygon(b.polygons[0])})
+	}
+
+	polygons := make([]geoJSONPolygon, len(b.polygons))
+	for i, p := range b.polygons {
+		polygons[i] = fromPolygon(p)
+	}
+	return json.Marshal(map[string]any{"type": "MultiPolygon", "coordinates": polygons})
+}
@@ -0,0 +1,109 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nyaruka/goflow/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoPoint(t *testing.T) {
+	_, valid := utils.NewGeoPoint(91, 0)
+	assert.False(t, valid)
+	_, valid = utils.NewGeoPoint(0, -181)
+	assert.False(t, valid)
+
+	tcs := []struct {
+		text  string
+		point utils.GeoPoint
+		found bool
+	}{
+		{"geo:-1.9441,30.0619", utils.GeoPoint{Lat: -1.9441, Lng: 30.0619}, true},
+		{"GEO: -1, 30", utils.GeoPoint{Lat: -1, Lng: 30}, true},
+		{"-2.90875,-79.0117686", utils.GeoPoint{Lat: -2.90875, Lng: -79.0117686}, true},
+		{"I'm at 40.7128, -74.0060 now", utils.GeoPoint{Lat: 40.7128, Lng: -74.006}, true},
+		{"123.4,10.5 then 12.5,10.5", utils.GeoPoint{Lat: 12.5, Lng: 10.5}, true}, // first pair has invalid latitude
+		{"3,5", utils.GeoPoint{}, false},
+		{"nowhere", utils.GeoPoint{}, false},
+	}
+
+	for _, tc := range tcs {
+		point, found := utils.ParseGeoPoint(tc.text)
+		assert.Equal(t, tc.found, found, "found mismatch for '%s'", tc.text)
+		assert.Equal(t, tc.point, point, "point mismatch for '%s'", tc.text)
+	}
+
+	kigali := utils.GeoPoint{Lat: -1.9441, Lng: 30.0619}
+	nairobi := utils.GeoPoint{Lat: -1.2921, Lng: 36.8219}
+
+	assert.Equal(t, "-1.9441,30.0619", kigali.String())
+	assert.InDelta(t, 0.0, kigali.DistanceTo(kigali), 0.0001)
+	assert.InDelta(t, 755.5, kigali.DistanceTo(nairobi), 1.0)
+	assert.InDelta(t, kigali.DistanceTo(nairobi), nairobi.DistanceTo(kigali), 0.0001)
+
+	marshaled, err := json.Marshal(kigali)
+	assert.NoError(t, err)
+	assert.Equal(t, `[30.0619,-1.9441]`, string(marshaled))
+
+	var point utils.GeoPoint
+	require.NoError(t, json.Unmarshal([]byte(`[36.8219,-1.2921]`), &point))
+	assert.Equal(t, nairobi, point)
+
+	assert.EqualError(t, json.Unmarshal([]byte(`[0,95]`), &point), "invalid position [0, 95]")
+}
+
+func TestGeoBBox(t *testing.T) {
+	bbox := &utils.GeoBBox{}
+	require.NoError(t, json.Unmarshal([]byte(`[30.05,-2.0,30.25,-1.85]`), bbox))
+	assert.Equal(t, utils.GeoPoint{Lat: -2.0, Lng: 30.05}, bbox.Min)
+	assert.Equal(t, utils.GeoPoint{Lat: -1.85, Lng: 30.25}, bbox.Max)
+
+	assert.True(t, bbox.Contains(utils.GeoPoint{Lat: -1.93, Lng: 30.1}))
+	assert.True(t, bbox.Contains(utils.GeoPoint{Lat: -2.0, Lng: 30.05})) // on the edge
+	assert.False(t, bbox.Contains(utils.GeoPoint{Lat: -1.95, Lng: 30.04}))
+
+	marshaled, err := json.Marshal(bbox)
+	assert.NoError(t, err)
+	assert.Equal(t, `[30.05,-2,30.25,-1.85]`, string(marshaled))
+
+	assert.EqualError(t, json.Unmarshal([]byte(`[30.25,-2.0,30.05,-1.85]`), bbox), "invalid bounding box [30.25, -2, 30.05, -1.85]")
+	assert.EqualError(t, json.Unmarshal([]byte(`[30.05,-100,30.25,-1.85]`), bbox), "invalid bounding box [30.05, -100, 30.25, -1.85]")
+}
+
+func TestGeoBoundary(t *testing.T) {
+	// a square with a square hole in the middle
+	polygonJSON := `{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]],[[4,4],[6,4],[6,6],[4,6],[4,4]]]}`
+
+	b := &utils.GeoBoundary{}
+	require.NoError(t, json.Unmarshal([]byte(polygonJSON), b))
+	assert.Len(t, b.Polygons(), 1)
+	assert.Equal(t, &utils.GeoBBox{Min: utils.GeoPoint{Lat: 0, Lng: 0}, Max: utils.GeoPoint{Lat: 10, Lng: 10}}, b.BBox())
+
+	assert.True(t, b.Contains(utils.GeoPoint{Lat: 2, Lng: 2}))
+	assert.True(t, b.Contains(utils.GeoPoint{Lat: 8, Lng: 3}))
+	assert.False(t, b.Contains(utils.GeoPoint{Lat: 5, Lng: 5})) // in the hole
+	assert.False(t, b.Contains(utils.GeoPoint{Lat: 12, Lng: 2}))
+
+	marshaled, err := json.Marshal(b)
+	assert.NoError(t, err)
+	assert.JSONEq(t, polygonJSON, string(marshaled))
+
+	multiJSON := `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]],[[[20,20],[21,20],[21,21],[20,20]]]]}`
+
+	require.NoError(t, json.Unmarshal([]byte(multiJSON), b))
+	assert.Len(t, b.Polygons(), 2)
+	assert.Equal(t, &utils.GeoBBox{Min: utils.GeoPoint{Lat: 0, Lng: 0}, Max: utils.GeoPoint{Lat: 21, Lng: 21}}, b.BBox())
+	assert.True(t, b.Contains(utils.GeoPoint{Lat: 20.2, Lng: 20.8}))
+	assert.False(t, b.Contains(utils.GeoPoint{Lat: 10, Lng: 10}))
+
+	marshaled, err = json.Marshal(b)
+	assert.NoError(t, err)
+	assert.JSONEq(t, multiJSON, string(marshaled))
+
+	assert.EqualError(t, json.Unmarshal([]byte(`{"type":"Point","coordinates":[1,2]}`), b), "unsupported geometry type 'Point'")
+	assert.EqualError(t, json.Unmarshal([]byte(`{"type":"Polygon","coordinates":[[[0,0],[1,1]]]}`), b), "polygon rings must have at least 3 positions")
+	assert.EqualError(t, json.Unmarshal([]byte(`{"type":"Polygon","coordinates":[[[0,0],[1,100],[1,0]]]}`), b), "invalid position [1, 100]")
+}
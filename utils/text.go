@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 
@@ -63,6 +64,34 @@ func PrefixOverlap(s1, s2 string) int {
 	return i
 }
 
+// EditDistance returns the Levenshtein distance between s1 and s2, i.e. the minimum number of single character
+// insertions, deletions or substitutions needed to turn one into the other
+func EditDistance(s1, s2 string) int {
+	r1 := []rune(s1)
+	r2 := []rune(s2)
+
+	// only need to keep the previous row of the distance matrix
+	prev := make([]int, len(r2)+1)
+	curr := make([]int, len(r2)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := range r1 {
+		curr[0] = i + 1
+		for j := range r2 {
+			cost := 1
+			if r1[i] == r2[j] {
+				cost = 0
+			}
+			curr[j+1] = min(prev[j+1]+1, curr[j]+1, prev[j]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(r2)]
+}
+
 // StringSlices returns the slices of s defined by pairs of indexes in indices
 func StringSlices(s string, indices []int) []string {
 	slices := make([]string, 0, len(indices)/2)
@@ -82,6 +111,17 @@ func StringSliceContains(slice []string, str string, caseSensitive bool) bool {
 	return false
 }
 
+// ParseBoolean parses a boolean from text such as "true", "No" or "1"
+func ParseBoolean(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "yes", "y", "1":
+		return true, nil
+	case "false", "no", "n", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("'%s' is not a valid boolean", s)
+}
+
 // Indent indents each non-empty line in the given string
 func Indent(s string, prefix string) string {
 	output := strings.Builder{}
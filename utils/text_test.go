@@ -128,6 +128,18 @@ func TestPrefixOverlap(t *testing.T) {
 	assert.Equal(t, 4, utils.PrefixOverlap("25078", "25073254252"))
 }
 
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, utils.EditDistance("", ""))
+	assert.Equal(t, 3, utils.EditDistance("abc", ""))
+	assert.Equal(t, 3, utils.EditDistance("", "abc"))
+	assert.Equal(t, 0, utils.EditDistance("hello", "hello"))
+	assert.Equal(t, 1, utils.EditDistance("hello", "helo"))
+	assert.Equal(t, 1, utils.EditDistance("hello", "hallo"))
+	assert.Equal(t, 2, utils.EditDistance("hello", "ehllo"))
+	assert.Equal(t, 3, utils.EditDistance("kitten", "sitting"))
+	assert.Equal(t, 1, utils.EditDistance("😄😟👨🏼", "😄😟👨"))
+}
+
 func TestStringSlices(t *testing.T) {
 	assert.Equal(t, []string{"he", "hello", "world"}, utils.StringSlices("hello world", []int{0, 2, 0, 5, 6, 11}))
 }
@@ -141,6 +153,34 @@ func TestStringSliceContains(t *testing.T) {
 	assert.True(t, utils.StringSliceContains([]string{"b", "a", "c"}, "A", false))
 }
 
+func TestParseBoolean(t *testing.T) {
+	tcs := []struct {
+		input    string
+		expected bool
+		err      string
+	}{
+		{"true", true, ""},
+		{" YES ", true, ""},
+		{"y", true, ""},
+		{"1", true, ""},
+		{"False", false, ""},
+		{"no", false, ""},
+		{"0", false, ""},
+		{"", false, "'' is not a valid boolean"},
+		{"maybe", false, "'maybe' is not a valid boolean"},
+	}
+
+	for _, tc := range tcs {
+		actual, err := utils.ParseBoolean(tc.input)
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err, "error mismatch for input '%s'", tc.input)
+		} else {
+			assert.NoError(t, err, "unexpected error for input '%s'", tc.input)
+			assert.Equal(t, tc.expected, actual, "result mismatch for input '%s'", tc.input)
+		}
+	}
+}
+
 func TestIndent(t *testing.T) {
 	assert.Equal(t, "", utils.Indent("", "  "))
 	assert.Equal(t, "  x", utils.Indent("x", "  "))